@@ -13,79 +13,64 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
 	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
-	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
-	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	queuenats "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
+	queueredis "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
 	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/wiring"
 )
 
-type loggerAdapter struct {
-	inner slog.Logger
-}
-
-func (l *loggerAdapter) Debug(msg string, args ...any) { l.inner.Debug(msg, args...) }
-func (l *loggerAdapter) Info(msg string, args ...any)  { l.inner.Info(msg, args...) }
-func (l *loggerAdapter) Warn(msg string, args ...any)  { l.inner.Warn(msg, args...) }
-func (l *loggerAdapter) Error(msg string, args ...any) { l.inner.Error(msg, args...) }
-func (l *loggerAdapter) With(args ...any) proxyhttp.Logger {
-	return &loggerAdapter{inner: l.inner.With(args...)}
-}
-
-type getProxiesAdapter struct {
-	uc *proxy.GetProxiesUseCase
-}
-
-func (a *getProxiesAdapter) Execute(ctx context.Context, input proxyhttp.GetProxiesInput) (proxyhttp.GetProxiesOutput, error) {
-	out, err := a.uc.Execute(ctx, proxy.GetProxiesInput{
-		Cursor:     input.Cursor,
-		Limit:      input.Limit,
-		Protocol:   input.Protocol,
-		Anonymity:  input.Anonymity,
-		MaxLatency: input.MaxLatency,
-	})
-	if err != nil {
-		return proxyhttp.GetProxiesOutput{}, err
-	}
-	return proxyhttp.GetProxiesOutput{
-		Proxies:    out.Proxies,
-		NextCursor: out.NextCursor,
-		Total:      out.Total,
-	}, nil
-}
-
-type getRandomProxyAdapter struct {
-	uc *proxy.GetRandomProxyUseCase
-}
-
-func (a *getRandomProxyAdapter) Execute(ctx context.Context, input proxyhttp.GetRandomProxyInput) (*proxy.Proxy, error) {
-	return a.uc.Execute(ctx, proxy.GetRandomProxyInput{
-		Protocol:   input.Protocol,
-		Anonymity:  input.Anonymity,
-		MaxLatency: input.MaxLatency,
-	})
-}
-
 type Config struct {
-	APIPort   string
-	RedisAddr string
-	RedisPass string
-	RedisDB   int
-	ProxyTTL  time.Duration
-	KeyPrefix string
+	APIPort     string
+	RedisAddr   string
+	RedisPass   string
+	RedisDB     int
+	ProxyTTL    time.Duration
+	SessionTTL  time.Duration
+	KeyPrefix   string
+	VerifyTopic string
+
+	QueueBackend string
+	NATSURL      string
+
+	CredentialsKey string
+	AuthEnabled    bool
+	AdminKey       string
+
+	ServiceName   string
+	TraceExporter string
 }
 
 func loadConfig() Config {
 	_ = godotenv.Load()
 
 	return Config{
-		APIPort:   getEnv("API_PORT", "8080"),
-		RedisAddr: getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPass: getEnv("REDIS_PASSWORD", ""),
-		RedisDB:   getEnvInt("REDIS_DB", 0),
-		ProxyTTL:  time.Duration(getEnvInt("PROXY_TTL_MINUTES", 30)) * time.Minute,
-		KeyPrefix: getEnv("REDIS_KEY_PREFIX", "v1"),
+		APIPort:     getEnv("API_PORT", "8080"),
+		RedisAddr:   getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPass:   getEnv("REDIS_PASSWORD", ""),
+		RedisDB:     getEnvInt("REDIS_DB", 0),
+		ProxyTTL:    time.Duration(getEnvInt("PROXY_TTL_MINUTES", 30)) * time.Minute,
+		SessionTTL:  time.Duration(getEnvInt("SESSION_TTL_MINUTES", 10)) * time.Minute,
+		KeyPrefix:   getEnv("REDIS_KEY_PREFIX", "v1"),
+		VerifyTopic: getEnv("REDIS_TOPIC_VERIFY", "proxies:verify"),
+
+		QueueBackend: getEnv("QUEUE_BACKEND", queue.BackendRedis),
+		NATSURL:      getEnv("NATS_URL", nats.DefaultURL),
+
+		CredentialsKey: getEnv("CREDENTIALS_KEY", ""),
+		AuthEnabled:    getEnv("API_AUTH_ENABLED", "true") != "false",
+		AdminKey:       getEnv("API_ADMIN_KEY", ""),
+
+		ServiceName:   getEnv("OTEL_SERVICE_NAME", "proxy-engine-api"),
+		TraceExporter: getEnv("TRACING_EXPORTER", tracing.ExporterNone),
 	}
 }
 
@@ -109,9 +94,28 @@ func main() {
 	cfg := loadConfig()
 
 	innerLogger := slog.NewJSON(logslog.LevelInfo)
-	logger := &loggerAdapter{inner: innerLogger}
+	logger := adapters.NewLogger(innerLogger)
 	innerLogger.Info("starting proxy-engine API", "port", cfg.APIPort)
 
+	if cfg.AuthEnabled && cfg.AdminKey == "" {
+		innerLogger.Error("API_ADMIN_KEY is required unless API_AUTH_ENABLED=false")
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.ServiceName,
+		Exporter:    cfg.TraceExporter,
+	})
+	if err != nil {
+		innerLogger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = shutdownTracing(shutdownCtx)
+	}()
+
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.RedisAddr,
 		Password: cfg.RedisPass,
@@ -130,15 +134,49 @@ func main() {
 
 	repo := proxyredis.NewRepository(redisClient, cfg.KeyPrefix).WithTTL(cfg.ProxyTTL)
 
-	getProxiesUC := proxy.NewGetProxiesUseCase(repo, innerLogger)
-	getRandomUC := proxy.NewGetRandomProxyUseCase(repo, innerLogger)
+	if cfg.CredentialsKey != "" {
+		cipher, err := crypt.NewAESGCMFromBase64(cfg.CredentialsKey)
+		if err != nil {
+			innerLogger.Error("invalid credentials key", "error", err)
+			os.Exit(1)
+		}
+		repo.WithCipher(cipher)
+	}
+
+	// the dead letter endpoints read the broker the workers dead-letter into
+	var q queue.Queue
+	switch cfg.QueueBackend {
+	case queue.BackendRedis:
+		q = queueredis.NewStreamsClient(redisClient)
+	case queue.BackendNATS:
+		conn, err := nats.Connect(cfg.NATSURL)
+		if err != nil {
+			innerLogger.Error("failed to connect to nats", "error", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
 
-	handler := proxyhttp.NewHandler(
-		&getProxiesAdapter{uc: getProxiesUC},
-		&getRandomProxyAdapter{uc: getRandomUC},
-		logger,
-	)
-	router := proxyhttp.NewRouter(handler, logger)
+		q, err = queuenats.NewJetStreamClient(conn)
+		if err != nil {
+			innerLogger.Error("failed to open jetstream", "error", err)
+			os.Exit(1)
+		}
+	default:
+		innerLogger.Error("unsupported queue backend", "backend", cfg.QueueBackend)
+		os.Exit(1)
+	}
+
+	router, err := wiring.NewAPI(ctx, wiring.APIConfig{
+		Topic:       cfg.VerifyTopic,
+		SessionTTL:  cfg.SessionTTL,
+		KeyPrefix:   cfg.KeyPrefix,
+		AuthEnabled: cfg.AuthEnabled,
+		AdminKey:    cfg.AdminKey,
+	}, repo, q, redisClient, metrics.NewRegistry(), innerLogger)
+	if err != nil {
+		innerLogger.Error("failed to set up api", "error", err)
+		os.Exit(1)
+	}
 
 	server := &http.Server{
 		Addr:         ":" + cfg.APIPort,
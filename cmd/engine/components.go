@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	"github.com/JulianoL13/app-proxy-engine/internal/wiring"
+)
+
+func newScheduler(ctx context.Context, cfg Config, b *backends, registry *metrics.Registry, logger slog.Logger) (func(context.Context) error, error) {
+	return wiring.NewScheduler(ctx, wiring.SchedulerConfig{
+		Topic:            cfg.Topic,
+		ScrapeInterval:   cfg.ScrapeInterval,
+		SourceTimeout:    cfg.SourceTimeout,
+		SourcesFile:      cfg.SourcesFile,
+		SourcesReload:    cfg.SourcesReload,
+		RecheckInterval:  cfg.RecheckInterval,
+		RecheckMaxAge:    cfg.RecheckMaxAge,
+		RecheckBatchSize: cfg.RecheckBatchSize,
+		Quarantine: scraper.QuarantinePolicy{
+			Strikes:     cfg.QuarantineStrikes,
+			BaseBackoff: cfg.QuarantineBase,
+			MaxBackoff:  cfg.QuarantineMax,
+		},
+	}, b.queue, b.store, b.cleaner, b.cipher, registry, logger)
+}
+
+func newWorker(ctx context.Context, cfg Config, b *backends, registry *metrics.Registry, logger slog.Logger) (func(context.Context) error, func(), error) {
+	uc, closeWorker, err := wiring.NewWorker(ctx, wiring.WorkerConfig{
+		ConsumerName: cfg.ConsumerName,
+		Topic:        cfg.Topic,
+		Group:        cfg.Group,
+		Concurrency:  cfg.Concurrency,
+		MaxAttempts:  cfg.MaxAttempts,
+		GeoIPDB:      cfg.GeoIPDB,
+
+		VerifyTimeout:       cfg.VerifyTimeout,
+		JudgeURLs:           cfg.JudgeURLs,
+		JudgeKey:            cfg.JudgeKey,
+		JudgeStrategy:       cfg.JudgeStrategy,
+		JudgeQuorum:         cfg.JudgeQuorum,
+		JudgeHealthInterval: cfg.JudgeHealthInterval,
+		JudgeTLSPort:        cfg.JudgeTLSPort,
+		CapabilityProbe:     cfg.CapabilityProbe,
+		CapabilityPlainURL:  cfg.CapabilityPlainURL,
+		CapabilityTLSTarget: cfg.CapabilityTLSTarget,
+		MITMTarget:          cfg.MITMTarget,
+		MITMPins:            cfg.MITMPins,
+	}, b.queue, b.store, b.cipher, registry, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	return uc.Execute, closeWorker, nil
+}
+
+func newAPI(ctx context.Context, cfg Config, b *backends, registry *metrics.Registry, logger slog.Logger) (func(context.Context) error, error) {
+	router, err := wiring.NewAPI(ctx, wiring.APIConfig{
+		Topic:       cfg.Topic,
+		SessionTTL:  cfg.SessionTTL,
+		KeyPrefix:   cfg.KeyPrefix,
+		AuthEnabled: cfg.AuthEnabled,
+		AdminKey:    cfg.AdminKey,
+	}, b.store, b.queue, b.redis, registry, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:         ":" + cfg.APIPort,
+		Handler:      router,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	return func(ctx context.Context) error {
+		errCh := make(chan error, 1)
+		go func() {
+			logger.Info("listening", "addr", server.Addr)
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- err
+			}
+			close(errCh)
+		}()
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		return server.Shutdown(shutdownCtx)
+	}, nil
+}
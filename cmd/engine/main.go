@@ -0,0 +1,470 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	logslog "log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"go.etcd.io/bbolt"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/memory"
+	queuenats "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
+	queueredis "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
+	proxybolt "github.com/JulianoL13/app-proxy-engine/internal/proxy/bolt"
+	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	scraperredis "github.com/JulianoL13/app-proxy-engine/internal/scraper/redis"
+	httpverifier "github.com/JulianoL13/app-proxy-engine/internal/verifier/http"
+)
+
+const (
+	componentScheduler = "scheduler"
+	componentWorker    = "worker"
+	componentAPI       = "api"
+
+	storageRedis = "redis"
+	storageBolt  = "bolt"
+)
+
+type Config struct {
+	Components     map[string]bool
+	QueueBackend   string
+	StorageBackend string
+	StoragePath    string
+
+	RedisAddr string
+	RedisPass string
+	RedisDB   int
+	KeyPrefix string
+	NATSURL   string
+
+	Topic          string
+	Group          string
+	ConsumerName   string
+	ProxyTTL       time.Duration
+	CredentialsKey string
+
+	ScrapeInterval    time.Duration
+	SourceTimeout     time.Duration
+	SourcesFile       string
+	SourcesReload     time.Duration
+	RecheckInterval   time.Duration
+	RecheckMaxAge     time.Duration
+	RecheckBatchSize  int
+	QuarantineStrikes int
+	QuarantineBase    time.Duration
+	QuarantineMax     time.Duration
+
+	Concurrency         int
+	VerifyTimeout       time.Duration
+	JudgeURLs           string
+	JudgeKey            string
+	JudgeStrategy       string
+	JudgeQuorum         int
+	JudgeHealthInterval time.Duration
+	JudgeTLSPort        string
+	CapabilityProbe     bool
+	CapabilityPlainURL  string
+	CapabilityTLSTarget string
+	MaxAttempts         int
+	RetryDelay          time.Duration
+	ClaimIdle           time.Duration
+	ConsumerTTL         time.Duration
+	GeoIPDB             string
+	MITMTarget          string
+	MITMPins            string
+
+	APIPort     string
+	SessionTTL  time.Duration
+	AuthEnabled bool
+	AdminKey    string
+	MetricsPort string
+
+	ServiceName   string
+	TraceExporter string
+}
+
+func loadConfig() (Config, error) {
+	_ = godotenv.Load()
+
+	components, err := parseComponents(getEnv("ENGINE_COMPONENTS", "scheduler,worker,api"))
+	if err != nil {
+		return Config{}, err
+	}
+
+	queueBackend := getEnv("QUEUE_BACKEND", queue.BackendMemory)
+	storageBackend := getEnv("STORAGE_BACKEND", storageRedis)
+
+	// Authentication is on unless turned off explicitly; its key store lives
+	// in redis, so an API without a redis backend has to opt out.
+	usesRedis := queueBackend == queue.BackendRedis || storageBackend == storageRedis
+	adminKey := getEnv("API_ADMIN_KEY", "")
+	authEnabled := getEnv("API_AUTH_ENABLED", "true") != "false"
+
+	retryDelay := time.Duration(getEnvInt("RETRY_DELAY_SECONDS", 30)) * time.Second
+	claimIdle := time.Duration(getEnvInt("CLAIM_IDLE_SECONDS", 300)) * time.Second
+	if queueBackend == queue.BackendRedis && claimIdle > 0 && claimIdle <= retryDelay {
+		return Config{}, errors.New("CLAIM_IDLE_SECONDS must exceed RETRY_DELAY_SECONDS")
+	}
+
+	if components[componentAPI] && authEnabled {
+		if adminKey == "" {
+			return Config{}, errors.New("API_ADMIN_KEY is required unless API_AUTH_ENABLED=false")
+		}
+		if !usesRedis {
+			return Config{}, errors.New("api authentication needs a redis queue or storage backend for its key store; set API_AUTH_ENABLED=false to serve without it")
+		}
+	}
+
+	return Config{
+		Components:     components,
+		QueueBackend:   queueBackend,
+		StorageBackend: storageBackend,
+		StoragePath:    getEnv("STORAGE_PATH", "proxies.db"),
+
+		RedisAddr: getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPass: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:   getEnvInt("REDIS_DB", 0),
+		KeyPrefix: getEnv("REDIS_KEY_PREFIX", "v1"),
+		NATSURL:   getEnv("NATS_URL", nats.DefaultURL),
+
+		Topic:          getEnv("REDIS_TOPIC_VERIFY", "proxies:verify"),
+		Group:          getEnv("REDIS_GROUP_WORKERS", "verifiers"),
+		ConsumerName:   getEnv("CONSUMER_NAME", mustHostname()),
+		ProxyTTL:       time.Duration(getEnvInt("PROXY_TTL_MINUTES", 30)) * time.Minute,
+		CredentialsKey: getEnv("CREDENTIALS_KEY", ""),
+
+		ScrapeInterval:    time.Duration(getEnvInt("SCRAPE_INTERVAL_MINUTES", 30)) * time.Minute,
+		SourceTimeout:     time.Duration(getEnvInt("SOURCE_TIMEOUT_SECONDS", 45)) * time.Second,
+		SourcesFile:       getEnv("SCRAPER_SOURCES_FILE", ""),
+		SourcesReload:     time.Duration(getEnvInt("SCRAPER_SOURCES_RELOAD_SECONDS", 10)) * time.Second,
+		RecheckInterval:   time.Duration(getEnvInt("RECHECK_INTERVAL_MINUTES", 5)) * time.Minute,
+		RecheckMaxAge:     time.Duration(getEnvInt("RECHECK_MAX_AGE_MINUTES", 15)) * time.Minute,
+		RecheckBatchSize:  getEnvInt("RECHECK_BATCH_SIZE", 500),
+		QuarantineStrikes: getEnvInt("SOURCE_QUARANTINE_STRIKES", 3),
+		QuarantineBase:    time.Duration(getEnvInt("SOURCE_QUARANTINE_BASE_MINUTES", 30)) * time.Minute,
+		QuarantineMax:     time.Duration(getEnvInt("SOURCE_QUARANTINE_MAX_MINUTES", 1440)) * time.Minute,
+
+		Concurrency:         getEnvInt("WORKER_CONCURRENCY", 50),
+		VerifyTimeout:       time.Duration(getEnvInt("VERIFY_TIMEOUT_SECONDS", 10)) * time.Second,
+		JudgeURLs:           getEnv("JUDGE_URL", ""),
+		JudgeKey:            getEnv("JUDGE_KEY", ""),
+		JudgeStrategy:       getEnv("JUDGE_STRATEGY", "round-robin"),
+		JudgeQuorum:         getEnvInt("JUDGE_QUORUM", 0),
+		JudgeHealthInterval: time.Duration(getEnvInt("JUDGE_HEALTH_INTERVAL_SECONDS", 30)) * time.Second,
+		JudgeTLSPort:        getEnv("JUDGE_TLS_PORT", httpverifier.DefaultJudgeTLSPort),
+		CapabilityProbe:     getEnv("CAPABILITY_PROBE_ENABLED", "true") != "false",
+		CapabilityPlainURL:  getEnv("CAPABILITY_PLAIN_URL", ""),
+		CapabilityTLSTarget: getEnv("CAPABILITY_TLS_TARGET", ""),
+		MaxAttempts:         getEnvInt("MAX_DELIVERY_ATTEMPTS", 5),
+		RetryDelay:          retryDelay,
+		ClaimIdle:           claimIdle,
+		ConsumerTTL:         time.Duration(getEnvInt("CONSUMER_IDLE_TTL_MINUTES", 60)) * time.Minute,
+		GeoIPDB:             getEnv("GEOIP_DB", ""),
+		MITMTarget:          getEnv("MITM_TARGET", ""),
+		MITMPins:            getEnv("MITM_PINS", ""),
+
+		APIPort:     getEnv("API_PORT", "8080"),
+		SessionTTL:  time.Duration(getEnvInt("SESSION_TTL_MINUTES", 10)) * time.Minute,
+		AuthEnabled: authEnabled,
+		AdminKey:    adminKey,
+		MetricsPort: getEnv("METRICS_PORT", "9090"),
+
+		ServiceName:   getEnv("OTEL_SERVICE_NAME", "proxy-engine"),
+		TraceExporter: getEnv("TRACING_EXPORTER", tracing.ExporterNone),
+	}, nil
+}
+
+func parseComponents(s string) (map[string]bool, error) {
+	components := make(map[string]bool)
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+		switch c {
+		case componentScheduler, componentWorker, componentAPI:
+			components[c] = true
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown component %q", c)
+		}
+	}
+	if len(components) == 0 {
+		return nil, errors.New("no components enabled")
+	}
+	return components, nil
+}
+
+// backends holds what the components share: the queue between scheduler and
+// worker, and the storage every component reads or writes. redis is only set
+// when one of the backends lives there.
+type backends struct {
+	queue   queue.Queue
+	store   Store
+	cleaner scraper.Cleaner
+	cipher  storage.Cipher
+	redis   *redis.Client
+	closers []func() error
+}
+
+func (b *backends) Close() {
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		_ = b.closers[i]()
+	}
+}
+
+func (b *backends) redisClient(ctx context.Context, cfg Config) (*redis.Client, error) {
+	if b.redis != nil {
+		return b.redis, nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPass,
+		DB:       cfg.RedisDB,
+	})
+	b.closers = append(b.closers, client.Close)
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("connect to redis: %w", err)
+	}
+	b.redis = client
+	return client, nil
+}
+
+func openBackends(ctx context.Context, cfg Config) (*backends, error) {
+	b := &backends{}
+
+	switch cfg.QueueBackend {
+	case queue.BackendMemory:
+		b.queue = memory.NewQueue().WithRetryDelay(cfg.RetryDelay)
+	case queue.BackendRedis:
+		client, err := b.redisClient(ctx, cfg)
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		b.queue = queueredis.NewStreamsClient(client).
+			WithRetryDelay(cfg.RetryDelay).
+			WithClaimIdle(cfg.ClaimIdle).
+			WithConsumerTTL(cfg.ConsumerTTL)
+	case queue.BackendNATS:
+		conn, err := nats.Connect(cfg.NATSURL)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("connect to nats: %w", err)
+		}
+		b.closers = append(b.closers, func() error { conn.Close(); return nil })
+
+		js, err := queuenats.NewJetStreamClient(conn)
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		b.queue = js.WithRetryDelay(cfg.RetryDelay)
+	default:
+		return nil, fmt.Errorf("unsupported queue backend %q", cfg.QueueBackend)
+	}
+
+	var cipher *crypt.AESGCM
+	if cfg.CredentialsKey != "" {
+		var err error
+		cipher, err = crypt.NewAESGCMFromBase64(cfg.CredentialsKey)
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("invalid credentials key: %w", err)
+		}
+		b.cipher = cipher
+	}
+
+	switch cfg.StorageBackend {
+	case storageRedis:
+		client, err := b.redisClient(ctx, cfg)
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+
+		repo := proxyredis.NewRepository(client, cfg.KeyPrefix).WithTTL(cfg.ProxyTTL)
+		if cipher != nil {
+			repo.WithCipher(cipher)
+		}
+		b.store = repo
+		b.cleaner = scraperredis.NewCleaner(client, cfg.KeyPrefix)
+	case storageBolt:
+		db, err := bbolt.Open(cfg.StoragePath, 0o600, &bbolt.Options{Timeout: time.Second})
+		if err != nil {
+			b.Close()
+			return nil, fmt.Errorf("open storage %s: %w", cfg.StoragePath, err)
+		}
+		b.closers = append(b.closers, db.Close)
+
+		repo := proxybolt.NewRepository(db).WithTTL(cfg.ProxyTTL)
+		if cipher != nil {
+			repo.WithCipher(cipher)
+		}
+		b.store = repo
+		b.cleaner = repo
+	default:
+		b.Close()
+		return nil, fmt.Errorf("unsupported storage backend %q", cfg.StorageBackend)
+	}
+
+	return b, nil
+}
+
+type component struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func main() {
+	cfg, err := loadConfig()
+
+	logger := slog.NewJSON(logslog.LevelInfo)
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: cfg.ServiceName,
+		Exporter:    cfg.TraceExporter,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = shutdownTracing(shutdownCtx)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b, err := openBackends(ctx, cfg)
+	if err != nil {
+		logger.Error("failed to open backends", "error", err)
+		os.Exit(1)
+	}
+	defer b.Close()
+	logger.Info("starting proxy-engine", "components", strings.Join(enabled(cfg.Components), ","),
+		"queue", cfg.QueueBackend, "storage", cfg.StorageBackend)
+
+	registry := metrics.NewRegistry()
+
+	var components []component
+	if cfg.Components[componentScheduler] {
+		run, err := newScheduler(ctx, cfg, b, registry, logger)
+		if err != nil {
+			logger.Error("failed to set up scheduler", "error", err)
+			os.Exit(1)
+		}
+		components = append(components, component{name: componentScheduler, run: run})
+	}
+	if cfg.Components[componentWorker] {
+		run, closeWorker, err := newWorker(ctx, cfg, b, registry, logger)
+		if err != nil {
+			logger.Error("failed to set up worker", "error", err)
+			os.Exit(1)
+		}
+		defer closeWorker()
+		components = append(components, component{name: componentWorker, run: run})
+	}
+	if cfg.Components[componentAPI] {
+		run, err := newAPI(ctx, cfg, b, registry, logger)
+		if err != nil {
+			logger.Error("failed to set up api", "error", err)
+			os.Exit(1)
+		}
+		components = append(components, component{name: componentAPI, run: run})
+	} else {
+		metricsServer := registry.NewServer(":" + cfg.MetricsPort)
+		go func() {
+			logger.Info("metrics listening", "addr", metricsServer.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("metrics server error", "error", err)
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+		logger.Info("shutting down...")
+		cancel()
+	}()
+
+	var wg sync.WaitGroup
+	failed := false
+	var mu sync.Mutex
+	for _, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("component stopped", "component", c.name, "error", err)
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+			cancel()
+		}()
+	}
+	wg.Wait()
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func enabled(components map[string]bool) []string {
+	var names []string
+	for _, name := range []string{componentScheduler, componentWorker, componentAPI} {
+		if components[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func mustHostname() string {
+	h, _ := os.Hostname()
+	return h
+}
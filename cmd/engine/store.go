@@ -0,0 +1,10 @@
+package main
+
+import "github.com/JulianoL13/app-proxy-engine/internal/wiring"
+
+// Store is everything the three components need from proxy storage.
+type Store interface {
+	wiring.SchedulerStore
+	wiring.WorkerStore
+	wiring.APIStore
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	logslog "log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/gateway"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
+)
+
+type Config struct {
+	GatewayPort string
+	SOCKS5Port  string
+	Username    string
+	Password    string
+	RedisAddr   string
+	RedisPass   string
+	RedisDB     int
+	KeyPrefix   string
+	MaxAttempts int
+	DialTimeout time.Duration
+	Protocol    string
+	Anonymity   string
+	MaxLatency  time.Duration
+	// RequireConnect limits tunnelled requests to upstreams whose CONNECT
+	// tunnel passed the worker's capability probe.
+	RequireConnect bool
+
+	CredentialsKey string
+}
+
+func loadConfig() Config {
+	_ = godotenv.Load()
+
+	return Config{
+		GatewayPort: getEnv("GATEWAY_PORT", "8888"),
+		SOCKS5Port:  getEnv("GATEWAY_SOCKS5_PORT", "1080"),
+		Username:    getEnv("GATEWAY_USERNAME", getEnv("GATEWAY_SOCKS5_USERNAME", "")),
+		Password:    getEnv("GATEWAY_PASSWORD", getEnv("GATEWAY_SOCKS5_PASSWORD", "")),
+		RedisAddr:   getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPass:   getEnv("REDIS_PASSWORD", ""),
+		RedisDB:     getEnvInt("REDIS_DB", 0),
+		KeyPrefix:   getEnv("REDIS_KEY_PREFIX", "v1"),
+		MaxAttempts: getEnvInt("GATEWAY_MAX_ATTEMPTS", 3),
+		DialTimeout: time.Duration(getEnvInt("GATEWAY_DIAL_TIMEOUT_SECONDS", 10)) * time.Second,
+		Protocol:    getEnv("GATEWAY_PROTOCOL", ""),
+		Anonymity:   getEnv("GATEWAY_ANONYMITY", ""),
+		MaxLatency:  time.Duration(getEnvInt("GATEWAY_MAX_LATENCY_MS", 0)) * time.Millisecond,
+
+		RequireConnect: getEnv("GATEWAY_REQUIRE_CONNECT", "true") != "false",
+
+		CredentialsKey: getEnv("CREDENTIALS_KEY", ""),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if val := os.Getenv(key); val != "" {
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger := slog.NewJSON(logslog.LevelInfo)
+	logger.Info("starting proxy-engine gateway", "port", cfg.GatewayPort, "socks5_port", cfg.SOCKS5Port)
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPass,
+		DB:       cfg.RedisDB,
+	})
+	defer redisClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		logger.Error("failed to connect to redis", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("connected to redis", "addr", cfg.RedisAddr)
+
+	repo := proxyredis.NewRepository(redisClient, cfg.KeyPrefix)
+
+	if cfg.CredentialsKey != "" {
+		cipher, err := crypt.NewAESGCMFromBase64(cfg.CredentialsKey)
+		if err != nil {
+			logger.Error("invalid credentials key", "error", err)
+			os.Exit(1)
+		}
+		repo.WithCipher(cipher)
+	}
+	getRandomUC := proxy.NewGetRandomProxyUseCase(repo, logger)
+
+	dialer := gateway.NewUpstreamDialer(cfg.DialTimeout)
+	defaultFilter := proxy.FilterOptions{
+		Protocol:   cfg.Protocol,
+		Anonymity:  cfg.Anonymity,
+		MaxLatency: cfg.MaxLatency,
+	}
+	// every SOCKS5 request is a tunnel
+	socksFilter := defaultFilter
+	if cfg.RequireConnect {
+		socksFilter.Capability = string(proxy.CapabilityConnect)
+	}
+
+	if cfg.Username == "" {
+		logger.Warn("GATEWAY_USERNAME is not set, the gateway relays for any client")
+	}
+
+	gw := gateway.NewServer(getRandomUC, dialer, logger, cfg.MaxAttempts, cfg.DialTimeout).
+		WithDefaultFilter(defaultFilter).
+		WithRequireConnect(cfg.RequireConnect).
+		WithCredentials(cfg.Username, cfg.Password)
+
+	socksServer := gateway.NewSOCKS5Server(getRandomUC, dialer, logger, cfg.MaxAttempts).
+		WithDefaultFilter(socksFilter).
+		WithCredentials(cfg.Username, cfg.Password)
+
+	server := &http.Server{
+		Addr:              ":" + cfg.GatewayPort,
+		Handler:           gw,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	go func() {
+		logger.Info("listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
+
+	socksCtx, socksCancel := context.WithCancel(context.Background())
+	defer socksCancel()
+
+	socksListener, err := net.Listen("tcp", ":"+cfg.SOCKS5Port)
+	if err != nil {
+		logger.Error("failed to listen for socks5", "error", err)
+		os.Exit(1)
+	}
+
+	go func() {
+		logger.Info("socks5 listening", "addr", socksListener.Addr().String())
+		if err := socksServer.Serve(socksCtx, socksListener); err != nil {
+			log.Fatalf("socks5 server error: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down gateway...")
+	socksCancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("gateway shutdown error", "error", err)
+	}
+
+	fmt.Println("gateway stopped")
+}
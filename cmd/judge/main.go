@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	logslog "log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	judgehttp "github.com/JulianoL13/app-proxy-engine/internal/judge/http"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	port := getEnv("JUDGE_PORT", "8090")
+	key := getEnv("JUDGE_KEY", "")
+	tlsPort := getEnv("JUDGE_TLS_PORT", "8443")
+	tlsCert := getEnv("JUDGE_TLS_CERT", "")
+	tlsKey := getEnv("JUDGE_TLS_KEY", "")
+
+	logger := slog.NewJSON(logslog.LevelInfo)
+
+	if key == "" {
+		logger.Error("JUDGE_KEY is required")
+		os.Exit(1)
+	}
+
+	signer, err := judge.NewSignerFromBase64(key)
+	if err != nil {
+		logger.Error("invalid judge key", "error", err)
+		os.Exit(1)
+	}
+
+	router := judgehttp.NewRouter(judgehttp.NewHandler(signer, logger))
+	server := newServer(":"+port, router)
+
+	go func() {
+		logger.Info("judge listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("server error", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	var tlsServer *http.Server
+	if tlsCert != "" && tlsKey != "" {
+		pin, err := publicKeyPin(tlsCert, tlsKey)
+		if err != nil {
+			logger.Error("invalid tls certificate", "error", err)
+			os.Exit(1)
+		}
+
+		tlsServer = newServer(":"+tlsPort, router)
+		go func() {
+			logger.Info("judge tls listening", "addr", tlsServer.Addr, "pin", pin.String())
+			if err := tlsServer.ListenAndServeTLS(tlsCert, tlsKey); err != nil && err != http.ErrServerClosed {
+				logger.Error("tls server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("shutting down judge...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("server shutdown error", "error", err)
+	}
+	if tlsServer != nil {
+		if err := tlsServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("tls server shutdown error", "error", err)
+		}
+	}
+}
+
+func newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+}
+
+func publicKeyPin(certFile, keyFile string) (judge.Pin, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return judge.Pin{}, err
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return judge.Pin{}, err
+	}
+	return judge.PublicKeyPin(leaf), nil
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
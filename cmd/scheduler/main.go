@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	logslog "log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -11,43 +11,23 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 
-	"github.com/JulianoL13/app-proxy-engine/internal/common/events"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
 	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	queuenats "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
 	queueredis "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
+	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
 	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
-	httpclient "github.com/JulianoL13/app-proxy-engine/internal/scraper/http"
 	scraperredis "github.com/JulianoL13/app-proxy-engine/internal/scraper/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/wiring"
 )
 
-type scraperAdapter struct {
-	uc *scraper.ScrapeProxiesUseCase
-}
-
-func (a *scraperAdapter) Execute(ctx context.Context) ([]scraper.ScrapedProxy, []error) {
-	results, errs := a.uc.Execute(ctx)
-	proxies := make([]scraper.ScrapedProxy, len(results))
-	for i, r := range results {
-		proxies[i] = r
-	}
-	return proxies, errs
-}
-
-type proxySerializer struct{}
-
-func (s proxySerializer) Serialize(p scraper.ScrapedProxy) ([]byte, error) {
-	event := events.ProxyDiscoveredEvent{
-		IP:       p.IP(),
-		Port:     p.Port(),
-		Protocol: p.Protocol(),
-		Source:   p.Source(),
-		Username: p.Username(),
-		Password: p.Password(),
-	}
-	return json.Marshal(event)
-}
-
 func main() {
 	_ = godotenv.Load()
 
@@ -57,10 +37,41 @@ func main() {
 	redisKeyPrefix := getEnv("REDIS_KEY_PREFIX", "proxies")
 	scrapeInterval := time.Duration(getEnvInt("SCRAPE_INTERVAL_MINUTES", 30)) * time.Minute
 	redisTopic := getEnv("REDIS_TOPIC_VERIFY", "proxies:verify")
+	queueBackend := getEnv("QUEUE_BACKEND", queue.BackendRedis)
+	natsURL := getEnv("NATS_URL", nats.DefaultURL)
 	sourceTimeout := time.Duration(getEnvInt("SOURCE_TIMEOUT_SECONDS", 45)) * time.Second
+	proxyTTL := time.Duration(getEnvInt("PROXY_TTL_MINUTES", 30)) * time.Minute
+	recheckInterval := time.Duration(getEnvInt("RECHECK_INTERVAL_MINUTES", 5)) * time.Minute
+	recheckMaxAge := time.Duration(getEnvInt("RECHECK_MAX_AGE_MINUTES", 15)) * time.Minute
+	recheckBatchSize := getEnvInt("RECHECK_BATCH_SIZE", 500)
+	sourcesFile := getEnv("SCRAPER_SOURCES_FILE", "")
+	credentialsKey := getEnv("CREDENTIALS_KEY", "")
+	metricsPort := getEnv("METRICS_PORT", "9090")
+	traceExporter := getEnv("TRACING_EXPORTER", tracing.ExporterNone)
+	serviceName := getEnv("OTEL_SERVICE_NAME", "proxy-engine-scheduler")
+	sourcesReload := time.Duration(getEnvInt("SCRAPER_SOURCES_RELOAD_SECONDS", 10)) * time.Second
+	quarantinePolicy := scraper.QuarantinePolicy{
+		Strikes:     getEnvInt("SOURCE_QUARANTINE_STRIKES", 3),
+		BaseBackoff: time.Duration(getEnvInt("SOURCE_QUARANTINE_BASE_MINUTES", 30)) * time.Minute,
+		MaxBackoff:  time.Duration(getEnvInt("SOURCE_QUARANTINE_MAX_MINUTES", 1440)) * time.Minute,
+	}
 
 	logger := slog.NewJSON(logslog.LevelInfo)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: serviceName,
+		Exporter:    traceExporter,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = shutdownTracing(shutdownCtx)
+	}()
+
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     redisAddr,
 		Password: redisPass,
@@ -76,17 +87,69 @@ func main() {
 		os.Exit(1)
 	}
 
-	publisher := queueredis.NewStreamsClient(redisClient)
-	fetcher := httpclient.New(logger)
-	sources := scraper.PublicSources()
-	scrapeUC := scraper.NewScrapeProxiesUseCase(fetcher, sources, logger, sourceTimeout)
+	var publisher scraper.Publisher
+	switch queueBackend {
+	case queue.BackendRedis:
+		publisher = queueredis.NewStreamsClient(redisClient)
+	case queue.BackendNATS:
+		conn, err := nats.Connect(natsURL)
+		if err != nil {
+			logger.Error("failed to connect to nats", "error", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+
+		publisher, err = queuenats.NewJetStreamClient(conn)
+		if err != nil {
+			logger.Error("failed to open jetstream", "error", err)
+			os.Exit(1)
+		}
+	default:
+		logger.Error("unsupported queue backend", "backend", queueBackend)
+		os.Exit(1)
+	}
+	logger.Info("using queue backend", "backend", queueBackend)
 
 	cleaner := scraperredis.NewCleaner(redisClient, redisKeyPrefix)
+	repo := proxyredis.NewRepository(redisClient, redisKeyPrefix).WithTTL(proxyTTL)
+
+	var cipher storage.Cipher
+	if credentialsKey != "" {
+		aesgcm, err := crypt.NewAESGCMFromBase64(credentialsKey)
+		if err != nil {
+			logger.Error("invalid credentials key", "error", err)
+			os.Exit(1)
+		}
+		repo.WithCipher(aesgcm)
+		cipher = aesgcm
+	}
 
-	scraperAdapt := &scraperAdapter{uc: scrapeUC}
-	serializer := proxySerializer{}
+	registry := metrics.NewRegistry()
+
+	run, err := wiring.NewScheduler(ctx, wiring.SchedulerConfig{
+		Topic:            redisTopic,
+		ScrapeInterval:   scrapeInterval,
+		SourceTimeout:    sourceTimeout,
+		SourcesFile:      sourcesFile,
+		SourcesReload:    sourcesReload,
+		RecheckInterval:  recheckInterval,
+		RecheckMaxAge:    recheckMaxAge,
+		RecheckBatchSize: recheckBatchSize,
+		Quarantine:       quarantinePolicy,
+	}, publisher, repo, cleaner, cipher, registry, logger)
+	if err != nil {
+		logger.Error("failed to set up scheduler", "error", err)
+		os.Exit(1)
+	}
 
-	uc := scraper.NewScheduleScrapingUseCase(scraperAdapt, serializer, publisher, cleaner, scrapeInterval, logger, redisTopic)
+	metricsServer := registry.NewServer(":" + metricsPort)
+	go func() {
+		logger.Info("metrics listening", "addr", metricsServer.Addr)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("metrics server error", "error", err)
+		}
+	}()
+	defer metricsServer.Close()
 
 	go func() {
 		quit := make(chan os.Signal, 1)
@@ -96,7 +159,7 @@ func main() {
 		cancel()
 	}()
 
-	if err := uc.Execute(ctx); err != nil && err != context.Canceled {
+	if err := run(ctx); err != nil && err != context.Canceled {
 		logger.Error("scheduler error", "error", err)
 		os.Exit(1)
 	}
@@ -2,9 +2,8 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	logslog "log/slog"
-	"net/url"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -12,73 +11,22 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 
-	"github.com/JulianoL13/app-proxy-engine/internal/common/events"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
 	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	queuenats "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
 	queueredis "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
-	"github.com/JulianoL13/app-proxy-engine/internal/common/workerpool"
-	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
 	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
-	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
 	httpverifier "github.com/JulianoL13/app-proxy-engine/internal/verifier/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/wiring"
 )
 
-type consumerAdapter struct {
-	inner *queueredis.StreamsClient
-}
-
-func (a *consumerAdapter) Subscribe(ctx context.Context, topic, group, consumer string) (<-chan verifier.Message, error) {
-	innerCh, err := a.inner.Subscribe(ctx, topic, group, consumer)
-	if err != nil {
-		return nil, err
-	}
-
-	outCh := make(chan verifier.Message)
-	go func() {
-		defer close(outCh)
-		for msg := range innerCh {
-			outCh <- verifier.Message{ID: msg.ID, Payload: msg.Payload}
-		}
-	}()
-
-	return outCh, nil
-}
-
-func (a *consumerAdapter) Ack(ctx context.Context, topic, group, msgID string) error {
-	return a.inner.Ack(ctx, topic, group, msgID)
-}
-
-type proxyDeserializer struct{}
-
-func (d proxyDeserializer) Deserialize(payload []byte) (verifier.VerifiedProxy, error) {
-	var event events.ProxyDiscoveredEvent
-	if err := json.Unmarshal(payload, &event); err != nil {
-		return nil, err
-	}
-	p := proxy.NewProxy(event.IP, event.Port, proxy.Protocol(event.Protocol), event.Source)
-	return &proxyAdapter{inner: p}, nil
-}
-
-type proxyAdapter struct {
-	inner *proxy.Proxy
-}
-
-func (a *proxyAdapter) Address() string { return a.inner.Address() }
-func (a *proxyAdapter) URL() *url.URL   { return a.inner.URL() }
-func (a *proxyAdapter) MarkSuccess(latency time.Duration, anonymity string) {
-	a.inner.MarkSuccess(latency, proxy.AnonymityLevelFromString(anonymity))
-}
-
-type writerAdapter struct {
-	inner *proxyredis.Repository
-}
-
-func (w *writerAdapter) Save(ctx context.Context, p verifier.VerifiedProxy) error {
-	pa := p.(*proxyAdapter)
-	return w.inner.Save(ctx, pa.inner)
-}
-
 func main() {
 	_ = godotenv.Load()
 
@@ -89,12 +37,48 @@ func main() {
 	proxyTTL := time.Duration(getEnvInt("PROXY_TTL_MINUTES", 30)) * time.Minute
 	consumerName := getEnv("CONSUMER_NAME", mustHostname())
 	redisTopic := getEnv("REDIS_TOPIC_VERIFY", "proxies:verify")
+	queueBackend := getEnv("QUEUE_BACKEND", queue.BackendRedis)
+	natsURL := getEnv("NATS_URL", nats.DefaultURL)
 	redisGroup := getEnv("REDIS_GROUP_WORKERS", "verifiers")
 	redisKeyPrefix := getEnv("REDIS_KEY_PREFIX", "v1")
 	concurrency := getEnvInt("WORKER_CONCURRENCY", 50)
+	credentialsKey := getEnv("CREDENTIALS_KEY", "")
+	metricsPort := getEnv("METRICS_PORT", "9090")
+	judgeURLs := getEnv("JUDGE_URL", "")
+	judgeKey := getEnv("JUDGE_KEY", "")
+	judgeStrategy := getEnv("JUDGE_STRATEGY", string(httpverifier.StrategyRoundRobin))
+	judgeQuorum := getEnvInt("JUDGE_QUORUM", 0)
+	judgeHealthInterval := time.Duration(getEnvInt("JUDGE_HEALTH_INTERVAL_SECONDS", 30)) * time.Second
+	capabilityProbe := getEnv("CAPABILITY_PROBE_ENABLED", "true") != "false"
+	capabilityPlainURL := getEnv("CAPABILITY_PLAIN_URL", "")
+	capabilityTLSTarget := getEnv("CAPABILITY_TLS_TARGET", "")
+	judgeTLSPort := getEnv("JUDGE_TLS_PORT", httpverifier.DefaultJudgeTLSPort)
+	maxAttempts := getEnvInt("MAX_DELIVERY_ATTEMPTS", 5)
+	retryDelay := time.Duration(getEnvInt("RETRY_DELAY_SECONDS", 30)) * time.Second
+	claimIdle := time.Duration(getEnvInt("CLAIM_IDLE_SECONDS", 300)) * time.Second
+	consumerTTL := time.Duration(getEnvInt("CONSUMER_IDLE_TTL_MINUTES", 60)) * time.Minute
+	geoipDB := getEnv("GEOIP_DB", "")
+	mitmTarget := getEnv("MITM_TARGET", "")
+	mitmPins := getEnv("MITM_PINS", "")
+	traceExporter := getEnv("TRACING_EXPORTER", tracing.ExporterNone)
+	serviceName := getEnv("OTEL_SERVICE_NAME", "proxy-engine-worker")
 
 	logger := slog.NewJSON(logslog.LevelInfo)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		ServiceName: serviceName,
+		Exporter:    traceExporter,
+	})
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = shutdownTracing(shutdownCtx)
+	}()
+
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     redisAddr,
 		Password: redisPass,
@@ -110,19 +94,87 @@ func main() {
 		os.Exit(1)
 	}
 
-	pool, err := workerpool.New(concurrency)
-	if err != nil {
-		logger.Error("failed to create worker pool", "error", err)
+	var q queue.Queue
+	switch queueBackend {
+	case queue.BackendRedis:
+		if claimIdle > 0 && claimIdle <= retryDelay {
+			logger.Error("CLAIM_IDLE_SECONDS must exceed RETRY_DELAY_SECONDS", "claim_idle", claimIdle, "retry_delay", retryDelay)
+			os.Exit(1)
+		}
+		q = queueredis.NewStreamsClient(redisClient).
+			WithRetryDelay(retryDelay).
+			WithClaimIdle(claimIdle).
+			WithConsumerTTL(consumerTTL)
+	case queue.BackendNATS:
+		conn, err := nats.Connect(natsURL)
+		if err != nil {
+			logger.Error("failed to connect to nats", "error", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+
+		js, err := queuenats.NewJetStreamClient(conn)
+		if err != nil {
+			logger.Error("failed to open jetstream", "error", err)
+			os.Exit(1)
+		}
+		q = js.WithRetryDelay(retryDelay)
+	default:
+		logger.Error("unsupported queue backend", "backend", queueBackend)
 		os.Exit(1)
 	}
-	defer pool.Stop()
+	logger.Info("using queue backend", "backend", queueBackend)
+
+	repo := proxyredis.NewRepository(redisClient, redisKeyPrefix).WithTTL(proxyTTL)
 
-	consumer := &consumerAdapter{inner: queueredis.NewStreamsClient(redisClient)}
-	checker := httpverifier.NewChecker("", verifyTimeout, logger)
-	deserializer := proxyDeserializer{}
-	writer := &writerAdapter{inner: proxyredis.NewRepository(redisClient, redisKeyPrefix).WithTTL(proxyTTL)}
+	var cipher storage.Cipher
+	if credentialsKey != "" {
+		aesgcm, err := crypt.NewAESGCMFromBase64(credentialsKey)
+		if err != nil {
+			logger.Error("invalid credentials key", "error", err)
+			os.Exit(1)
+		}
+		repo.WithCipher(aesgcm)
+		cipher = aesgcm
+	}
 
-	uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, consumerName, redisTopic, redisGroup)
+	registry := metrics.NewRegistry()
+
+	uc, closeWorker, err := wiring.NewWorker(ctx, wiring.WorkerConfig{
+		ConsumerName: consumerName,
+		Topic:        redisTopic,
+		Group:        redisGroup,
+		Concurrency:  concurrency,
+		MaxAttempts:  maxAttempts,
+		GeoIPDB:      geoipDB,
+
+		VerifyTimeout:       verifyTimeout,
+		JudgeURLs:           judgeURLs,
+		JudgeKey:            judgeKey,
+		JudgeStrategy:       judgeStrategy,
+		JudgeQuorum:         judgeQuorum,
+		JudgeHealthInterval: judgeHealthInterval,
+		JudgeTLSPort:        judgeTLSPort,
+		CapabilityProbe:     capabilityProbe,
+		CapabilityPlainURL:  capabilityPlainURL,
+		CapabilityTLSTarget: capabilityTLSTarget,
+		MITMTarget:          mitmTarget,
+		MITMPins:            mitmPins,
+	}, q, repo, cipher, registry, logger)
+	if err != nil {
+		logger.Error("failed to set up worker", "error", err)
+		os.Exit(1)
+	}
+	defer closeWorker()
+
+	metricsServer := registry.NewServer(":" + metricsPort)
+	go func() {
+		logger.Info("metrics listening", "addr", metricsServer.Addr)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("metrics server error", "error", err)
+		}
+	}()
+	defer metricsServer.Close()
 
 	go func() {
 		quit := make(chan os.Signal, 1)
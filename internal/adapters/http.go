@@ -0,0 +1,121 @@
+// Package adapters bridges the domain use cases to the ports declared by
+// their delivery packages, so every binary wires them the same way.
+package adapters
+
+import (
+	"context"
+	"errors"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+)
+
+type Logger struct {
+	inner slog.Logger
+}
+
+func NewLogger(inner slog.Logger) *Logger {
+	return &Logger{inner: inner}
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.inner.Debug(msg, args...) }
+func (l *Logger) Info(msg string, args ...any)  { l.inner.Info(msg, args...) }
+func (l *Logger) Warn(msg string, args ...any)  { l.inner.Warn(msg, args...) }
+func (l *Logger) Error(msg string, args ...any) { l.inner.Error(msg, args...) }
+func (l *Logger) With(args ...any) proxyhttp.Logger {
+	return &Logger{inner: l.inner.With(args...)}
+}
+
+type GetProxies struct {
+	uc *proxy.GetProxiesUseCase
+}
+
+func NewGetProxies(uc *proxy.GetProxiesUseCase) *GetProxies {
+	return &GetProxies{uc: uc}
+}
+
+func (a *GetProxies) Execute(ctx context.Context, input proxyhttp.GetProxiesInput) (proxyhttp.GetProxiesOutput, error) {
+	out, err := a.uc.Execute(ctx, proxy.GetProxiesInput{
+		Cursor:     input.Cursor,
+		Limit:      input.Limit,
+		Protocol:   input.Protocol,
+		Anonymity:  input.Anonymity,
+		Capability: input.Capability,
+		Country:    input.Country,
+		ASN:        input.ASN,
+		MaxLatency: input.MaxLatency,
+	})
+	if err != nil {
+		return proxyhttp.GetProxiesOutput{}, err
+	}
+	return proxyhttp.GetProxiesOutput{
+		Proxies:    out.Proxies,
+		NextCursor: out.NextCursor,
+		Total:      out.Total,
+	}, nil
+}
+
+type GetRandomProxy struct {
+	uc *proxy.GetRandomProxyUseCase
+}
+
+func NewGetRandomProxy(uc *proxy.GetRandomProxyUseCase) *GetRandomProxy {
+	return &GetRandomProxy{uc: uc}
+}
+
+func (a *GetRandomProxy) Execute(ctx context.Context, input proxyhttp.GetRandomProxyInput) (*proxy.Proxy, error) {
+	return a.uc.Execute(ctx, proxy.GetRandomProxyInput{
+		Protocol:     input.Protocol,
+		Anonymity:    input.Anonymity,
+		MaxLatency:   input.MaxLatency,
+		Session:      input.Session,
+		DistinctExit: input.DistinctExit,
+	})
+}
+
+// DeadLetters serves the dead letter queue of one topic from whichever
+// queue backend is configured.
+type DeadLetters struct {
+	inner queue.Queue
+	topic string
+}
+
+func NewDeadLetters(inner queue.Queue, topic string) *DeadLetters {
+	return &DeadLetters{inner: inner, topic: topic}
+}
+
+func (a *DeadLetters) List(ctx context.Context, after string, limit int) ([]proxyhttp.DeadLetter, error) {
+	letters, err := a.inner.DeadLetters(ctx, a.topic, after, int64(limit))
+	if err != nil {
+		return nil, deadLetterError(err)
+	}
+	result := make([]proxyhttp.DeadLetter, len(letters))
+	for i, dl := range letters {
+		result[i] = proxyhttp.DeadLetter{
+			ID:         dl.ID,
+			SourceID:   dl.SourceID,
+			Reason:     dl.Reason,
+			Deliveries: dl.Deliveries,
+			FailedAt:   dl.FailedAt,
+			Payload:    dl.Payload,
+		}
+	}
+	return result, nil
+}
+
+func (a *DeadLetters) Replay(ctx context.Context, id string) error {
+	return deadLetterError(a.inner.Replay(ctx, a.topic, id))
+}
+
+func deadLetterError(err error) error {
+	switch {
+	case errors.Is(err, queue.ErrDeadLetterNotFound):
+		return proxyhttp.ErrDeadLetterNotFound
+	case errors.Is(err, queue.ErrInvalidID):
+		return proxyhttp.ErrInvalidDeadLetterID
+	default:
+		return err
+	}
+}
@@ -0,0 +1,46 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/memory"
+	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+)
+
+func TestDeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := memory.NewQueue()
+	require.NoError(t, q.Publish(ctx, "verify", []byte("payload")))
+
+	messages, err := q.Subscribe(ctx, "verify", "workers", "w1")
+	require.NoError(t, err)
+
+	select {
+	case msg := <-messages:
+		require.NoError(t, q.DeadLetter(ctx, "verify", "workers", msg, "broken"))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	dlq := adapters.NewDeadLetters(q, "verify")
+
+	letters, err := dlq.List(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "broken", letters[0].Reason)
+	assert.Equal(t, []byte("payload"), letters[0].Payload)
+
+	_, err = dlq.List(ctx, "1-0", 10)
+	assert.ErrorIs(t, err, proxyhttp.ErrInvalidDeadLetterID)
+
+	require.NoError(t, dlq.Replay(ctx, letters[0].ID))
+	assert.ErrorIs(t, dlq.Replay(ctx, letters[0].ID), proxyhttp.ErrDeadLetterNotFound)
+}
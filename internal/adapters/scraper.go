@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/events"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type Scraper struct {
+	uc *scraper.ScrapeProxiesUseCase
+}
+
+func NewScraper(uc *scraper.ScrapeProxiesUseCase) *Scraper {
+	return &Scraper{uc: uc}
+}
+
+func (a *Scraper) SourceNames() []string {
+	return a.uc.SourceNames()
+}
+
+func (a *Scraper) Execute(ctx context.Context, skip map[string]bool) ([]scraper.ScrapedProxy, []error) {
+	results, errs := a.uc.Execute(ctx, skip)
+	proxies := make([]scraper.ScrapedProxy, len(results))
+	for i, r := range results {
+		proxies[i] = r
+	}
+	return proxies, errs
+}
+
+type StaleProxies interface {
+	GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]*proxy.Proxy, error)
+}
+
+type StaleReader struct {
+	inner StaleProxies
+}
+
+func NewStaleReader(inner StaleProxies) *StaleReader {
+	return &StaleReader{inner: inner}
+}
+
+func (a *StaleReader) GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]scraper.ScrapedProxy, error) {
+	stale, err := a.inner.GetStale(ctx, checkedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	proxies := make([]scraper.ScrapedProxy, len(stale))
+	for i, p := range stale {
+		proxies[i] = scraper.NewScrapeOutputWithAuth(p.IP, p.Port, string(p.Protocol), p.Source, p.Username, p.Password)
+	}
+	return proxies, nil
+}
+
+// ProxySerializer encodes scraped proxies as discovery events; the recheck
+// scheduler uses one with Recheck set. Credentials are sealed with Cipher,
+// and a proxy that has them is refused with storage.ErrNoCipher when no
+// Cipher is configured, so it never reaches the queue.
+type ProxySerializer struct {
+	Cipher  storage.Cipher
+	Recheck bool
+}
+
+func (s ProxySerializer) Serialize(p scraper.ScrapedProxy) ([]byte, error) {
+	event := events.ProxyDiscoveredEvent{
+		IP:       p.IP(),
+		Port:     p.Port(),
+		Protocol: p.Protocol(),
+		Source:   p.Source(),
+		Recheck:  s.Recheck,
+	}
+
+	if p.Username() != "" || p.Password() != "" {
+		sealed, err := sealCredentials(s.Cipher, p.Username(), p.Password())
+		if err != nil {
+			return nil, fmt.Errorf("serialize %s:%d: %w", p.IP(), p.Port(), err)
+		}
+		event.Credentials = sealed
+	}
+
+	return json.Marshal(event)
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func sealCredentials(cipher storage.Cipher, username, password string) ([]byte, error) {
+	if cipher == nil {
+		return nil, storage.ErrNoCipher
+	}
+	plain, err := json.Marshal(credentials{Username: username, Password: password})
+	if err != nil {
+		return nil, err
+	}
+	return cipher.Encrypt(plain)
+}
+
+func openCredentials(cipher storage.Cipher, sealed []byte) (credentials, error) {
+	var creds credentials
+	if cipher == nil {
+		return creds, storage.ErrNoCipher
+	}
+	plain, err := cipher.Decrypt(sealed)
+	if err != nil {
+		return creds, fmt.Errorf("decrypt credentials: %w", err)
+	}
+	if err := json.Unmarshal(plain, &creds); err != nil {
+		return creds, fmt.Errorf("unmarshal credentials: %w", err)
+	}
+	return creds, nil
+}
+
+// SourceHealthRepository is the part of proxy storage that keeps the
+// scraper's quarantine state next to each source's stats.
+type SourceHealthRepository interface {
+	GetSourceStats(ctx context.Context, sources []string) (map[string]proxy.SourceStats, error)
+	SaveSourceHealth(ctx context.Context, s proxy.SourceStats) error
+}
+
+type SourceHealthStore struct {
+	inner SourceHealthRepository
+}
+
+func NewSourceHealthStore(inner SourceHealthRepository) *SourceHealthStore {
+	return &SourceHealthStore{inner: inner}
+}
+
+func (a *SourceHealthStore) GetSourceHealth(ctx context.Context, sources []string) (map[string]scraper.SourceHealth, error) {
+	stats, err := a.inner.GetSourceStats(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+	health := make(map[string]scraper.SourceHealth, len(stats))
+	for name, s := range stats {
+		health[name] = scraper.SourceHealth{
+			Name:             s.Name,
+			Verified:         s.Verified,
+			LastVerified:     s.LastVerified,
+			Strikes:          s.Strikes,
+			Quarantines:      s.Quarantines,
+			QuarantinedUntil: s.QuarantinedUntil,
+			LastCheckedAt:    s.LastCheckedAt,
+			Forced:           s.Forced,
+		}
+	}
+	return health, nil
+}
+
+func (a *SourceHealthStore) SaveSourceHealth(ctx context.Context, health scraper.SourceHealth) error {
+	return a.inner.SaveSourceHealth(ctx, proxy.SourceStats{
+		Name:             health.Name,
+		LastVerified:     health.LastVerified,
+		Strikes:          health.Strikes,
+		Quarantines:      health.Quarantines,
+		QuarantinedUntil: health.QuarantinedUntil,
+		LastCheckedAt:    health.LastCheckedAt,
+	})
+}
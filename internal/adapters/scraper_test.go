@@ -0,0 +1,79 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type fakeSourceRepository struct {
+	stats map[string]proxy.SourceStats
+	saved []proxy.SourceStats
+}
+
+func (r *fakeSourceRepository) GetSourceStats(_ context.Context, sources []string) (map[string]proxy.SourceStats, error) {
+	stats := make(map[string]proxy.SourceStats, len(sources))
+	for _, source := range sources {
+		s := r.stats[source]
+		s.Name = source
+		stats[source] = s
+	}
+	return stats, nil
+}
+
+func (r *fakeSourceRepository) SaveSourceHealth(_ context.Context, s proxy.SourceStats) error {
+	r.saved = append(r.saved, s)
+	return nil
+}
+
+func TestSourceHealthStore(t *testing.T) {
+	ctx := context.Background()
+	until := time.Now().Add(time.Hour)
+	checked := time.Now()
+
+	repo := &fakeSourceRepository{stats: map[string]proxy.SourceStats{
+		"s1": {
+			Scraped:          10,
+			Verified:         4,
+			AvgLatency:       time.Second,
+			LastVerified:     2,
+			Strikes:          1,
+			Quarantines:      3,
+			QuarantinedUntil: until,
+			LastCheckedAt:    checked,
+			Forced:           true,
+		},
+	}}
+	store := adapters.NewSourceHealthStore(repo)
+
+	health, err := store.GetSourceHealth(ctx, []string{"s1", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, scraper.SourceHealth{
+		Name:             "s1",
+		Verified:         4,
+		LastVerified:     2,
+		Strikes:          1,
+		Quarantines:      3,
+		QuarantinedUntil: until,
+		LastCheckedAt:    checked,
+		Forced:           true,
+	}, health["s1"])
+	assert.Equal(t, scraper.SourceHealth{Name: "unknown"}, health["unknown"])
+
+	require.NoError(t, store.SaveSourceHealth(ctx, health["s1"]))
+	assert.Equal(t, []proxy.SourceStats{{
+		Name:             "s1",
+		LastVerified:     2,
+		Strikes:          1,
+		Quarantines:      3,
+		QuarantinedUntil: until,
+		LastCheckedAt:    checked,
+	}}, repo.saved)
+}
@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/events"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+type Consumer struct {
+	inner queue.Queue
+}
+
+func NewConsumer(inner queue.Queue) *Consumer {
+	return &Consumer{inner: inner}
+}
+
+func (a *Consumer) Subscribe(ctx context.Context, topic, group, consumer string) (<-chan verifier.Message, error) {
+	innerCh, err := a.inner.Subscribe(ctx, topic, group, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	outCh := make(chan verifier.Message)
+	go func() {
+		defer close(outCh)
+		for msg := range innerCh {
+			outCh <- verifier.Message{ID: msg.ID, Payload: msg.Payload, Headers: msg.Headers, Deliveries: msg.Deliveries}
+		}
+	}()
+
+	return outCh, nil
+}
+
+func (a *Consumer) Ack(ctx context.Context, topic, group, msgID string) error {
+	return a.inner.Ack(ctx, topic, group, msgID)
+}
+
+func (a *Consumer) Nack(ctx context.Context, topic, group, msgID string) error {
+	return a.inner.Nack(ctx, topic, group, msgID)
+}
+
+func (a *Consumer) DeadLetter(ctx context.Context, topic, group string, msg verifier.Message, reason string) error {
+	return a.inner.DeadLetter(ctx, topic, group, queue.Message{
+		ID:         msg.ID,
+		Payload:    msg.Payload,
+		Headers:    msg.Headers,
+		Deliveries: msg.Deliveries,
+	}, reason)
+}
+
+// ProxyDeserializer decodes discovery events, opening sealed credentials
+// with Cipher.
+type ProxyDeserializer struct {
+	Cipher storage.Cipher
+}
+
+func (d ProxyDeserializer) Deserialize(payload []byte) (verifier.VerifiedProxy, error) {
+	var event events.ProxyDiscoveredEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	p := proxy.NewProxy(event.IP, event.Port, proxy.Protocol(event.Protocol), event.Source)
+	if len(event.Credentials) > 0 {
+		creds, err := openCredentials(d.Cipher, event.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		p.Username = creds.Username
+		p.Password = creds.Password
+	}
+	return &VerifiedProxy{inner: p, recheck: event.Recheck}, nil
+}
+
+// VerifiedProxy exposes a proxy.Proxy to the verifier, which only sees the
+// verifier.VerifiedProxy port.
+type VerifiedProxy struct {
+	inner   *proxy.Proxy
+	recheck bool
+}
+
+func NewVerifiedProxy(p *proxy.Proxy) *VerifiedProxy {
+	return &VerifiedProxy{inner: p}
+}
+
+func (a *VerifiedProxy) Unwrap() *proxy.Proxy { return a.inner }
+func (a *VerifiedProxy) Address() string      { return a.inner.Address() }
+func (a *VerifiedProxy) URL() *url.URL        { return a.inner.URL() }
+func (a *VerifiedProxy) Source() string       { return a.inner.Source }
+func (a *VerifiedProxy) Recheck() bool        { return a.recheck }
+func (a *VerifiedProxy) MarkSuccess(latency time.Duration, anonymity string) {
+	a.inner.MarkSuccess(latency, proxy.AnonymityLevelFromString(anonymity))
+}
+func (a *VerifiedProxy) SetCapabilities(caps verifier.Capabilities) {
+	a.inner.SupportsConnect = caps.Connect
+	a.inner.SupportsPlainHTTP = caps.PlainHTTP
+}
+func (a *VerifiedProxy) SetExitIP(ip string) { a.inner.ExitIP = ip }
+func (a *VerifiedProxy) SetLocation(loc verifier.Location) {
+	a.inner.Country = loc.Country
+	a.inner.City = loc.City
+	a.inner.ASN = loc.ASN
+}
+
+type ProxyWriter interface {
+	Save(ctx context.Context, p *proxy.Proxy) error
+	RecordFailure(ctx context.Context, p *proxy.Proxy) error
+	RecordMITM(ctx context.Context, p *proxy.Proxy) error
+}
+
+type Writer struct {
+	inner ProxyWriter
+}
+
+func NewWriter(inner ProxyWriter) *Writer {
+	return &Writer{inner: inner}
+}
+
+func (w *Writer) Save(ctx context.Context, p verifier.VerifiedProxy) error {
+	err := w.inner.Save(ctx, unwrap(p))
+	if errors.Is(err, proxy.ErrIntercepted) {
+		return fmt.Errorf("%w: %w", verifier.ErrProxyBlocked, err)
+	}
+	return err
+}
+
+func (w *Writer) RecordFailure(ctx context.Context, p verifier.VerifiedProxy) error {
+	return w.inner.RecordFailure(ctx, unwrap(p))
+}
+
+func (w *Writer) RecordMITM(ctx context.Context, p verifier.VerifiedProxy) error {
+	return w.inner.RecordMITM(ctx, unwrap(p))
+}
+
+func unwrap(p verifier.VerifiedProxy) *proxy.Proxy {
+	return p.(*VerifiedProxy).inner
+}
@@ -0,0 +1,177 @@
+package adapters_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/memory"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+type fakeWriter struct {
+	saveErr error
+	saved   []*proxy.Proxy
+	failed  []*proxy.Proxy
+	mitm    []*proxy.Proxy
+}
+
+func (w *fakeWriter) Save(_ context.Context, p *proxy.Proxy) error {
+	w.saved = append(w.saved, p)
+	return w.saveErr
+}
+
+func (w *fakeWriter) RecordFailure(_ context.Context, p *proxy.Proxy) error {
+	w.failed = append(w.failed, p)
+	return nil
+}
+
+func (w *fakeWriter) RecordMITM(_ context.Context, p *proxy.Proxy) error {
+	w.mitm = append(w.mitm, p)
+	return nil
+}
+
+func TestProxySerialization(t *testing.T) {
+	cipher, err := crypt.NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+
+	authenticated := scraper.NewScrapeOutputWithAuth("1.1.1.1", 8080, "socks5", "s1", "alice", "s3cret")
+
+	t.Run("seals credentials in the payload", func(t *testing.T) {
+		payload, err := adapters.ProxySerializer{Cipher: cipher}.Serialize(authenticated)
+		require.NoError(t, err)
+		assert.NotContains(t, string(payload), "alice")
+		assert.NotContains(t, string(payload), "s3cret")
+
+		vp, err := adapters.ProxyDeserializer{Cipher: cipher}.Deserialize(payload)
+		require.NoError(t, err)
+
+		p := vp.(*adapters.VerifiedProxy).Unwrap()
+		assert.Equal(t, "1.1.1.1:8080", vp.Address())
+		assert.Equal(t, "s1", vp.Source())
+		assert.Equal(t, proxy.SOCKS5, p.Protocol)
+		assert.Equal(t, "alice", p.Username)
+		assert.Equal(t, "s3cret", p.Password)
+		assert.False(t, vp.Recheck())
+
+		_, err = adapters.ProxyDeserializer{}.Deserialize(payload)
+		assert.ErrorIs(t, err, storage.ErrNoCipher)
+	})
+
+	t.Run("refuses credentials without a cipher", func(t *testing.T) {
+		_, err := adapters.ProxySerializer{}.Serialize(authenticated)
+		assert.ErrorIs(t, err, storage.ErrNoCipher)
+	})
+
+	t.Run("marks rechecks", func(t *testing.T) {
+		payload, err := adapters.ProxySerializer{Recheck: true}.Serialize(scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "s1"))
+		require.NoError(t, err)
+
+		vp, err := adapters.ProxyDeserializer{}.Deserialize(payload)
+		require.NoError(t, err)
+		assert.True(t, vp.Recheck())
+	})
+
+	t.Run("rejects malformed payloads", func(t *testing.T) {
+		_, err := adapters.ProxyDeserializer{}.Deserialize([]byte("not json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifiedProxy(t *testing.T) {
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	vp := adapters.NewVerifiedProxy(p)
+
+	vp.MarkSuccess(100*time.Millisecond, "elite")
+	vp.SetCapabilities(verifier.Capabilities{Connect: true, PlainHTTP: true})
+	vp.SetExitIP("9.9.9.9")
+	vp.SetLocation(verifier.Location{Country: "BR", City: "Recife", ASN: 123})
+
+	assert.Equal(t, proxy.Elite, p.Anonymity)
+	assert.Equal(t, 100*time.Millisecond, p.Latency)
+	assert.True(t, p.SupportsConnect)
+	assert.True(t, p.SupportsPlainHTTP)
+	assert.Equal(t, "9.9.9.9", p.ExitIP)
+	assert.Equal(t, "BR", p.Country)
+	assert.Equal(t, "Recife", p.City)
+	assert.Equal(t, uint(123), p.ASN)
+}
+
+func TestWriter(t *testing.T) {
+	ctx := context.Background()
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+
+	t.Run("unwraps proxies", func(t *testing.T) {
+		inner := &fakeWriter{}
+		w := adapters.NewWriter(inner)
+
+		require.NoError(t, w.Save(ctx, adapters.NewVerifiedProxy(p)))
+		require.NoError(t, w.RecordFailure(ctx, adapters.NewVerifiedProxy(p)))
+		require.NoError(t, w.RecordMITM(ctx, adapters.NewVerifiedProxy(p)))
+
+		assert.Equal(t, []*proxy.Proxy{p}, inner.saved)
+		assert.Equal(t, []*proxy.Proxy{p}, inner.failed)
+		assert.Equal(t, []*proxy.Proxy{p}, inner.mitm)
+	})
+
+	t.Run("maps intercepted proxies to blocked", func(t *testing.T) {
+		w := adapters.NewWriter(&fakeWriter{saveErr: proxy.ErrIntercepted})
+
+		err := w.Save(ctx, adapters.NewVerifiedProxy(p))
+		assert.ErrorIs(t, err, verifier.ErrProxyBlocked)
+		assert.ErrorIs(t, err, proxy.ErrIntercepted)
+	})
+
+	t.Run("passes other errors through", func(t *testing.T) {
+		boom := errors.New("boom")
+		w := adapters.NewWriter(&fakeWriter{saveErr: boom})
+
+		err := w.Save(ctx, adapters.NewVerifiedProxy(p))
+		assert.ErrorIs(t, err, boom)
+		assert.NotErrorIs(t, err, verifier.ErrProxyBlocked)
+	})
+}
+
+func TestConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := memory.NewQueue()
+	consumer := adapters.NewConsumer(q)
+
+	require.NoError(t, q.Publish(ctx, "verify", []byte("payload")))
+
+	messages, err := consumer.Subscribe(ctx, "verify", "workers", "w1")
+	require.NoError(t, err)
+
+	var msg verifier.Message
+	select {
+	case msg = <-messages:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+	assert.Equal(t, []byte("payload"), msg.Payload)
+	assert.Equal(t, int64(1), msg.Deliveries)
+
+	require.NoError(t, consumer.DeadLetter(ctx, "verify", "workers", msg, "broken"))
+
+	letters, err := q.Subscribe(ctx, queue.DeadLetterTopic("verify"), "inspect", "i1")
+	require.NoError(t, err)
+	select {
+	case dl := <-letters:
+		assert.Equal(t, []byte("payload"), dl.Payload)
+		assert.Equal(t, "broken", dl.Headers[queue.ReasonHeader])
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for dead letter")
+	}
+}
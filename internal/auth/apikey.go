@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeReadList   Scope = "read-list"
+	ScopeReadRandom Scope = "read-random"
+	ScopeAdmin      Scope = "admin"
+)
+
+const (
+	tokenPrefix = "pe_"
+	idLength    = 16
+)
+
+func ParseScope(s string) (Scope, error) {
+	switch scope := Scope(s); scope {
+	case ScopeReadList, ScopeReadRandom, ScopeAdmin:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("%q: %w", s, ErrInvalidScope)
+	}
+}
+
+type APIKey struct {
+	ID        string
+	Name      string
+	Scopes    []Scope
+	PerMinute int
+	PerDay    int
+	CreatedAt time.Time
+}
+
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func KeyID(hash string) string {
+	return hash[:idLength]
+}
+
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
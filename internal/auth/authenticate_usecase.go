@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type KeyReader interface {
+	GetKey(ctx context.Context, hash string) (*APIKey, error)
+}
+
+type QuotaUsage struct {
+	Minute int64
+	Day    int64
+}
+
+type QuotaCounter interface {
+	Consume(ctx context.Context, keyID string, now time.Time) (QuotaUsage, error)
+}
+
+type AuthenticateUseCase struct {
+	reader KeyReader
+	quota  QuotaCounter
+}
+
+func NewAuthenticateUseCase(reader KeyReader, quota QuotaCounter) *AuthenticateUseCase {
+	return &AuthenticateUseCase{
+		reader: reader,
+		quota:  quota,
+	}
+}
+
+func (uc *AuthenticateUseCase) Execute(ctx context.Context, token string, scope Scope) (*APIKey, error) {
+	if token == "" {
+		return nil, ErrInvalidKey
+	}
+
+	key, err := uc.reader.GetKey(ctx, HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if !key.HasScope(scope) {
+		return key, ErrInsufficientScope
+	}
+
+	if key.PerMinute <= 0 && key.PerDay <= 0 {
+		return key, nil
+	}
+
+	now := time.Now()
+	usage, err := uc.quota.Consume(ctx, key.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.PerMinute > 0 && usage.Minute > int64(key.PerMinute) {
+		return key, &QuotaError{
+			Window:     "per-minute",
+			Limit:      key.PerMinute,
+			RetryAfter: now.Truncate(time.Minute).Add(time.Minute).Sub(now),
+		}
+	}
+
+	if key.PerDay > 0 && usage.Day > int64(key.PerDay) {
+		utc := now.UTC()
+		midnight := time.Date(utc.Year(), utc.Month(), utc.Day()+1, 0, 0, 0, 0, time.UTC)
+		return key, &QuotaError{
+			Window:     "per-day",
+			Limit:      key.PerDay,
+			RetryAfter: midnight.Sub(now),
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+	"github.com/JulianoL13/app-proxy-engine/internal/auth/mocks"
+)
+
+func TestAuthenticateUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	token := "pe_test-token"
+	hash := auth.HashToken(token)
+
+	readerKey := &auth.APIKey{ID: auth.KeyID(hash), Scopes: []auth.Scope{auth.ScopeReadList}}
+
+	t.Run("rejects empty token", func(t *testing.T) {
+		uc := auth.NewAuthenticateUseCase(mocks.NewKeyReader(t), mocks.NewQuotaCounter(t))
+
+		_, err := uc.Execute(ctx, "", auth.ScopeReadList)
+
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+	})
+
+	t.Run("rejects unknown key", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(nil, nil)
+
+		_, err := auth.NewAuthenticateUseCase(reader, mocks.NewQuotaCounter(t)).Execute(ctx, token, auth.ScopeReadList)
+
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+	})
+
+	t.Run("propagates store errors", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(nil, errors.New("redis down"))
+
+		_, err := auth.NewAuthenticateUseCase(reader, mocks.NewQuotaCounter(t)).Execute(ctx, token, auth.ScopeReadList)
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, auth.ErrInvalidKey)
+	})
+
+	t.Run("rejects missing scope", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(readerKey, nil)
+
+		_, err := auth.NewAuthenticateUseCase(reader, mocks.NewQuotaCounter(t)).Execute(ctx, token, auth.ScopeReadRandom)
+
+		assert.ErrorIs(t, err, auth.ErrInsufficientScope)
+	})
+
+	t.Run("admin scope grants everything", func(t *testing.T) {
+		admin := &auth.APIKey{ID: "admin", Scopes: []auth.Scope{auth.ScopeAdmin}}
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(admin, nil)
+
+		key, err := auth.NewAuthenticateUseCase(reader, mocks.NewQuotaCounter(t)).Execute(ctx, token, auth.ScopeReadRandom)
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin", key.ID)
+	})
+
+	t.Run("skips quota when unlimited", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(readerKey, nil)
+
+		key, err := auth.NewAuthenticateUseCase(reader, mocks.NewQuotaCounter(t)).Execute(ctx, token, auth.ScopeReadList)
+
+		require.NoError(t, err)
+		assert.Equal(t, readerKey, key)
+	})
+
+	limited := &auth.APIKey{ID: "limited", Scopes: []auth.Scope{auth.ScopeReadList}, PerMinute: 10, PerDay: 100}
+
+	t.Run("allows requests within quota", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(limited, nil)
+		quota := mocks.NewQuotaCounter(t)
+		quota.EXPECT().Consume(ctx, "limited", mock.Anything).Return(auth.QuotaUsage{Minute: 10, Day: 100}, nil)
+
+		_, err := auth.NewAuthenticateUseCase(reader, quota).Execute(ctx, token, auth.ScopeReadList)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects when minute quota is exceeded", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(limited, nil)
+		quota := mocks.NewQuotaCounter(t)
+		quota.EXPECT().Consume(ctx, "limited", mock.Anything).Return(auth.QuotaUsage{Minute: 11, Day: 50}, nil)
+
+		_, err := auth.NewAuthenticateUseCase(reader, quota).Execute(ctx, token, auth.ScopeReadList)
+
+		assert.ErrorIs(t, err, auth.ErrQuotaExceeded)
+		var quotaErr *auth.QuotaError
+		require.ErrorAs(t, err, &quotaErr)
+		assert.Equal(t, "per-minute", quotaErr.Window)
+		assert.Greater(t, quotaErr.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, quotaErr.RetryAfter, time.Minute)
+	})
+
+	t.Run("rejects when day quota is exceeded", func(t *testing.T) {
+		reader := mocks.NewKeyReader(t)
+		reader.EXPECT().GetKey(ctx, hash).Return(limited, nil)
+		quota := mocks.NewQuotaCounter(t)
+		quota.EXPECT().Consume(ctx, "limited", mock.Anything).Return(auth.QuotaUsage{Minute: 1, Day: 101}, nil)
+
+		_, err := auth.NewAuthenticateUseCase(reader, quota).Execute(ctx, token, auth.ScopeReadList)
+
+		var quotaErr *auth.QuotaError
+		require.ErrorAs(t, err, &quotaErr)
+		assert.Equal(t, "per-day", quotaErr.Window)
+		assert.LessOrEqual(t, quotaErr.RetryAfter, 24*time.Hour)
+	})
+}
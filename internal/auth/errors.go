@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidKey        = errors.New("invalid api key")
+	ErrInsufficientScope = errors.New("insufficient scope")
+	ErrQuotaExceeded     = errors.New("quota exceeded")
+	ErrInvalidScope      = errors.New("invalid scope")
+	ErrInvalidQuota      = errors.New("quotas must not be negative")
+	ErrKeyNotFound       = errors.New("api key not found")
+)
+
+type QuotaError struct {
+	Window     string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s quota of %d requests exceeded", e.Window, e.Limit)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// BootstrapKeyName names the admin key registered from the configured token.
+const BootstrapKeyName = "bootstrap-admin"
+
+type ManageKeysLogger interface {
+	Info(msg string, args ...any)
+}
+
+type KeyStore interface {
+	SaveKey(ctx context.Context, hash string, key APIKey) error
+	ListKeys(ctx context.Context) ([]APIKey, error)
+	DeleteKey(ctx context.Context, id string) error
+	IsRevoked(ctx context.Context, id string) (bool, error)
+}
+
+type CreateKeyInput struct {
+	Name      string
+	Scopes    []Scope
+	PerMinute int
+	PerDay    int
+	Token     string
+}
+
+type CreateKeyOutput struct {
+	Key   APIKey
+	Token string
+}
+
+type ManageKeysUseCase struct {
+	store  KeyStore
+	logger ManageKeysLogger
+}
+
+func NewManageKeysUseCase(store KeyStore, logger ManageKeysLogger) *ManageKeysUseCase {
+	return &ManageKeysUseCase{
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (uc *ManageKeysUseCase) Create(ctx context.Context, input CreateKeyInput) (CreateKeyOutput, error) {
+	if len(input.Scopes) == 0 {
+		return CreateKeyOutput{}, ErrInvalidScope
+	}
+	for _, s := range input.Scopes {
+		if _, err := ParseScope(string(s)); err != nil {
+			return CreateKeyOutput{}, err
+		}
+	}
+	if input.PerMinute < 0 || input.PerDay < 0 {
+		return CreateKeyOutput{}, ErrInvalidQuota
+	}
+
+	token := input.Token
+	if token == "" {
+		generated, err := GenerateToken()
+		if err != nil {
+			return CreateKeyOutput{}, err
+		}
+		token = generated
+	}
+
+	hash := HashToken(token)
+	key := APIKey{
+		ID:        KeyID(hash),
+		Name:      input.Name,
+		Scopes:    input.Scopes,
+		PerMinute: input.PerMinute,
+		PerDay:    input.PerDay,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := uc.store.SaveKey(ctx, hash, key); err != nil {
+		return CreateKeyOutput{}, err
+	}
+
+	uc.logger.Info("api key created", "id", key.ID, "name", key.Name, "scopes", key.Scopes)
+	return CreateKeyOutput{Key: key, Token: token}, nil
+}
+
+func (uc *ManageKeysUseCase) List(ctx context.Context) ([]APIKey, error) {
+	keys, err := uc.store.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (uc *ManageKeysUseCase) Revoke(ctx context.Context, id string) error {
+	if err := uc.store.DeleteKey(ctx, id); err != nil {
+		return err
+	}
+
+	uc.logger.Info("api key revoked", "id", id)
+	return nil
+}
+
+// Bootstrap registers token as the admin key. Bootstrap keys left over from a
+// previous token are revoked, and a key that already exists or was revoked is
+// left as is, so a restart neither resets nor resurrects it.
+func (uc *ManageKeysUseCase) Bootstrap(ctx context.Context, token string) error {
+	keys, err := uc.store.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	id := KeyID(HashToken(token))
+	exists := false
+	for _, key := range keys {
+		if key.Name != BootstrapKeyName {
+			continue
+		}
+		if key.ID == id {
+			exists = true
+			continue
+		}
+		if err := uc.Revoke(ctx, key.ID); err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return err
+		}
+	}
+	if exists {
+		return nil
+	}
+
+	revoked, err := uc.store.IsRevoked(ctx, id)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		uc.logger.Info("bootstrap admin key was revoked, not registering it again", "id", id)
+		return nil
+	}
+
+	_, err = uc.Create(ctx, CreateKeyInput{
+		Name:   BootstrapKeyName,
+		Scopes: []Scope{ScopeAdmin},
+		Token:  token,
+	})
+	return err
+}
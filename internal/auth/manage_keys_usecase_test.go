@@ -0,0 +1,170 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+	"github.com/JulianoL13/app-proxy-engine/internal/auth/mocks"
+)
+
+type testLogger struct{}
+
+func (l testLogger) Info(msg string, args ...any) {}
+
+func TestManageKeysUseCase_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generates and stores hashed token", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		var savedHash string
+		store.EXPECT().SaveKey(ctx, mock.Anything, mock.Anything).
+			Run(func(_ context.Context, hash string, key auth.APIKey) { savedHash = hash }).
+			Return(nil)
+
+		out, err := auth.NewManageKeysUseCase(store, testLogger{}).Create(ctx, auth.CreateKeyInput{
+			Name:      "bot",
+			Scopes:    []auth.Scope{auth.ScopeReadRandom},
+			PerMinute: 60,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(out.Token, "pe_"))
+		assert.Equal(t, auth.HashToken(out.Token), savedHash)
+		assert.NotContains(t, savedHash, out.Token)
+		assert.Equal(t, auth.KeyID(savedHash), out.Key.ID)
+		assert.Equal(t, 60, out.Key.PerMinute)
+	})
+
+	t.Run("uses provided token", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().SaveKey(ctx, auth.HashToken("fixed"), mock.Anything).Return(nil)
+
+		out, err := auth.NewManageKeysUseCase(store, testLogger{}).Create(ctx, auth.CreateKeyInput{
+			Name:   "admin",
+			Scopes: []auth.Scope{auth.ScopeAdmin},
+			Token:  "fixed",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "fixed", out.Token)
+	})
+
+	t.Run("rejects invalid scope", func(t *testing.T) {
+		_, err := auth.NewManageKeysUseCase(mocks.NewKeyStore(t), testLogger{}).Create(ctx, auth.CreateKeyInput{
+			Name:   "bot",
+			Scopes: []auth.Scope{"write"},
+		})
+
+		assert.ErrorIs(t, err, auth.ErrInvalidScope)
+	})
+
+	t.Run("rejects negative quota", func(t *testing.T) {
+		_, err := auth.NewManageKeysUseCase(mocks.NewKeyStore(t), testLogger{}).Create(ctx, auth.CreateKeyInput{
+			Name:   "bot",
+			Scopes: []auth.Scope{auth.ScopeReadList},
+			PerDay: -1,
+		})
+
+		assert.ErrorIs(t, err, auth.ErrInvalidQuota)
+	})
+}
+
+func TestManageKeysUseCase_List(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	store := mocks.NewKeyStore(t)
+	store.EXPECT().ListKeys(ctx).Return([]auth.APIKey{
+		{ID: "b", CreatedAt: now},
+		{ID: "a", CreatedAt: now.Add(-time.Hour)},
+	}, nil)
+
+	keys, err := auth.NewManageKeysUseCase(store, testLogger{}).List(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "a", keys[0].ID)
+	assert.Equal(t, "b", keys[1].ID)
+}
+
+func TestManageKeysUseCase_Revoke(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes key", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().DeleteKey(ctx, "abc").Return(nil)
+
+		assert.NoError(t, auth.NewManageKeysUseCase(store, testLogger{}).Revoke(ctx, "abc"))
+	})
+
+	t.Run("propagates not found", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().DeleteKey(ctx, "missing").Return(auth.ErrKeyNotFound)
+
+		err := auth.NewManageKeysUseCase(store, testLogger{}).Revoke(ctx, "missing")
+		assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+	})
+}
+
+func TestManageKeysUseCase_Bootstrap(t *testing.T) {
+	ctx := context.Background()
+	hash := auth.HashToken("admin-token")
+	id := auth.KeyID(hash)
+
+	t.Run("creates missing key", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().ListKeys(ctx).Return(nil, nil)
+		store.EXPECT().IsRevoked(ctx, id).Return(false, nil)
+		store.EXPECT().SaveKey(ctx, hash, mock.MatchedBy(func(key auth.APIKey) bool {
+			return key.ID == id && key.Name == auth.BootstrapKeyName && key.HasScope(auth.ScopeAdmin)
+		})).Return(nil)
+
+		assert.NoError(t, auth.NewManageKeysUseCase(store, testLogger{}).Bootstrap(ctx, "admin-token"))
+	})
+
+	t.Run("keeps existing key", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().ListKeys(ctx).Return([]auth.APIKey{
+			{ID: id, Name: auth.BootstrapKeyName},
+			{ID: "other", Name: "bot"},
+		}, nil)
+
+		assert.NoError(t, auth.NewManageKeysUseCase(store, testLogger{}).Bootstrap(ctx, "admin-token"))
+	})
+
+	t.Run("revokes keys of a previous token", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().ListKeys(ctx).Return([]auth.APIKey{
+			{ID: "old", Name: auth.BootstrapKeyName},
+			{ID: "other", Name: "bot"},
+		}, nil)
+		store.EXPECT().DeleteKey(ctx, "old").Return(nil)
+		store.EXPECT().IsRevoked(ctx, id).Return(false, nil)
+		store.EXPECT().SaveKey(ctx, hash, mock.Anything).Return(nil)
+
+		assert.NoError(t, auth.NewManageKeysUseCase(store, testLogger{}).Bootstrap(ctx, "admin-token"))
+	})
+
+	t.Run("does not restore a revoked key", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().ListKeys(ctx).Return(nil, nil)
+		store.EXPECT().IsRevoked(ctx, id).Return(true, nil)
+
+		assert.NoError(t, auth.NewManageKeysUseCase(store, testLogger{}).Bootstrap(ctx, "admin-token"))
+	})
+
+	t.Run("propagates list failure", func(t *testing.T) {
+		store := mocks.NewKeyStore(t)
+		store.EXPECT().ListKeys(ctx).Return(nil, assert.AnError)
+
+		err := auth.NewManageKeysUseCase(store, testLogger{}).Bootstrap(ctx, "admin-token")
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
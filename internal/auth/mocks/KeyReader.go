@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/JulianoL13/app-proxy-engine/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyReader is an autogenerated mock type for the KeyReader type
+type KeyReader struct {
+	mock.Mock
+}
+
+type KeyReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeyReader) EXPECT() *KeyReader_Expecter {
+	return &KeyReader_Expecter{mock: &_m.Mock}
+}
+
+// GetKey provides a mock function with given fields: ctx, hash
+func (_m *KeyReader) GetKey(ctx context.Context, hash string) (*auth.APIKey, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetKey")
+	}
+
+	var r0 *auth.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*auth.APIKey, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *auth.APIKey); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyReader_GetKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetKey'
+type KeyReader_GetKey_Call struct {
+	*mock.Call
+}
+
+// GetKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *KeyReader_Expecter) GetKey(ctx interface{}, hash interface{}) *KeyReader_GetKey_Call {
+	return &KeyReader_GetKey_Call{Call: _e.mock.On("GetKey", ctx, hash)}
+}
+
+func (_c *KeyReader_GetKey_Call) Run(run func(ctx context.Context, hash string)) *KeyReader_GetKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *KeyReader_GetKey_Call) Return(_a0 *auth.APIKey, _a1 error) *KeyReader_GetKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyReader_GetKey_Call) RunAndReturn(run func(context.Context, string) (*auth.APIKey, error)) *KeyReader_GetKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeyReader creates a new instance of KeyReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyReader {
+	mock := &KeyReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,248 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/JulianoL13/app-proxy-engine/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyStore is an autogenerated mock type for the KeyStore type
+type KeyStore struct {
+	mock.Mock
+}
+
+type KeyStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeyStore) EXPECT() *KeyStore_Expecter {
+	return &KeyStore_Expecter{mock: &_m.Mock}
+}
+
+// DeleteKey provides a mock function with given fields: ctx, id
+func (_m *KeyStore) DeleteKey(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// KeyStore_DeleteKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteKey'
+type KeyStore_DeleteKey_Call struct {
+	*mock.Call
+}
+
+// DeleteKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *KeyStore_Expecter) DeleteKey(ctx interface{}, id interface{}) *KeyStore_DeleteKey_Call {
+	return &KeyStore_DeleteKey_Call{Call: _e.mock.On("DeleteKey", ctx, id)}
+}
+
+func (_c *KeyStore_DeleteKey_Call) Run(run func(ctx context.Context, id string)) *KeyStore_DeleteKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *KeyStore_DeleteKey_Call) Return(_a0 error) *KeyStore_DeleteKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KeyStore_DeleteKey_Call) RunAndReturn(run func(context.Context, string) error) *KeyStore_DeleteKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsRevoked provides a mock function with given fields: ctx, id
+func (_m *KeyStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyStore_IsRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRevoked'
+type KeyStore_IsRevoked_Call struct {
+	*mock.Call
+}
+
+// IsRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *KeyStore_Expecter) IsRevoked(ctx interface{}, id interface{}) *KeyStore_IsRevoked_Call {
+	return &KeyStore_IsRevoked_Call{Call: _e.mock.On("IsRevoked", ctx, id)}
+}
+
+func (_c *KeyStore_IsRevoked_Call) Run(run func(ctx context.Context, id string)) *KeyStore_IsRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *KeyStore_IsRevoked_Call) Return(_a0 bool, _a1 error) *KeyStore_IsRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyStore_IsRevoked_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *KeyStore_IsRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListKeys provides a mock function with given fields: ctx
+func (_m *KeyStore) ListKeys(ctx context.Context) ([]auth.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListKeys")
+	}
+
+	var r0 []auth.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]auth.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []auth.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]auth.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyStore_ListKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListKeys'
+type KeyStore_ListKeys_Call struct {
+	*mock.Call
+}
+
+// ListKeys is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *KeyStore_Expecter) ListKeys(ctx interface{}) *KeyStore_ListKeys_Call {
+	return &KeyStore_ListKeys_Call{Call: _e.mock.On("ListKeys", ctx)}
+}
+
+func (_c *KeyStore_ListKeys_Call) Run(run func(ctx context.Context)) *KeyStore_ListKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *KeyStore_ListKeys_Call) Return(_a0 []auth.APIKey, _a1 error) *KeyStore_ListKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyStore_ListKeys_Call) RunAndReturn(run func(context.Context) ([]auth.APIKey, error)) *KeyStore_ListKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveKey provides a mock function with given fields: ctx, hash, key
+func (_m *KeyStore) SaveKey(ctx context.Context, hash string, key auth.APIKey) error {
+	ret := _m.Called(ctx, hash, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, auth.APIKey) error); ok {
+		r0 = rf(ctx, hash, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// KeyStore_SaveKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveKey'
+type KeyStore_SaveKey_Call struct {
+	*mock.Call
+}
+
+// SaveKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+//   - key auth.APIKey
+func (_e *KeyStore_Expecter) SaveKey(ctx interface{}, hash interface{}, key interface{}) *KeyStore_SaveKey_Call {
+	return &KeyStore_SaveKey_Call{Call: _e.mock.On("SaveKey", ctx, hash, key)}
+}
+
+func (_c *KeyStore_SaveKey_Call) Run(run func(ctx context.Context, hash string, key auth.APIKey)) *KeyStore_SaveKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(auth.APIKey))
+	})
+	return _c
+}
+
+func (_c *KeyStore_SaveKey_Call) Return(_a0 error) *KeyStore_SaveKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KeyStore_SaveKey_Call) RunAndReturn(run func(context.Context, string, auth.APIKey) error) *KeyStore_SaveKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeyStore creates a new instance of KeyStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyStore {
+	mock := &KeyStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/JulianoL13/app-proxy-engine/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// QuotaCounter is an autogenerated mock type for the QuotaCounter type
+type QuotaCounter struct {
+	mock.Mock
+}
+
+type QuotaCounter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *QuotaCounter) EXPECT() *QuotaCounter_Expecter {
+	return &QuotaCounter_Expecter{mock: &_m.Mock}
+}
+
+// Consume provides a mock function with given fields: ctx, keyID, now
+func (_m *QuotaCounter) Consume(ctx context.Context, keyID string, now time.Time) (auth.QuotaUsage, error) {
+	ret := _m.Called(ctx, keyID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 auth.QuotaUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (auth.QuotaUsage, error)); ok {
+		return rf(ctx, keyID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) auth.QuotaUsage); ok {
+		r0 = rf(ctx, keyID, now)
+	} else {
+		r0 = ret.Get(0).(auth.QuotaUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, keyID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QuotaCounter_Consume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Consume'
+type QuotaCounter_Consume_Call struct {
+	*mock.Call
+}
+
+// Consume is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID string
+//   - now time.Time
+func (_e *QuotaCounter_Expecter) Consume(ctx interface{}, keyID interface{}, now interface{}) *QuotaCounter_Consume_Call {
+	return &QuotaCounter_Consume_Call{Call: _e.mock.On("Consume", ctx, keyID, now)}
+}
+
+func (_c *QuotaCounter_Consume_Call) Run(run func(ctx context.Context, keyID string, now time.Time)) *QuotaCounter_Consume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *QuotaCounter_Consume_Call) Return(_a0 auth.QuotaUsage, _a1 error) *QuotaCounter_Consume_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *QuotaCounter_Consume_Call) RunAndReturn(run func(context.Context, string, time.Time) (auth.QuotaUsage, error)) *QuotaCounter_Consume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewQuotaCounter creates a new instance of QuotaCounter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewQuotaCounter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *QuotaCounter {
+	mock := &QuotaCounter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,168 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+)
+
+const (
+	fieldID        = "id"
+	fieldName      = "name"
+	fieldScopes    = "scopes"
+	fieldPerMinute = "per_minute"
+	fieldPerDay    = "per_day"
+	fieldCreatedAt = "created_at"
+)
+
+type KeyStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func NewKeyStore(client *redis.Client, keyPrefix string) *KeyStore {
+	if keyPrefix == "" {
+		keyPrefix = "proxies"
+	}
+	return &KeyStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (s *KeyStore) keyIndexKey() string {
+	return fmt.Sprintf("%s:apikeys", s.keyPrefix)
+}
+
+func (s *KeyStore) revokedKey() string {
+	return fmt.Sprintf("%s:apikeys:revoked", s.keyPrefix)
+}
+
+func (s *KeyStore) apiKeyKey(hash string) string {
+	return fmt.Sprintf("%s:apikey:%s", s.keyPrefix, hash)
+}
+
+func (s *KeyStore) SaveKey(ctx context.Context, hash string, key auth.APIKey) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.keyIndexKey(), key.ID, hash)
+	pipe.Del(ctx, s.apiKeyKey(hash))
+	pipe.HSet(ctx, s.apiKeyKey(hash),
+		fieldID, key.ID,
+		fieldName, key.Name,
+		fieldScopes, strings.Join(scopes, ","),
+		fieldPerMinute, key.PerMinute,
+		fieldPerDay, key.PerDay,
+		fieldCreatedAt, key.CreatedAt.Unix(),
+	)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("save api key: %w", err)
+	}
+	return nil
+}
+
+func (s *KeyStore) GetKey(ctx context.Context, hash string) (*auth.APIKey, error) {
+	fields, err := s.client.HGetAll(ctx, s.apiKeyKey(hash)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	key := parseKey(fields)
+	return &key, nil
+}
+
+func (s *KeyStore) ListKeys(ctx context.Context) ([]auth.APIKey, error) {
+	hashes, err := s.client.HVals(ctx, s.keyIndexKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	pipe := s.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(hashes))
+	for i, hash := range hashes {
+		cmds[i] = pipe.HGetAll(ctx, s.apiKeyKey(hash))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("hgetall api keys: %w", err)
+	}
+
+	keys := make([]auth.APIKey, 0, len(cmds))
+	for _, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		keys = append(keys, parseKey(fields))
+	}
+
+	return keys, nil
+}
+
+func (s *KeyStore) DeleteKey(ctx context.Context, id string) error {
+	hash, err := s.client.HGet(ctx, s.keyIndexKey(), id).Result()
+	if err == redis.Nil {
+		return auth.ErrKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get api key hash: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HDel(ctx, s.keyIndexKey(), id)
+	pipe.Del(ctx, s.apiKeyKey(hash))
+	pipe.SAdd(ctx, s.revokedKey(), id)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("delete api key: %w", err)
+	}
+	return nil
+}
+
+func (s *KeyStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	revoked, err := s.client.SIsMember(ctx, s.revokedKey(), id).Result()
+	if err != nil {
+		return false, fmt.Errorf("check revoked api key: %w", err)
+	}
+	return revoked, nil
+}
+
+func parseKey(fields map[string]string) auth.APIKey {
+	var scopes []auth.Scope
+	for _, s := range strings.Split(fields[fieldScopes], ",") {
+		if s != "" {
+			scopes = append(scopes, auth.Scope(s))
+		}
+	}
+
+	return auth.APIKey{
+		ID:        fields[fieldID],
+		Name:      fields[fieldName],
+		Scopes:    scopes,
+		PerMinute: int(parseInt(fields[fieldPerMinute])),
+		PerDay:    int(parseInt(fields[fieldPerDay])),
+		CreatedAt: time.Unix(parseInt(fields[fieldCreatedAt]), 0).UTC(),
+	}
+}
+
+func parseInt(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
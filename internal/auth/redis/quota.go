@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+)
+
+const (
+	minuteWindowTTL = 2 * time.Minute
+	dayWindowTTL    = 48 * time.Hour
+)
+
+type QuotaCounter struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func NewQuotaCounter(client *redis.Client, keyPrefix string) *QuotaCounter {
+	if keyPrefix == "" {
+		keyPrefix = "proxies"
+	}
+	return &QuotaCounter{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (q *QuotaCounter) minuteKey(keyID string, now time.Time) string {
+	return fmt.Sprintf("%s:quota:%s:m:%d", q.keyPrefix, keyID, now.Unix()/60)
+}
+
+func (q *QuotaCounter) dayKey(keyID string, now time.Time) string {
+	return fmt.Sprintf("%s:quota:%s:d:%s", q.keyPrefix, keyID, now.UTC().Format("20060102"))
+}
+
+func (q *QuotaCounter) Consume(ctx context.Context, keyID string, now time.Time) (auth.QuotaUsage, error) {
+	minuteKey := q.minuteKey(keyID, now)
+	dayKey := q.dayKey(keyID, now)
+
+	pipe := q.client.TxPipeline()
+	minute := pipe.Incr(ctx, minuteKey)
+	pipe.Expire(ctx, minuteKey, minuteWindowTTL)
+	day := pipe.Incr(ctx, dayKey)
+	pipe.Expire(ctx, dayKey, dayWindowTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return auth.QuotaUsage{}, fmt.Errorf("consume quota: %w", err)
+	}
+
+	return auth.QuotaUsage{Minute: minute.Val(), Day: day.Val()}, nil
+}
@@ -0,0 +1,134 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+	authredis "github.com/JulianoL13/app-proxy-engine/internal/auth/redis"
+)
+
+func TestKeyStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	store := authredis.NewKeyStore(client, "test")
+
+	hash := auth.HashToken("pe_secret")
+	key := auth.APIKey{
+		ID:        auth.KeyID(hash),
+		Name:      "bot",
+		Scopes:    []auth.Scope{auth.ScopeReadList, auth.ScopeReadRandom},
+		PerMinute: 60,
+		PerDay:    1000,
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+	}
+
+	t.Run("saves and loads key by hash", func(t *testing.T) {
+		require.NoError(t, store.SaveKey(ctx, hash, key))
+
+		loaded, err := store.GetKey(ctx, hash)
+		require.NoError(t, err)
+		require.NotNil(t, loaded)
+		assert.Equal(t, key, *loaded)
+
+		exists, err := client.Exists(ctx, "test:apikey:pe_secret").Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), exists)
+	})
+
+	t.Run("returns nil for unknown hash", func(t *testing.T) {
+		loaded, err := store.GetKey(ctx, auth.HashToken("nope"))
+		require.NoError(t, err)
+		assert.Nil(t, loaded)
+	})
+
+	t.Run("lists keys", func(t *testing.T) {
+		keys, err := store.ListKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, key.ID, keys[0].ID)
+	})
+
+	t.Run("deletes key by id", func(t *testing.T) {
+		require.NoError(t, store.DeleteKey(ctx, key.ID))
+
+		loaded, err := store.GetKey(ctx, hash)
+		require.NoError(t, err)
+		assert.Nil(t, loaded)
+
+		assert.ErrorIs(t, store.DeleteKey(ctx, key.ID), auth.ErrKeyNotFound)
+
+		revoked, err := store.IsRevoked(ctx, key.ID)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("reports keys never revoked", func(t *testing.T) {
+		revoked, err := store.IsRevoked(ctx, "unknown")
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
+
+func TestQuotaCounter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	counter := authredis.NewQuotaCounter(client, "test")
+	now := time.Date(2024, 5, 1, 12, 30, 10, 0, time.UTC)
+
+	t.Run("counts requests per window", func(t *testing.T) {
+		usage, err := counter.Consume(ctx, "k1", now)
+		require.NoError(t, err)
+		assert.Equal(t, auth.QuotaUsage{Minute: 1, Day: 1}, usage)
+
+		usage, err = counter.Consume(ctx, "k1", now.Add(20*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, auth.QuotaUsage{Minute: 2, Day: 2}, usage)
+	})
+
+	t.Run("resets minute window but keeps day window", func(t *testing.T) {
+		usage, err := counter.Consume(ctx, "k1", now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, auth.QuotaUsage{Minute: 1, Day: 3}, usage)
+	})
+
+	t.Run("tracks keys independently", func(t *testing.T) {
+		usage, err := counter.Consume(ctx, "k2", now)
+		require.NoError(t, err)
+		assert.Equal(t, auth.QuotaUsage{Minute: 1, Day: 1}, usage)
+	})
+}
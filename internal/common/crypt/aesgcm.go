@@ -0,0 +1,68 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidKey        = errors.New("encryption key must be 16, 24 or 32 bytes")
+	ErrMalformedEnvelope = errors.New("malformed ciphertext")
+)
+
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+func NewAESGCMFromBase64(encoded string) (*AESGCM, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	return NewAESGCM(key)
+}
+
+func (c *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *AESGCM) Decrypt(data []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(data) < size {
+		return nil, ErrMalformedEnvelope
+	}
+
+	plaintext, err := c.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
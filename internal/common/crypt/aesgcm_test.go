@@ -0,0 +1,72 @@
+package crypt_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+)
+
+func TestAESGCM(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	c, err := crypt.NewAESGCM(key)
+	require.NoError(t, err)
+
+	t.Run("round trips", func(t *testing.T) {
+		sealed, err := c.Encrypt([]byte("user:secret"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(sealed), "secret")
+
+		plain, err := c.Decrypt(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "user:secret", string(plain))
+	})
+
+	t.Run("uses a fresh nonce per message", func(t *testing.T) {
+		a, err := c.Encrypt([]byte("same"))
+		require.NoError(t, err)
+		b, err := c.Encrypt([]byte("same"))
+		require.NoError(t, err)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("rejects tampered data", func(t *testing.T) {
+		sealed, err := c.Encrypt([]byte("payload"))
+		require.NoError(t, err)
+		sealed[len(sealed)-1] ^= 0xFF
+
+		_, err = c.Decrypt(sealed)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects short data", func(t *testing.T) {
+		_, err := c.Decrypt([]byte("x"))
+		assert.ErrorIs(t, err, crypt.ErrMalformedEnvelope)
+	})
+
+	t.Run("rejects invalid key size", func(t *testing.T) {
+		_, err := crypt.NewAESGCM([]byte("short"))
+		assert.ErrorIs(t, err, crypt.ErrInvalidKey)
+	})
+
+	t.Run("accepts base64 key", func(t *testing.T) {
+		fromB64, err := crypt.NewAESGCMFromBase64(base64.StdEncoding.EncodeToString(key))
+		require.NoError(t, err)
+
+		sealed, err := c.Encrypt([]byte("shared"))
+		require.NoError(t, err)
+		plain, err := fromB64.Decrypt(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "shared", string(plain))
+
+		_, err = crypt.NewAESGCMFromBase64("not base64!")
+		assert.Error(t, err)
+	})
+}
@@ -5,6 +5,10 @@ type ProxyDiscoveredEvent struct {
 	Port     int    `json:"port"`
 	Protocol string `json:"protocol"`
 	Source   string `json:"source"`
-	Username string `json:"username,omitempty"`
-	Password string `json:"password,omitempty"`
+	// Credentials holds the username and password of an authenticated proxy
+	// sealed with the shared credentials key; they never travel in the clear.
+	Credentials []byte `json:"credentials,omitempty"`
+	// Recheck marks a proxy requeued by the recheck scheduler rather than
+	// freshly scraped, so it does not count towards its source's stats again.
+	Recheck bool `json:"recheck,omitempty"`
 }
@@ -0,0 +1,98 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+)
+
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+
+	srv := httptest.NewServer(reg.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := metrics.NewHTTPMetrics(reg)
+
+	m.ObserveRequest("GET", "/api/v1/proxies", 200, 20*time.Millisecond)
+	m.ObserveRequest("GET", "/api/v1/proxies", 200, 30*time.Millisecond)
+	m.ObserveRequest("GET", "/api/v1/proxies", 401, time.Millisecond)
+
+	expected := `
+# HELP proxy_engine_http_requests_total HTTP requests handled, by method, route and status.
+# TYPE proxy_engine_http_requests_total counter
+proxy_engine_http_requests_total{method="GET",route="/api/v1/proxies",status="200"} 2
+proxy_engine_http_requests_total{method="GET",route="/api/v1/proxies",status="401"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "proxy_engine_http_requests_total"))
+	assert.Contains(t, scrape(t, reg), `proxy_engine_http_request_duration_seconds_count{method="GET",route="/api/v1/proxies",status="200"} 2`)
+}
+
+func TestVerifierMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := metrics.NewVerifierMetrics(reg)
+
+	m.ObserveVerification("success", 300*time.Millisecond)
+	m.ObserveVerification("proxy_timeout", 10*time.Second)
+
+	body := scrape(t, reg)
+	assert.Contains(t, body, `proxy_engine_verifier_verifications_total{outcome="success"} 1`)
+	assert.Contains(t, body, `proxy_engine_verifier_verifications_total{outcome="proxy_timeout"} 1`)
+	assert.Contains(t, body, `proxy_engine_verifier_check_duration_seconds_count{outcome="success"} 1`)
+}
+
+func TestScraperMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := metrics.NewScraperMetrics(reg)
+
+	m.ObserveFetch("s1", "success", 120, time.Second)
+	m.ObserveFetch("s2", "skipped", 0, 0)
+	m.ObservePublish("s1", 118, 2)
+
+	body := scrape(t, reg)
+	assert.Contains(t, body, `proxy_engine_scraper_source_fetches_total{result="success",source="s1"} 1`)
+	assert.Contains(t, body, `proxy_engine_scraper_source_fetches_total{result="skipped",source="s2"} 1`)
+	assert.Contains(t, body, `proxy_engine_scraper_source_proxies_total{source="s1"} 120`)
+	assert.Contains(t, body, `proxy_engine_scraper_published_total{result="success",source="s1"} 118`)
+	assert.Contains(t, body, `proxy_engine_scraper_published_total{result="error",source="s1"} 2`)
+	assert.NotContains(t, body, `proxy_engine_scraper_source_fetch_duration_seconds_count{source="s2"}`)
+}
+
+type fakePool struct {
+	workers, running, waiting int
+}
+
+func (p fakePool) Workers() int { return p.workers }
+func (p fakePool) Running() int { return p.running }
+func (p fakePool) Waiting() int { return p.waiting }
+
+func TestRegisterPool(t *testing.T) {
+	reg := metrics.NewRegistry()
+	metrics.RegisterPool(reg, fakePool{workers: 50, running: 40, waiting: 3})
+
+	body := scrape(t, reg)
+	assert.Contains(t, body, "proxy_engine_worker_pool_capacity 50")
+	assert.Contains(t, body, "proxy_engine_worker_pool_running 40")
+	assert.Contains(t, body, "proxy_engine_worker_pool_waiting 3")
+	assert.Contains(t, body, "proxy_engine_worker_pool_saturation_ratio 0.8")
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type PoolStats interface {
+	Workers() int
+	Running() int
+	Waiting() int
+}
+
+func RegisterPool(reg *Registry, pool PoolStats) {
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker_pool",
+			Name:      "capacity",
+			Help:      "Maximum number of concurrent verification jobs.",
+		}, func() float64 { return float64(pool.Workers()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker_pool",
+			Name:      "running",
+			Help:      "Verification jobs currently running.",
+		}, func() float64 { return float64(pool.Running()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker_pool",
+			Name:      "waiting",
+			Help:      "Submissions blocked waiting for a free worker.",
+		}, func() float64 { return float64(pool.Waiting()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker_pool",
+			Name:      "saturation_ratio",
+			Help:      "Running jobs divided by pool capacity.",
+		}, func() float64 {
+			if pool.Workers() <= 0 {
+				return 0
+			}
+			return float64(pool.Running()) / float64(pool.Workers())
+		}),
+	)
+}
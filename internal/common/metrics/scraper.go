@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type ScraperMetrics struct {
+	fetches   *prometheus.CounterVec
+	fetched   *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	published *prometheus.CounterVec
+}
+
+func NewScraperMetrics(reg *Registry) *ScraperMetrics {
+	m := &ScraperMetrics{
+		fetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "source_fetches_total",
+			Help:      "Source fetch attempts, by source and result.",
+		}, []string{"source", "result"}),
+		fetched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "source_proxies_total",
+			Help:      "Proxies parsed from successful source fetches, by source.",
+		}, []string{"source"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "source_fetch_duration_seconds",
+			Help:      "Source fetch latency, by source.",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 45, 60},
+		}, []string{"source"}),
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scraper",
+			Name:      "published_total",
+			Help:      "Proxies published to the verify queue, by source and result.",
+		}, []string{"source", "result"}),
+	}
+	reg.MustRegister(m.fetches, m.fetched, m.duration, m.published)
+	return m
+}
+
+func (m *ScraperMetrics) ObserveFetch(source, result string, proxies int, duration time.Duration) {
+	m.fetches.WithLabelValues(source, result).Inc()
+	if proxies > 0 {
+		m.fetched.WithLabelValues(source).Add(float64(proxies))
+	}
+	if duration > 0 {
+		m.duration.WithLabelValues(source).Observe(duration.Seconds())
+	}
+}
+
+func (m *ScraperMetrics) ObservePublish(source string, published, failed int) {
+	m.published.WithLabelValues(source, "success").Add(float64(published))
+	if failed > 0 {
+		m.published.WithLabelValues(source, "error").Add(float64(failed))
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type VerifierMetrics struct {
+	verifications *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+}
+
+func NewVerifierMetrics(reg *Registry) *VerifierMetrics {
+	m := &VerifierMetrics{
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "verifier",
+			Name:      "verifications_total",
+			Help:      "Proxy verifications, by outcome.",
+		}, []string{"outcome"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "verifier",
+			Name:      "check_duration_seconds",
+			Help:      "Time spent checking a proxy, by outcome.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 8, 10, 15, 30},
+		}, []string{"outcome"}),
+	}
+	reg.MustRegister(m.verifications, m.latency)
+	return m
+}
+
+func (m *VerifierMetrics) ObserveVerification(outcome string, latency time.Duration) {
+	m.verifications.WithLabelValues(outcome).Inc()
+	m.latency.WithLabelValues(outcome).Observe(latency.Seconds())
+}
@@ -0,0 +1,425 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
+)
+
+const (
+	defaultMaxLen = 100000
+	readBatch     = 10
+	tracerName    = "github.com/JulianoL13/app-proxy-engine/internal/common/queue/memory"
+)
+
+// entry is a published message. Replaying a dead letter marks it removed
+// instead of cutting it out, which would shift the entries after it.
+type entry struct {
+	seq     uint64
+	payload []byte
+	headers map[string]string
+	removed bool
+}
+
+// pendingEntry is an unacked delivery. holder is the subscription that has
+// it in flight, or zero once it is nacked or that subscription ended.
+type pendingEntry struct {
+	consumer    string
+	holder      uint64
+	deliveries  int64
+	deliveredAt time.Time
+}
+
+type group struct {
+	next    uint64
+	pending map[uint64]*pendingEntry
+}
+
+type topic struct {
+	entries []entry
+	lastSeq uint64
+	groups  map[string]*group
+	notify  chan struct{}
+}
+
+func (t *topic) firstSeq() uint64 {
+	if len(t.entries) == 0 {
+		return t.lastSeq + 1
+	}
+	return t.entries[0].seq
+}
+
+func (t *topic) lookup(seq uint64) (entry, bool) {
+	first := t.firstSeq()
+	if seq < first || seq > t.lastSeq || t.entries[seq-first].removed {
+		return entry{}, false
+	}
+	return t.entries[seq-first], true
+}
+
+// Queue keeps topics, consumer groups and pending entries in process memory,
+// mirroring the delivery semantics of the Redis Streams backend. Nothing
+// survives a restart.
+type Queue struct {
+	mu         sync.Mutex
+	topics     map[string]*topic
+	maxLen     int
+	retryDelay time.Duration
+	claimIdle  time.Duration
+	subs       uint64
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		topics:     make(map[string]*topic),
+		maxLen:     defaultMaxLen,
+		retryDelay: queue.DefaultRetryDelay,
+	}
+}
+
+func (q *Queue) WithMaxLen(maxLen int) *Queue {
+	q.maxLen = maxLen
+	return q
+}
+
+func (q *Queue) WithRetryDelay(delay time.Duration) *Queue {
+	q.retryDelay = delay
+	return q
+}
+
+func (q *Queue) WithClaimIdle(idle time.Duration) *Queue {
+	q.claimIdle = idle
+	return q
+}
+
+func (q *Queue) topic(name string) *topic {
+	t, ok := q.topics[name]
+	if !ok {
+		t = &topic{groups: make(map[string]*group), notify: make(chan struct{})}
+		q.topics[name] = t
+	}
+	return t
+}
+
+func (q *Queue) Publish(ctx context.Context, topic string, payload []byte) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "queue.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "memory"),
+			attribute.String("messaging.destination.name", topic),
+		),
+	)
+	defer span.End()
+
+	headers := make(map[string]string)
+	tracing.Inject(ctx, headers)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seq := q.append(topic, payload, headers)
+	span.SetAttributes(attribute.String("messaging.message.id", formatID(seq)))
+	return nil
+}
+
+func (q *Queue) append(name string, payload []byte, headers map[string]string) uint64 {
+	t := q.topic(name)
+	t.lastSeq++
+	t.entries = append(t.entries, entry{
+		seq:     t.lastSeq,
+		payload: append([]byte(nil), payload...),
+		headers: headers,
+	})
+	// trim approximately, like XADD MAXLEN ~, so a full topic is not copied
+	// on every publish
+	if q.maxLen > 0 && len(t.entries) > q.maxLen+q.maxLen/10 {
+		t.entries = append([]entry(nil), t.entries[len(t.entries)-q.maxLen:]...)
+	}
+
+	close(t.notify)
+	t.notify = make(chan struct{})
+	return t.lastSeq
+}
+
+func (q *Queue) Subscribe(ctx context.Context, topic, group, consumer string) (<-chan queue.Message, error) {
+	q.mu.Lock()
+	t := q.topic(topic)
+	if _, ok := t.groups[group]; !ok {
+		t.groups[group] = newGroup(t)
+	}
+	q.subs++
+	sub := q.subs
+	q.mu.Unlock()
+
+	messages := make(chan queue.Message)
+
+	go func() {
+		defer close(messages)
+		defer q.release(topic, group, sub)
+
+		interval := q.retryDelay
+		if q.claimIdle > 0 {
+			interval = min(interval, q.claimIdle)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		minIdle := time.Duration(0)
+		for {
+			batch, notify := q.read(topic, group, consumer, sub, minIdle)
+			minIdle = q.retryDelay
+
+			for _, m := range batch {
+				select {
+				case <-ctx.Done():
+					return
+				case messages <- m:
+				}
+			}
+			if len(batch) > 0 {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+func newGroup(t *topic) *group {
+	return &group{next: t.firstSeq(), pending: make(map[uint64]*pendingEntry)}
+}
+
+// read hands out this consumer's own entries pending for at least minIdle,
+// entries orphaned by other consumers past the claim threshold, and then new
+// entries, leaving alone the ones still in flight. It returns the channel
+// that is closed on the next publish.
+func (q *Queue) read(name, groupName, consumer string, sub uint64, minIdle time.Duration) ([]queue.Message, <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.topic(name)
+	g := t.groups[groupName]
+	now := time.Now()
+
+	var batch []queue.Message
+	deliver := func(seq uint64, p *pendingEntry) {
+		e, ok := t.lookup(seq)
+		if !ok {
+			delete(g.pending, seq)
+			return
+		}
+		p.consumer = consumer
+		p.holder = sub
+		p.deliveries++
+		p.deliveredAt = now
+		batch = append(batch, toMessage(e, p.deliveries))
+	}
+
+	for _, seq := range slices.Sorted(maps.Keys(g.pending)) {
+		p := g.pending[seq]
+		if p.holder != 0 {
+			continue
+		}
+		idle := now.Sub(p.deliveredAt)
+		own := p.consumer == consumer && idle >= minIdle
+		orphaned := p.consumer != consumer && q.claimIdle > 0 && idle >= q.claimIdle
+		if own || orphaned {
+			deliver(seq, p)
+		}
+	}
+
+	g.next = max(g.next, t.firstSeq())
+	for len(batch) < readBatch && g.next <= t.lastSeq {
+		p := &pendingEntry{}
+		g.pending[g.next] = p
+		deliver(g.next, p)
+		g.next++
+	}
+
+	return batch, t.notify
+}
+
+func toMessage(e entry, deliveries int64) queue.Message {
+	headers := make(map[string]string, len(e.headers))
+	for k, v := range e.headers {
+		headers[k] = v
+	}
+	return queue.Message{
+		ID:         formatID(e.seq),
+		Payload:    e.payload,
+		Headers:    headers,
+		Deliveries: deliveries,
+	}
+}
+
+func (q *Queue) Ack(_ context.Context, topic, group, msgID string) error {
+	seq, err := parseID(msgID)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.ack(topic, group, seq)
+	return nil
+}
+
+// Nack hands an entry back for redelivery once the retry delay has passed
+// since the failure.
+func (q *Queue) Nack(_ context.Context, topic, group, msgID string) error {
+	seq, err := parseID(msgID)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if p, ok := q.pending(topic, group, seq); ok {
+		p.holder = 0
+		p.deliveredAt = time.Now()
+	}
+	return nil
+}
+
+// release hands the entries a finished subscription still held back to the
+// group.
+func (q *Queue) release(name, groupName string, sub uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, p := range q.topics[name].groups[groupName].pending {
+		if p.holder == sub {
+			p.holder = 0
+		}
+	}
+}
+
+func (q *Queue) pending(name, groupName string, seq uint64) (*pendingEntry, bool) {
+	t, ok := q.topics[name]
+	if !ok {
+		return nil, false
+	}
+	g, ok := t.groups[groupName]
+	if !ok {
+		return nil, false
+	}
+	p, ok := g.pending[seq]
+	return p, ok
+}
+
+func (q *Queue) ack(name, groupName string, seq uint64) {
+	t, ok := q.topics[name]
+	if !ok {
+		return
+	}
+	if g, ok := t.groups[groupName]; ok {
+		delete(g.pending, seq)
+	}
+}
+
+func (q *Queue) DeadLetter(_ context.Context, topic, group string, msg queue.Message, reason string) error {
+	seq, err := parseID(msg.ID)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.append(queue.DeadLetterTopic(topic), msg.Payload, queue.DeadLetterHeaders(msg, reason))
+	q.ack(topic, group, seq)
+	return nil
+}
+
+func (q *Queue) DeadLetters(_ context.Context, topic, after string, count int64) ([]queue.DeadLetter, error) {
+	var from uint64
+	if after != "" {
+		seq, err := parseID(after)
+		if err != nil {
+			return nil, err
+		}
+		from = seq
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t, ok := q.topics[queue.DeadLetterTopic(topic)]
+	if !ok {
+		return nil, nil
+	}
+
+	var letters []queue.DeadLetter
+	for _, e := range t.entries {
+		if count > 0 && int64(len(letters)) >= count {
+			break
+		}
+		if e.seq <= from || e.removed {
+			continue
+		}
+		letters = append(letters, queue.NewDeadLetter(formatID(e.seq), e.payload, e.headers))
+	}
+	return letters, nil
+}
+
+// Replay publishes a dead letter back to its topic with its original headers
+// and removes it from the dead letter queue.
+func (q *Queue) Replay(_ context.Context, topic, id string) error {
+	seq, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t, ok := q.topics[queue.DeadLetterTopic(topic)]
+	if !ok {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+	e, ok := t.lookup(seq)
+	if !ok {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+
+	t.entries[seq-t.firstSeq()].removed = true
+	dl := queue.NewDeadLetter(id, e.payload, e.headers)
+	q.append(topic, dl.Payload, dl.Headers)
+	return nil
+}
+
+func (q *Queue) Close() error {
+	return nil
+}
+
+func formatID(seq uint64) string {
+	return strconv.FormatUint(seq, 10)
+}
+
+func parseID(id string) (uint64, error) {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("message id %q: %w", id, queue.ErrInvalidID)
+	}
+	return seq, nil
+}
@@ -0,0 +1,58 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/memory"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/queuetest"
+)
+
+func TestQueue_Conformance(t *testing.T) {
+	queuetest.Run(t, memory.NewQueue().WithRetryDelay(queuetest.RetryDelay))
+}
+
+func TestQueue_ClaimIdle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := memory.NewQueue().WithClaimIdle(100 * time.Millisecond)
+	require.NoError(t, q.Publish(ctx, "topic", []byte("orphan")))
+
+	deadCtx, deadCancel := context.WithCancel(ctx)
+	messages, err := q.Subscribe(deadCtx, "topic", "group", "dead")
+	require.NoError(t, err)
+	<-messages
+	deadCancel()
+
+	messages, err = q.Subscribe(ctx, "topic", "group", "live")
+	require.NoError(t, err)
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, "orphan", string(msg.Payload))
+		assert.Equal(t, int64(2), msg.Deliveries)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for claimed message")
+	}
+}
+
+func TestQueue_MaxLen(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := memory.NewQueue().WithMaxLen(2)
+	for _, p := range []string{"a", "b", "c"} {
+		require.NoError(t, q.Publish(ctx, "topic", []byte(p)))
+	}
+
+	messages, err := q.Subscribe(ctx, "topic", "group", "consumer")
+	require.NoError(t, err)
+
+	assert.Equal(t, "b", string((<-messages).Payload))
+	assert.Equal(t, "c", string((<-messages).Payload))
+}
@@ -0,0 +1,427 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
+)
+
+const (
+	defaultMaxLen = 1000000
+	errorBackoff  = 1 * time.Second
+	readBatch     = 10
+	tracerName    = "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
+)
+
+var ErrUnknownMessage = errors.New("message not in flight")
+
+// JetStreamClient maps each topic to a stream and each group to a durable
+// pull consumer. Unacked messages come back once AckWait (the retry delay)
+// expires, to whichever member of the group pulls next, so there is no
+// per-consumer pending list to recover or reclaim. While a message is in
+// flight its subscription keeps resetting AckWait with progress acks.
+type JetStreamClient struct {
+	js         jetstream.JetStream
+	maxLen     int64
+	retryDelay time.Duration
+
+	mu       sync.Mutex
+	subs     uint64
+	streams  map[string]struct{}
+	inflight map[string]inflightMsg
+}
+
+type inflightMsg struct {
+	msg jetstream.Msg
+	sub uint64
+}
+
+func NewJetStreamClient(conn *nats.Conn) (*JetStreamClient, error) {
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("jetstream: %w", err)
+	}
+
+	return &JetStreamClient{
+		js:         js,
+		maxLen:     defaultMaxLen,
+		retryDelay: queue.DefaultRetryDelay,
+		streams:    make(map[string]struct{}),
+		inflight:   make(map[string]inflightMsg),
+	}, nil
+}
+
+func (c *JetStreamClient) WithMaxLen(maxLen int64) *JetStreamClient {
+	c.maxLen = maxLen
+	return c
+}
+
+func (c *JetStreamClient) WithRetryDelay(delay time.Duration) *JetStreamClient {
+	c.retryDelay = delay
+	return c
+}
+
+func (c *JetStreamClient) ensureStream(ctx context.Context, topic string) (string, error) {
+	name := streamName(topic)
+
+	c.mu.Lock()
+	_, ok := c.streams[name]
+	c.mu.Unlock()
+	if ok {
+		return name, nil
+	}
+
+	_, err := c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      name,
+		Subjects:  []string{topic},
+		Retention: jetstream.LimitsPolicy,
+		MaxMsgs:   c.maxLen,
+		Storage:   jetstream.FileStorage,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create stream %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	c.streams[name] = struct{}{}
+	c.mu.Unlock()
+	return name, nil
+}
+
+func (c *JetStreamClient) Publish(ctx context.Context, topic string, payload []byte) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "queue.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", topic),
+		),
+	)
+	defer span.End()
+
+	headers := make(map[string]string)
+	tracing.Inject(ctx, headers)
+
+	ack, err := c.publish(ctx, topic, payload, headers)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	span.SetAttributes(attribute.String("messaging.message.id", formatID(ack.Sequence)))
+	return nil
+}
+
+func (c *JetStreamClient) publish(ctx context.Context, topic string, payload []byte, headers map[string]string) (*jetstream.PubAck, error) {
+	if _, err := c.ensureStream(ctx, topic); err != nil {
+		return nil, err
+	}
+
+	msg := nats.NewMsg(topic)
+	msg.Data = payload
+	for k, v := range headers {
+		msg.Header.Set(k, v)
+	}
+
+	ack, err := c.js.PublishMsg(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("publish %s: %w", topic, err)
+	}
+	return ack, nil
+}
+
+func (c *JetStreamClient) Subscribe(ctx context.Context, topic, group, _ string) (<-chan queue.Message, error) {
+	stream, err := c.ensureStream(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	cons, err := c.js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+		Durable:       consumerName(group),
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       c.retryDelay,
+		MaxDeliver:    -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create consumer %s: %w", group, err)
+	}
+
+	iter, err := cons.Messages(jetstream.PullMaxMessages(readBatch))
+	if err != nil {
+		return nil, fmt.Errorf("consume %s: %w", topic, err)
+	}
+
+	messages := make(chan queue.Message)
+
+	c.mu.Lock()
+	c.subs++
+	sub := c.subs
+	c.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		iter.Stop()
+	}()
+
+	go c.keepAlive(ctx, sub)
+
+	go func() {
+		defer close(messages)
+
+		for {
+			msg, err := iter.Next()
+			if err != nil {
+				if ctx.Err() != nil || errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+					return
+				}
+				time.Sleep(errorBackoff)
+				continue
+			}
+
+			m, ok := c.track(topic, group, sub, msg)
+			if !ok {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case messages <- m:
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+func (c *JetStreamClient) track(topic, group string, sub uint64, msg jetstream.Msg) (queue.Message, bool) {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return queue.Message{}, false
+	}
+
+	headers := firstValues(msg.Headers())
+
+	id := formatID(meta.Sequence.Stream)
+	c.mu.Lock()
+	c.inflight[inflightKey(topic, group, id)] = inflightMsg{msg: msg, sub: sub}
+	c.mu.Unlock()
+
+	return queue.Message{
+		ID:         id,
+		Payload:    msg.Data(),
+		Headers:    headers,
+		Deliveries: int64(meta.NumDelivered),
+	}, true
+}
+
+// keepAlive sends progress acks for the messages sub holds in flight, often
+// enough that AckWait never expires under them. It stops with the
+// subscription, so the messages of a stopped consumer come back.
+func (c *JetStreamClient) keepAlive(ctx context.Context, sub uint64) {
+	if c.retryDelay <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.retryDelay / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var held []jetstream.Msg
+		c.mu.Lock()
+		for _, m := range c.inflight {
+			if m.sub == sub {
+				held = append(held, m.msg)
+			}
+		}
+		c.mu.Unlock()
+
+		for _, msg := range held {
+			_ = msg.InProgress()
+		}
+	}
+}
+
+func (c *JetStreamClient) untrack(topic, group, msgID string) (jetstream.Msg, bool) {
+	key := inflightKey(topic, group, msgID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.inflight[key]
+	delete(c.inflight, key)
+	return m.msg, ok
+}
+
+func (c *JetStreamClient) Ack(ctx context.Context, topic, group, msgID string) error {
+	msg, ok := c.untrack(topic, group, msgID)
+	if !ok {
+		return fmt.Errorf("ack %s: %w", msgID, ErrUnknownMessage)
+	}
+	if err := msg.DoubleAck(ctx); err != nil {
+		return fmt.Errorf("ack %s: %w", msgID, err)
+	}
+	return nil
+}
+
+// Nack hands a message back for redelivery once the retry delay has passed
+// since the failure.
+func (c *JetStreamClient) Nack(_ context.Context, topic, group, msgID string) error {
+	msg, ok := c.untrack(topic, group, msgID)
+	if !ok {
+		return fmt.Errorf("nack %s: %w", msgID, ErrUnknownMessage)
+	}
+	if err := msg.NakWithDelay(c.retryDelay); err != nil {
+		return fmt.Errorf("nack %s: %w", msgID, err)
+	}
+	return nil
+}
+
+func (c *JetStreamClient) DeadLetter(ctx context.Context, topic, group string, msg queue.Message, reason string) error {
+	if _, err := c.publish(ctx, queue.DeadLetterTopic(topic), msg.Payload, queue.DeadLetterHeaders(msg, reason)); err != nil {
+		return fmt.Errorf("dead letter %s: %w", msg.ID, err)
+	}
+	return c.Ack(ctx, topic, group, msg.ID)
+}
+
+// DeadLetters reads the dead letter stream of topic by sequence, starting
+// after the entry id after.
+func (c *JetStreamClient) DeadLetters(ctx context.Context, topic, after string, count int64) ([]queue.DeadLetter, error) {
+	var seq uint64
+	if after != "" {
+		from, err := parseID(after)
+		if err != nil {
+			return nil, err
+		}
+		seq = from
+	}
+
+	dlq := queue.DeadLetterTopic(topic)
+	stream, err := c.js.Stream(ctx, streamName(dlq))
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("stream %s: %w", dlq, err)
+	}
+
+	var letters []queue.DeadLetter
+	for count <= 0 || int64(len(letters)) < count {
+		raw, err := stream.GetMsg(ctx, seq+1, jetstream.WithGetMsgSubject(dlq))
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", dlq, err)
+		}
+
+		letters = append(letters, queue.NewDeadLetter(formatID(raw.Sequence), raw.Data, firstValues(raw.Header)))
+		seq = raw.Sequence
+	}
+	return letters, nil
+}
+
+// Replay publishes a dead letter back to its topic with its original headers
+// and deletes it from the dead letter stream.
+func (c *JetStreamClient) Replay(ctx context.Context, topic, id string) error {
+	seq, err := parseID(id)
+	if err != nil {
+		return err
+	}
+
+	dlq := queue.DeadLetterTopic(topic)
+	stream, err := c.js.Stream(ctx, streamName(dlq))
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("stream %s: %w", dlq, err)
+	}
+
+	raw, err := stream.GetMsg(ctx, seq)
+	if errors.Is(err, jetstream.ErrMsgNotFound) {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", dlq, err)
+	}
+
+	dl := queue.NewDeadLetter(id, raw.Data, firstValues(raw.Header))
+	if _, err := c.publish(ctx, topic, dl.Payload, dl.Headers); err != nil {
+		return fmt.Errorf("replay %s: %w", id, err)
+	}
+	if err := stream.DeleteMsg(ctx, seq); err != nil {
+		return fmt.Errorf("replay %s: %w", id, err)
+	}
+	return nil
+}
+
+func (c *JetStreamClient) Close() error {
+	return nil
+}
+
+// streamName derives a valid stream or consumer name from a topic, since
+// names may not contain the dots, colons and wildcards subjects allow.
+func streamName(topic string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, topic)
+}
+
+func consumerName(group string) string {
+	return streamName(group)
+}
+
+func inflightKey(topic, group, id string) string {
+	return topic + "\x00" + group + "\x00" + id
+}
+
+func formatID(seq uint64) string {
+	return strconv.FormatUint(seq, 10)
+}
+
+func parseID(id string) (uint64, error) {
+	seq, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("message id %q: %w", id, queue.ErrInvalidID)
+	}
+	return seq, nil
+}
+
+// firstValues flattens NATS headers, which may repeat a key, to the single
+// values queue messages carry.
+func firstValues(header nats.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for k, v := range header {
+		if len(v) > 0 {
+			headers[k] = v[0]
+		}
+	}
+	return headers
+}
@@ -0,0 +1,37 @@
+package nats_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+	natscontainer "github.com/testcontainers/testcontainers-go/modules/nats"
+
+	queuenats "github.com/JulianoL13/app-proxy-engine/internal/common/queue/nats"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/queuetest"
+)
+
+func TestJetStreamClient_Conformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	natsContainer, err := natscontainer.Run(ctx, "nats:2.11-alpine")
+	require.NoError(t, err)
+	defer func() { _ = natsContainer.Terminate(ctx) }()
+
+	url, err := natsContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	conn, err := nats.Connect(url)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := queuenats.NewJetStreamClient(conn)
+	require.NoError(t, err)
+
+	queuetest.Run(t, client.WithRetryDelay(queuetest.RetryDelay))
+}
@@ -0,0 +1,105 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendNATS   = "nats"
+	BackendMemory = "memory"
+)
+
+const (
+	DefaultRetryDelay = 30 * time.Second
+
+	DeadLetterHeaderPrefix = "dlq_"
+	ReasonHeader           = DeadLetterHeaderPrefix + "reason"
+	SourceIDHeader         = DeadLetterHeaderPrefix + "source_id"
+	DeliveriesHeader       = DeadLetterHeaderPrefix + "deliveries"
+	FailedAtHeader         = DeadLetterHeaderPrefix + "failed_at"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrInvalidID          = errors.New("invalid message id")
+)
+
+type Message struct {
+	ID         string
+	Payload    []byte
+	Headers    map[string]string
+	Deliveries int64
+}
+
+// Queue is the contract every broker backend implements: messages published
+// to a topic are shared by the consumers of a group, stay pending until
+// acked, and are redelivered with a growing Deliveries count otherwise.
+// A delivered message is in flight until it is acked, nacked or
+// dead-lettered, or its subscription ends, and is not redelivered meanwhile;
+// Nack hands it back for redelivery after the retry delay.
+type Queue interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	Subscribe(ctx context.Context, topic, group, consumer string) (<-chan Message, error)
+	Ack(ctx context.Context, topic, group, msgID string) error
+	Nack(ctx context.Context, topic, group, msgID string) error
+	DeadLetter(ctx context.Context, topic, group string, msg Message, reason string) error
+	DeadLetters(ctx context.Context, topic, after string, count int64) ([]DeadLetter, error)
+	Replay(ctx context.Context, topic, id string) error
+	Close() error
+}
+
+// DeadLetter is an entry of a topic's dead letter queue. Headers holds the
+// original message headers, without the dead letter bookkeeping.
+type DeadLetter struct {
+	ID         string
+	SourceID   string
+	Reason     string
+	Deliveries int64
+	FailedAt   time.Time
+	Payload    []byte
+	Headers    map[string]string
+}
+
+func DeadLetterTopic(topic string) string {
+	return topic + ":dlq"
+}
+
+// DeadLetterHeaders returns the headers msg is stored with in the dead letter
+// queue: its own, plus why and when it failed.
+func DeadLetterHeaders(msg Message, reason string) map[string]string {
+	headers := make(map[string]string, len(msg.Headers)+4)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[ReasonHeader] = reason
+	headers[SourceIDHeader] = msg.ID
+	headers[DeliveriesHeader] = strconv.FormatInt(msg.Deliveries, 10)
+	headers[FailedAtHeader] = time.Now().UTC().Format(time.RFC3339)
+	return headers
+}
+
+// NewDeadLetter reads the entry id of a dead letter queue back from the
+// headers DeadLetterHeaders wrote.
+func NewDeadLetter(id string, payload []byte, headers map[string]string) DeadLetter {
+	dl := DeadLetter{
+		ID:       id,
+		SourceID: headers[SourceIDHeader],
+		Reason:   headers[ReasonHeader],
+		Payload:  payload,
+		Headers:  make(map[string]string, len(headers)),
+	}
+	dl.Deliveries, _ = strconv.ParseInt(headers[DeliveriesHeader], 10, 64)
+	dl.FailedAt, _ = time.Parse(time.RFC3339, headers[FailedAtHeader])
+
+	for k, v := range headers {
+		if !strings.HasPrefix(k, DeadLetterHeaderPrefix) {
+			dl.Headers[k] = v
+		}
+	}
+	return dl
+}
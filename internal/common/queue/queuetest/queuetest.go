@@ -0,0 +1,266 @@
+// Package queuetest holds the conformance suite every queue backend runs
+// against, so the pipeline behaves the same whichever broker is configured.
+package queuetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+)
+
+const (
+	// RetryDelay is the redelivery delay backends under test must be
+	// configured with.
+	RetryDelay = 300 * time.Millisecond
+
+	deliveryTimeout = 15 * time.Second
+	quietPeriod     = 3 * RetryDelay
+)
+
+func Run(t *testing.T, q queue.Queue) {
+	t.Run("delivers published messages", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(ctx, topic, []byte(`{"msg":"hello"}`)))
+
+		msg := receive(t, messages)
+		assert.Equal(t, `{"msg":"hello"}`, string(msg.Payload))
+		assert.NotEmpty(t, msg.ID)
+		assert.Equal(t, int64(1), msg.Deliveries)
+		require.NoError(t, q.Ack(ctx, topic, "group", msg.ID))
+	})
+
+	t.Run("delivers messages published before the group existed", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		require.NoError(t, q.Publish(ctx, topic, []byte("early")))
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+
+		msg := receive(t, messages)
+		assert.Equal(t, "early", string(msg.Payload))
+		require.NoError(t, q.Ack(ctx, topic, "group", msg.ID))
+	})
+
+	t.Run("redelivers nacked messages with a growing delivery count", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(ctx, topic, []byte("retry")))
+
+		first := receive(t, messages)
+		assert.Equal(t, int64(1), first.Deliveries)
+
+		expectNone(t, messages)
+		require.NoError(t, q.Nack(ctx, topic, "group", first.ID))
+
+		second := receive(t, messages)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, "retry", string(second.Payload))
+		assert.Equal(t, int64(2), second.Deliveries)
+		require.NoError(t, q.Ack(ctx, topic, "group", second.ID))
+	})
+
+	t.Run("does not redeliver acked messages", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(ctx, topic, []byte("once")))
+
+		msg := receive(t, messages)
+		require.NoError(t, q.Ack(ctx, topic, "group", msg.ID))
+
+		expectNone(t, messages)
+	})
+
+	t.Run("shares messages between consumers of a group", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		first, err := q.Subscribe(ctx, topic, "group", "first")
+		require.NoError(t, err)
+		second, err := q.Subscribe(ctx, topic, "group", "second")
+		require.NoError(t, err)
+
+		want := []string{"a", "b", "c", "d"}
+		for _, p := range want {
+			require.NoError(t, q.Publish(ctx, topic, []byte(p)))
+		}
+
+		var got []string
+		deadline := time.After(deliveryTimeout)
+		for len(got) < len(want) {
+			var msg queue.Message
+			select {
+			case msg = <-first:
+			case msg = <-second:
+			case <-deadline:
+				t.Fatalf("received %v, want %v", got, want)
+			}
+			require.NoError(t, q.Ack(ctx, topic, "group", msg.ID))
+			got = append(got, string(msg.Payload))
+		}
+		assert.ElementsMatch(t, want, got)
+
+		expectNone(t, first)
+		expectNone(t, second)
+	})
+
+	t.Run("delivers every message to each group", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		first, err := q.Subscribe(ctx, topic, "first", "consumer")
+		require.NoError(t, err)
+		second, err := q.Subscribe(ctx, topic, "second", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(ctx, topic, []byte("fanout")))
+
+		a := receive(t, first)
+		b := receive(t, second)
+		assert.Equal(t, "fanout", string(a.Payload))
+		assert.Equal(t, "fanout", string(b.Payload))
+		require.NoError(t, q.Ack(ctx, topic, "first", a.ID))
+		require.NoError(t, q.Ack(ctx, topic, "second", b.ID))
+	})
+
+	t.Run("redelivers to the next subscriber after a consumer stops", func(t *testing.T) {
+		topic := topicName(t)
+
+		firstCtx, firstCancel := context.WithCancel(context.Background())
+		messages, err := q.Subscribe(firstCtx, topic, "group", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(firstCtx, topic, []byte("recover")))
+
+		lost := receive(t, messages)
+		firstCancel()
+
+		ctx := subscribeContext(t)
+		messages, err = q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+
+		msg := receive(t, messages)
+		assert.Equal(t, lost.ID, msg.ID)
+		assert.Equal(t, "recover", string(msg.Payload))
+		assert.Equal(t, int64(2), msg.Deliveries)
+		require.NoError(t, q.Ack(ctx, topic, "group", msg.ID))
+	})
+
+	t.Run("moves dead letters to the dlq topic", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+		require.NoError(t, q.Publish(ctx, topic, []byte("poison")))
+
+		msg := receive(t, messages)
+		require.NoError(t, q.DeadLetter(ctx, topic, "group", msg, "save failed"))
+
+		letters, err := q.Subscribe(ctx, queue.DeadLetterTopic(topic), "inspect", "consumer")
+		require.NoError(t, err)
+
+		dl := receive(t, letters)
+		assert.Equal(t, "poison", string(dl.Payload))
+		assert.Equal(t, "save failed", dl.Headers[queue.ReasonHeader])
+		assert.Equal(t, msg.ID, dl.Headers[queue.SourceIDHeader])
+		assert.Equal(t, "1", dl.Headers[queue.DeliveriesHeader])
+		assert.NotEmpty(t, dl.Headers[queue.FailedAtHeader])
+		require.NoError(t, q.Ack(ctx, queue.DeadLetterTopic(topic), "inspect", dl.ID))
+
+		expectNone(t, messages)
+	})
+
+	t.Run("lists and replays dead letters", func(t *testing.T) {
+		ctx := subscribeContext(t)
+		topic := topicName(t)
+
+		messages, err := q.Subscribe(ctx, topic, "group", "consumer")
+		require.NoError(t, err)
+		for _, p := range []string{"first", "second"} {
+			require.NoError(t, q.Publish(ctx, topic, []byte(p)))
+			msg := receive(t, messages)
+			require.NoError(t, q.DeadLetter(ctx, topic, "group", msg, "save failed"))
+		}
+
+		letters, err := q.DeadLetters(ctx, topic, "", 1)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, "first", string(letters[0].Payload))
+		assert.Equal(t, "save failed", letters[0].Reason)
+		assert.NotEmpty(t, letters[0].SourceID)
+		assert.Equal(t, int64(1), letters[0].Deliveries)
+		assert.False(t, letters[0].FailedAt.IsZero())
+		assert.NotContains(t, letters[0].Headers, queue.ReasonHeader)
+
+		next, err := q.DeadLetters(ctx, topic, letters[0].ID, 10)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		assert.Equal(t, "second", string(next[0].Payload))
+
+		require.NoError(t, q.Replay(ctx, topic, letters[0].ID))
+
+		replayed := receive(t, messages)
+		assert.Equal(t, "first", string(replayed.Payload))
+		assert.NotContains(t, replayed.Headers, queue.ReasonHeader)
+		require.NoError(t, q.Ack(ctx, topic, "group", replayed.ID))
+
+		remaining, err := q.DeadLetters(ctx, topic, "", 10)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, next[0].ID, remaining[0].ID)
+
+		assert.ErrorIs(t, q.Replay(ctx, topic, letters[0].ID), queue.ErrDeadLetterNotFound)
+	})
+}
+
+func subscribeContext(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func topicName(t *testing.T) string {
+	return fmt.Sprintf("queuetest:%s:%d", strings.ReplaceAll(t.Name(), "/", ":"), time.Now().UnixNano())
+}
+
+func receive(t *testing.T, messages <-chan queue.Message) queue.Message {
+	t.Helper()
+
+	select {
+	case msg, ok := <-messages:
+		require.True(t, ok, "subscription closed")
+		return msg
+	case <-time.After(deliveryTimeout):
+		t.Fatal("timeout waiting for message")
+		return queue.Message{}
+	}
+}
+
+func expectNone(t *testing.T, messages <-chan queue.Message) {
+	t.Helper()
+
+	select {
+	case msg := <-messages:
+		t.Fatalf("unexpected delivery of %s (%q)", msg.ID, msg.Payload)
+	case <-time.After(quietPeriod):
+	}
+}
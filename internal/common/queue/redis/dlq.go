@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+)
+
+const reasonUndecodable = "undecodable entry"
+
+// replayScript republishes a dead letter only if this call removed it, so
+// concurrent replays of the same entry publish it once.
+var replayScript = redis.NewScript(`
+if redis.call('XDEL', KEYS[1], ARGV[1]) == 0 then
+	return false
+end
+return redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[2], '*', unpack(ARGV, 3))
+`)
+
+func (s *StreamsClient) DeadLetter(ctx context.Context, topic, group string, msg Message, reason string) error {
+	s.untrack(topic, group, msg.ID)
+
+	headers := queue.DeadLetterHeaders(msg, reason)
+	values := make(map[string]interface{}, len(headers)+1)
+	for k, v := range headers {
+		values[k] = v
+	}
+	values[payloadField] = msg.Payload
+
+	pipe := s.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: queue.DeadLetterTopic(topic),
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: values,
+	})
+	pipe.XAck(ctx, topic, group, msg.ID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("dead letter %s: %w", msg.ID, err)
+	}
+	return nil
+}
+
+// discard moves an entry without a payload straight to the dead letter
+// queue. Subscribe cannot hand it out, so left pending it would be claimed
+// and skipped forever. Entries trimmed from the stream are only acked.
+func (s *StreamsClient) discard(ctx context.Context, topic, group string, msg redis.XMessage, deliveries int64) {
+	if len(msg.Values) == 0 {
+		_ = s.client.XAck(ctx, topic, group, msg.ID).Err()
+		return
+	}
+
+	headers := make(map[string]string, len(msg.Values))
+	for k, v := range msg.Values {
+		if str, ok := v.(string); ok {
+			headers[k] = str
+		}
+	}
+
+	m := Message{ID: msg.ID, Headers: headers, Deliveries: deliveries}
+	_ = s.DeadLetter(ctx, topic, group, m, reasonUndecodable)
+}
+
+func (s *StreamsClient) DeadLetters(ctx context.Context, topic, after string, count int64) ([]queue.DeadLetter, error) {
+	start := "-"
+	if after != "" {
+		if err := validateID(after); err != nil {
+			return nil, err
+		}
+		start = "(" + after
+	}
+
+	msgs, err := s.client.XRangeN(ctx, queue.DeadLetterTopic(topic), start, "+", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("xrange %s: %w", queue.DeadLetterTopic(topic), err)
+	}
+
+	letters := make([]queue.DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		if dl, ok := toDeadLetter(msg); ok {
+			letters = append(letters, dl)
+		}
+	}
+	return letters, nil
+}
+
+func (s *StreamsClient) Replay(ctx context.Context, topic, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	dlq := queue.DeadLetterTopic(topic)
+
+	msgs, err := s.client.XRange(ctx, dlq, id, id).Result()
+	if err != nil {
+		return fmt.Errorf("xrange %s: %w", dlq, err)
+	}
+	// a bare millisecond id ranges over every entry of that millisecond
+	if len(msgs) == 0 || msgs[0].ID != id {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+
+	dl, ok := toDeadLetter(msgs[0])
+	if !ok {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+
+	args := make([]interface{}, 0, 2*len(dl.Headers)+4)
+	args = append(args, id, s.maxLen)
+	for k, v := range dl.Headers {
+		args = append(args, k, v)
+	}
+	args = append(args, payloadField, dl.Payload)
+
+	err = replayScript.Run(ctx, s.client, []string{dlq, topic}, args...).Err()
+	if err == redis.Nil {
+		return fmt.Errorf("%s: %w", id, queue.ErrDeadLetterNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("replay %s: %w", id, err)
+	}
+	return nil
+}
+
+// validateID checks id is a stream entry id Redis accepts: milliseconds and
+// an optional sequence, both unsigned 64-bit.
+func validateID(id string) error {
+	ms, seq, hasSeq := strings.Cut(id, "-")
+	if _, err := strconv.ParseUint(ms, 10, 64); err != nil {
+		return fmt.Errorf("message id %q: %w", id, queue.ErrInvalidID)
+	}
+	if hasSeq {
+		if _, err := strconv.ParseUint(seq, 10, 64); err != nil {
+			return fmt.Errorf("message id %q: %w", id, queue.ErrInvalidID)
+		}
+	}
+	return nil
+}
+
+func toDeadLetter(msg redis.XMessage) (queue.DeadLetter, bool) {
+	m, ok := toMessage(msg)
+	if !ok {
+		return queue.DeadLetter{}, false
+	}
+	return queue.NewDeadLetter(m.ID, m.Payload, m.Headers), true
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// inflightEntry records which subscription holds a delivered entry, so the
+// retry pass and the reclaimer leave work in progress alone.
+type inflightEntry struct {
+	id       string
+	consumer string
+	sub      uint64
+}
+
+func inflightKey(topic, group, id string) string {
+	return topic + "\x00" + group + "\x00" + id
+}
+
+// deliver hands m to the subscriber and holds it in flight until it is
+// acked, nacked or dead-lettered.
+func (s *StreamsClient) deliver(ctx context.Context, topic, group, consumer string, sub uint64, m Message, messages chan<- Message) bool {
+	key := inflightKey(topic, group, m.ID)
+
+	s.mu.Lock()
+	s.inflight[key] = inflightEntry{id: m.ID, consumer: consumer, sub: sub}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.untrack(topic, group, m.ID)
+		return false
+	case messages <- m:
+		return true
+	}
+}
+
+func (s *StreamsClient) held(topic, group, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.inflight[inflightKey(topic, group, id)]
+	return ok
+}
+
+func (s *StreamsClient) untrack(topic, group, id string) (inflightEntry, bool) {
+	key := inflightKey(topic, group, id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.inflight[key]
+	delete(s.inflight, key)
+	return e, ok
+}
+
+// release drops the entries a finished subscription still held, leaving
+// them pending for the next subscriber.
+func (s *StreamsClient) release(sub uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, e := range s.inflight {
+		if e.sub == sub {
+			delete(s.inflight, key)
+		}
+	}
+}
+
+// keepAlive touches the entries sub holds well within the claim threshold,
+// on its own ticker so a subscriber blocked on delivery keeps them too.
+func (s *StreamsClient) keepAlive(ctx context.Context, topic, group, consumer string, sub uint64) {
+	interval := s.retryDelay / 2
+	if s.claimIdle > 0 && s.claimIdle/3 < interval {
+		interval = s.claimIdle / 3
+	}
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.touch(ctx, topic, group, consumer, sub)
+		}
+	}
+}
+
+// touch resets the idle time of the entries sub still holds. XCLAIM with
+// JUSTID does not bump the delivery count, and keeps the reclaimers of other
+// consumers from taking over work in progress.
+func (s *StreamsClient) touch(ctx context.Context, topic, group, consumer string, sub uint64) {
+	var ids []string
+
+	s.mu.Lock()
+	for _, e := range s.inflight {
+		if e.sub == sub {
+			ids = append(ids, e.id)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(ids) == 0 {
+		return
+	}
+
+	_ = s.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   topic,
+		Group:    group,
+		Consumer: consumer,
+		Messages: ids,
+	}).Err()
+}
+
+// Nack hands a delivered entry back for redelivery once the retry delay has
+// passed since the failure.
+func (s *StreamsClient) Nack(ctx context.Context, topic, group, msgID string) error {
+	e, ok := s.untrack(topic, group, msgID)
+	if !ok {
+		return nil
+	}
+
+	err := s.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   topic,
+		Group:    group,
+		Consumer: e.consumer,
+		Messages: []string{msgID},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("nack %s: %w", msgID, err)
+	}
+	return nil
+}
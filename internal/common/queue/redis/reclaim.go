@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+// WithClaimIdle enables the background reclaimer: entries left pending by any
+// consumer of the group for longer than idle are taken over by the consumer
+// that subscribed. Zero disables it; otherwise idle must exceed the retry
+// delay, or failed entries would be reclaimed before they are retried.
+func (s *StreamsClient) WithClaimIdle(idle time.Duration) *StreamsClient {
+	s.claimIdle = idle
+	return s
+}
+
+// WithConsumerTTL makes the reclaimer drop consumers that have been idle for
+// longer than ttl and own no pending entries. Zero keeps them forever.
+func (s *StreamsClient) WithConsumerTTL(ttl time.Duration) *StreamsClient {
+	s.consumerTTL = ttl
+	return s
+}
+
+func (s *StreamsClient) runReclaimer(ctx context.Context, topic, group, consumer string, sub uint64, messages chan<- Message) {
+	ticker := time.NewTicker(s.claimIdle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.reclaim(ctx, topic, group, consumer, sub, messages)
+		if s.consumerTTL > 0 {
+			_, _ = s.pruneConsumers(ctx, topic, group, consumer)
+		}
+	}
+}
+
+// reclaim takes over entries any consumer of the group left idle past the
+// claim threshold. Entries this client still holds in flight are skipped
+// before claiming, so their delivery count is not bumped.
+func (s *StreamsClient) reclaim(ctx context.Context, topic, group, consumer string, sub uint64, messages chan<- Message) {
+	s.claimPending(ctx, topic, group, "", consumer, sub, s.claimIdle, messages)
+}
+
+func (s *StreamsClient) pruneConsumers(ctx context.Context, topic, group, self string) (int, error) {
+	consumers, err := s.client.XInfoConsumers(ctx, topic, group).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, c := range consumers {
+		if c.Name == self || c.Pending > 0 || c.Idle < s.consumerTTL {
+			continue
+		}
+		if err := s.client.XGroupDelConsumer(ctx, topic, group, c.Name).Err(); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -3,31 +3,49 @@ package redis
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
 )
 
 const (
 	defaultMaxLen     = 1000000
 	errorBackoff      = 1 * time.Second
 	readBlockDuration = 5 * time.Second
+	pendingBatch      = 100
+	payloadField      = "payload"
+	tracerName        = "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
 )
 
-type Message struct {
-	ID      string
-	Payload []byte
-}
+type Message = queue.Message
 
 type StreamsClient struct {
-	client *redis.Client
-	maxLen int64
+	client      *redis.Client
+	maxLen      int64
+	retryDelay  time.Duration
+	claimIdle   time.Duration
+	consumerTTL time.Duration
+
+	subs     atomic.Uint64
+	mu       sync.Mutex
+	inflight map[string]inflightEntry
 }
 
 func NewStreamsClient(client *redis.Client) *StreamsClient {
 	return &StreamsClient{
-		client: client,
-		maxLen: defaultMaxLen,
+		client:     client,
+		maxLen:     defaultMaxLen,
+		retryDelay: queue.DefaultRetryDelay,
+		inflight:   make(map[string]inflightEntry),
 	}
 }
 
@@ -36,18 +54,43 @@ func (s *StreamsClient) WithMaxLen(maxLen int64) *StreamsClient {
 	return s
 }
 
+func (s *StreamsClient) WithRetryDelay(delay time.Duration) *StreamsClient {
+	s.retryDelay = delay
+	return s
+}
+
 func (s *StreamsClient) Publish(ctx context.Context, topic string, payload []byte) error {
-	_, err := s.client.XAdd(ctx, &redis.XAddArgs{
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "queue.publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "redis"),
+			attribute.String("messaging.destination.name", topic),
+		),
+	)
+	defer span.End()
+
+	headers := make(map[string]string)
+	tracing.Inject(ctx, headers)
+
+	values := make(map[string]interface{}, len(headers)+1)
+	for k, v := range headers {
+		values[k] = v
+	}
+	values[payloadField] = payload
+
+	id, err := s.client.XAdd(ctx, &redis.XAddArgs{
 		Stream: topic,
 		MaxLen: s.maxLen,
 		Approx: true,
-		Values: map[string]interface{}{
-			"payload": payload,
-		},
+		Values: values,
 	}).Result()
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("xadd %s: %w", topic, err)
 	}
+
+	span.SetAttributes(attribute.String("messaging.message.id", id))
 	return nil
 }
 
@@ -58,63 +101,126 @@ func (s *StreamsClient) Subscribe(ctx context.Context, topic, group, consumer st
 	}
 
 	messages := make(chan Message)
+	sub := s.subs.Add(1)
+
+	// a blocked XREADGROUP only notices the cancellation once it returns, so
+	// the held entries are released right away for the next subscriber
+	context.AfterFunc(ctx, func() { s.release(sub) })
 
 	go func() {
 		defer close(messages)
 
-		s.recoverPending(ctx, topic, group, consumer, messages)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.keepAlive(ctx, topic, group, consumer, sub)
+		}()
 
-		s.consumeLive(ctx, topic, group, consumer, messages)
+		if s.claimIdle > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.runReclaimer(ctx, topic, group, consumer, sub, messages)
+			}()
+		}
+
+		s.redeliver(ctx, topic, group, consumer, sub, 0, messages)
+
+		s.consumeLive(ctx, topic, group, consumer, sub, messages)
+		wg.Wait()
 	}()
 
 	return messages, nil
 }
 
-func (s *StreamsClient) recoverPending(ctx context.Context, topic, group, consumer string, messages chan<- Message) {
+// redeliver re-reads this consumer's own unacked entries that have been idle
+// for at least minIdle, skipping the ones still in flight. Claiming them
+// again bumps their delivery count, which is what retry policies built on top
+// of Subscribe rely on.
+func (s *StreamsClient) redeliver(ctx context.Context, topic, group, consumer string, sub uint64, minIdle time.Duration, messages chan<- Message) {
+	s.claimPending(ctx, topic, group, consumer, consumer, sub, minIdle, messages)
+}
+
+// claimPending claims the entries owner left pending for at least minIdle,
+// or those of any consumer when owner is empty, and delivers them to
+// consumer. Entries still in flight are listed but never claimed, so their
+// delivery count is left alone.
+func (s *StreamsClient) claimPending(ctx context.Context, topic, group, owner, consumer string, sub uint64, minIdle time.Duration, messages chan<- Message) {
+	start := "-"
 	for {
 		if ctx.Err() != nil {
 			return
 		}
 
-		result, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream:   topic,
 			Group:    group,
-			Consumer: consumer,
-			Streams:  []string{topic, "0"},
-			Count:    100,
+			Idle:     minIdle,
+			Start:    start,
+			End:      "+",
+			Count:    pendingBatch,
+			Consumer: owner,
 		}).Result()
-
-		if err != nil {
-			time.Sleep(errorBackoff)
-			continue
+		if err != nil || len(pending) == 0 {
+			return
 		}
 
-		if len(result) == 0 || len(result[0].Messages) == 0 {
-			return
+		ids := make([]string, 0, len(pending))
+		deliveries := make(map[string]int64, len(pending))
+		for _, p := range pending {
+			if s.held(topic, group, p.ID) {
+				continue
+			}
+			ids = append(ids, p.ID)
+			deliveries[p.ID] = p.RetryCount
 		}
 
-		for _, stream := range result {
-			for _, msg := range stream.Messages {
-				payload, ok := msg.Values["payload"].(string)
+		if len(ids) > 0 {
+			claimed, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   topic,
+				Group:    group,
+				Consumer: consumer,
+				MinIdle:  minIdle,
+				Messages: ids,
+			}).Result()
+			if err != nil {
+				return
+			}
+
+			for _, msg := range claimed {
+				m, ok := toMessage(msg)
 				if !ok {
+					s.discard(ctx, topic, group, msg, deliveries[msg.ID]+1)
 					continue
 				}
+				m.Deliveries = deliveries[msg.ID] + 1
 
-				select {
-				case <-ctx.Done():
+				if !s.deliver(ctx, topic, group, consumer, sub, m, messages) {
 					return
-				case messages <- Message{ID: msg.ID, Payload: []byte(payload)}:
 				}
 			}
 		}
+
+		if len(pending) < pendingBatch {
+			return
+		}
+		start = "(" + pending[len(pending)-1].ID
 	}
 }
 
-func (s *StreamsClient) consumeLive(ctx context.Context, topic, group, consumer string, messages chan<- Message) {
+func (s *StreamsClient) consumeLive(ctx context.Context, topic, group, consumer string, sub uint64, messages chan<- Message) {
+	lastRetry := time.Now()
 	for {
 		if ctx.Err() != nil {
 			return
 		}
 
+		if time.Since(lastRetry) >= s.retryDelay {
+			s.redeliver(ctx, topic, group, consumer, sub, s.retryDelay, messages)
+			lastRetry = time.Now()
+		}
+
 		result, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 			Group:    group,
 			Consumer: consumer,
@@ -135,22 +241,43 @@ func (s *StreamsClient) consumeLive(ctx context.Context, topic, group, consumer
 
 		for _, stream := range result {
 			for _, msg := range stream.Messages {
-				payload, ok := msg.Values["payload"].(string)
+				m, ok := toMessage(msg)
 				if !ok {
+					s.discard(ctx, topic, group, msg, 1)
 					continue
 				}
+				m.Deliveries = 1
 
-				select {
-				case <-ctx.Done():
+				if !s.deliver(ctx, topic, group, consumer, sub, m, messages) {
 					return
-				case messages <- Message{ID: msg.ID, Payload: []byte(payload)}:
 				}
 			}
 		}
 	}
 }
 
+func toMessage(msg redis.XMessage) (Message, bool) {
+	payload, ok := msg.Values[payloadField].(string)
+	if !ok {
+		return Message{}, false
+	}
+
+	headers := make(map[string]string, len(msg.Values)-1)
+	for k, v := range msg.Values {
+		if k == payloadField {
+			continue
+		}
+		if str, ok := v.(string); ok {
+			headers[k] = str
+		}
+	}
+
+	return Message{ID: msg.ID, Payload: []byte(payload), Headers: headers}, true
+}
+
 func (s *StreamsClient) Ack(ctx context.Context, topic, group, msgID string) error {
+	s.untrack(topic, group, msgID)
+
 	_, err := s.client.XAck(ctx, topic, group, msgID).Result()
 	if err != nil {
 		return fmt.Errorf("xack %s: %w", msgID, err)
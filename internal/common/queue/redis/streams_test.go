@@ -2,6 +2,7 @@ package redis_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -9,8 +10,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue/queuetest"
 	queueredis "github.com/JulianoL13/app-proxy-engine/internal/common/queue/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
 )
 
 func TestStreamsClient_Publish(t *testing.T) {
@@ -115,6 +121,7 @@ func TestStreamsClient_Subscribe(t *testing.T) {
 		select {
 		case msg := <-messages:
 			assert.Equal(t, `{"pending":"msg"}`, string(msg.Payload))
+			assert.Equal(t, int64(2), msg.Deliveries)
 			err = streams.Ack(ctx, topic, group, msg.ID)
 			assert.NoError(t, err)
 		case <-secondCtx.Done():
@@ -122,3 +129,337 @@ func TestStreamsClient_Subscribe(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamsClient_Retry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	streams := queueredis.NewStreamsClient(client).WithRetryDelay(100 * time.Millisecond)
+
+	t.Run("redelivers nacked messages with a growing delivery count", func(t *testing.T) {
+		topic := "retry-test"
+		group := "retry-group"
+
+		subCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
+
+		messages, err := streams.Subscribe(subCtx, topic, group, "retry-consumer")
+		require.NoError(t, err)
+		require.NoError(t, streams.Publish(ctx, topic, []byte(`{"retry":"me"}`)))
+
+		for want := int64(1); want <= 2; want++ {
+			select {
+			case msg := <-messages:
+				assert.Equal(t, want, msg.Deliveries)
+				if want == 2 {
+					require.NoError(t, streams.Ack(ctx, topic, group, msg.ID))
+				} else {
+					require.NoError(t, streams.Nack(ctx, topic, group, msg.ID))
+				}
+			case <-subCtx.Done():
+				t.Fatalf("timeout waiting for delivery %d", want)
+			}
+		}
+	})
+
+	t.Run("moves messages to the dead letter stream and replays them", func(t *testing.T) {
+		topic := "dlq-test"
+		group := "dlq-group"
+
+		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		messages, err := streams.Subscribe(subCtx, topic, group, "dlq-consumer")
+		require.NoError(t, err)
+		require.NoError(t, streams.Publish(ctx, topic, []byte(`{"bad":"payload"}`)))
+
+		var msg queueredis.Message
+		select {
+		case msg = <-messages:
+		case <-subCtx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+
+		require.NoError(t, streams.DeadLetter(ctx, topic, group, msg, "save failed"))
+
+		pending, err := client.XPending(ctx, topic, group).Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), pending.Count)
+
+		letters, err := streams.DeadLetters(ctx, topic, "", 10)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, msg.ID, letters[0].SourceID)
+		assert.Equal(t, "save failed", letters[0].Reason)
+		assert.Equal(t, int64(1), letters[0].Deliveries)
+		assert.Equal(t, `{"bad":"payload"}`, string(letters[0].Payload))
+		assert.False(t, letters[0].FailedAt.IsZero())
+
+		require.NoError(t, streams.Replay(ctx, topic, letters[0].ID))
+
+		select {
+		case replayed := <-messages:
+			assert.Equal(t, `{"bad":"payload"}`, string(replayed.Payload))
+			assert.NotContains(t, replayed.Headers, "dlq_reason")
+		case <-subCtx.Done():
+			t.Fatal("timeout waiting for replayed message")
+		}
+
+		letters, err = streams.DeadLetters(ctx, topic, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+
+		err = streams.Replay(ctx, topic, "0-1")
+		assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+		err = streams.Replay(ctx, topic, "99999999999999999999-0")
+		assert.ErrorIs(t, err, queue.ErrInvalidID)
+
+		_, err = streams.DeadLetters(ctx, topic, "1-99999999999999999999", 10)
+		assert.ErrorIs(t, err, queue.ErrInvalidID)
+	})
+
+	t.Run("replays a dead letter once under concurrent replays", func(t *testing.T) {
+		topic := "dlq-race-test"
+		group := "dlq-race-group"
+
+		require.NoError(t, client.XGroupCreateMkStream(ctx, topic, group, "$").Err())
+		require.NoError(t, streams.DeadLetter(ctx, topic, group, queueredis.Message{ID: "1-1", Payload: []byte("once")}, "failed"))
+
+		letters, err := streams.DeadLetters(ctx, topic, "", 10)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = streams.Replay(ctx, topic, letters[0].ID)
+			}(i)
+		}
+		wg.Wait()
+
+		replayed := 0
+		for _, err := range errs {
+			if err == nil {
+				replayed++
+			} else {
+				assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+			}
+		}
+		assert.Equal(t, 1, replayed)
+
+		length, err := client.XLen(ctx, topic).Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), length)
+	})
+
+	t.Run("dead-letters entries without a payload", func(t *testing.T) {
+		topic := "undecodable-test"
+		group := "undecodable-group"
+
+		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		_, err := streams.Subscribe(subCtx, topic, group, "undecodable-consumer")
+		require.NoError(t, err)
+		require.NoError(t, client.XAdd(ctx, &goredis.XAddArgs{Stream: topic, Values: map[string]interface{}{"other": "field"}}).Err())
+
+		require.Eventually(t, func() bool {
+			letters, err := streams.DeadLetters(ctx, topic, "", 10)
+			return err == nil && len(letters) == 1 && letters[0].Reason == "undecodable entry"
+		}, 5*time.Second, 50*time.Millisecond)
+
+		pending, err := client.XPending(ctx, topic, group).Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), pending.Count)
+	})
+}
+
+func TestStreamsClient_Reclaim(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	t.Run("takes over entries of a dead consumer and removes it", func(t *testing.T) {
+		topic := "reclaim-test"
+		group := "reclaim-group"
+
+		require.NoError(t, queueredis.NewStreamsClient(client).Publish(ctx, topic, []byte(`{"orphan":"msg"}`)))
+
+		deadCtx, deadCancel := context.WithTimeout(ctx, 2*time.Second)
+		messages, err := queueredis.NewStreamsClient(client).Subscribe(deadCtx, topic, group, "dead-consumer")
+		require.NoError(t, err)
+
+		select {
+		case <-messages:
+		case <-deadCtx.Done():
+			t.Fatal("timeout on first delivery")
+		}
+		deadCancel()
+
+		streams := queueredis.NewStreamsClient(client).
+			WithClaimIdle(200 * time.Millisecond).
+			WithConsumerTTL(200 * time.Millisecond)
+
+		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		messages, err = streams.Subscribe(subCtx, topic, group, "live-consumer")
+		require.NoError(t, err)
+
+		select {
+		case msg := <-messages:
+			assert.Equal(t, `{"orphan":"msg"}`, string(msg.Payload))
+			assert.Equal(t, int64(2), msg.Deliveries)
+			require.NoError(t, streams.Ack(ctx, topic, group, msg.ID))
+		case <-subCtx.Done():
+			t.Fatal("timeout waiting for reclaimed message")
+		}
+
+		assert.Eventually(t, func() bool {
+			consumers, err := client.XInfoConsumers(ctx, topic, group).Result()
+			if err != nil {
+				return false
+			}
+			for _, c := range consumers {
+				if c.Name == "dead-consumer" {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("keeps entries in flight without bumping their delivery count", func(t *testing.T) {
+		topic := "held-test"
+		group := "held-group"
+
+		streams := queueredis.NewStreamsClient(client).
+			WithRetryDelay(time.Second).
+			WithClaimIdle(300 * time.Millisecond)
+
+		subCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		messages, err := streams.Subscribe(subCtx, topic, group, "held-consumer")
+		require.NoError(t, err)
+		require.NoError(t, streams.Publish(ctx, topic, []byte(`{"slow":"work"}`)))
+
+		var msg queueredis.Message
+		select {
+		case msg = <-messages:
+		case <-subCtx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+
+		// the subscriber does not read further while the message is worked on
+		time.Sleep(1500 * time.Millisecond)
+
+		pending, err := client.XPendingExt(ctx, &goredis.XPendingExtArgs{
+			Stream: topic, Group: group, Start: msg.ID, End: msg.ID, Count: 1,
+		}).Result()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, int64(1), pending[0].RetryCount)
+		assert.Less(t, pending[0].Idle, 300*time.Millisecond)
+
+		require.NoError(t, streams.Ack(ctx, topic, group, msg.ID))
+	})
+}
+
+func TestStreamsClient_Conformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	queuetest.Run(t, queueredis.NewStreamsClient(client).WithRetryDelay(queuetest.RetryDelay))
+}
+
+func TestStreamsClient_TracePropagation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	shutdown, err := tracing.Setup(ctx, tracing.Config{ServiceName: "test", Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	defer func() { _ = shutdown(ctx) }()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	streams := queueredis.NewStreamsClient(client)
+
+	t.Run("carries trace context next to payload", func(t *testing.T) {
+		topic := "trace-test"
+
+		spanCtx, span := otel.Tracer("test").Start(ctx, "cycle")
+		defer span.End()
+
+		subCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		messages, err := streams.Subscribe(subCtx, topic, "trace-group", "trace-consumer")
+		require.NoError(t, err)
+
+		err = streams.Publish(spanCtx, topic, []byte(`{}`))
+		require.NoError(t, err)
+
+		select {
+		case msg := <-messages:
+			assert.Contains(t, msg.Headers, "traceparent")
+			remote := trace.SpanContextFromContext(tracing.Extract(ctx, msg.Headers))
+			assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+		case <-subCtx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+	})
+}
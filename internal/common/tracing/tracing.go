@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+const (
+	ExporterOTLP = "otlp"
+	ExporterNone = "none"
+)
+
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+type Config struct {
+	ServiceName string
+	Exporter    string
+	SampleRatio float64
+}
+
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		exporter = NoopExporter{}
+	case ExporterOTLP:
+		otlp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		exporter = otlp
+	default:
+		return nil, fmt.Errorf("%q: %w", cfg.Exporter, ErrUnknownExporter)
+	}
+
+	provider := NewProvider(exporter, cfg.ServiceName, cfg.SampleRatio)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+func NewProvider(exporter sdktrace.SpanExporter, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+}
+
+type NoopExporter struct{}
+
+func (NoopExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return nil
+}
+
+func (NoopExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func Inject(ctx context.Context, fields map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(fields))
+}
+
+func Extract(ctx context.Context, fields map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(fields))
+}
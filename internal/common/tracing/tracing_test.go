@@ -0,0 +1,45 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tracing"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects unknown exporter", func(t *testing.T) {
+		_, err := tracing.Setup(ctx, tracing.Config{ServiceName: "test", Exporter: "zipkin"})
+		assert.ErrorIs(t, err, tracing.ErrUnknownExporter)
+	})
+
+	t.Run("round trips trace context through fields", func(t *testing.T) {
+		shutdown, err := tracing.Setup(ctx, tracing.Config{ServiceName: "test", Exporter: tracing.ExporterNone})
+		require.NoError(t, err)
+		defer func() { _ = shutdown(ctx) }()
+
+		spanCtx, span := otel.Tracer("test").Start(ctx, "parent")
+		defer span.End()
+
+		fields := make(map[string]string)
+		tracing.Inject(spanCtx, fields)
+		assert.Contains(t, fields, "traceparent")
+
+		remote := trace.SpanContextFromContext(tracing.Extract(ctx, fields))
+		assert.True(t, remote.IsRemote())
+		assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+		assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+	})
+
+	t.Run("extract without fields yields no span context", func(t *testing.T) {
+		remote := trace.SpanContextFromContext(tracing.Extract(ctx, nil))
+		assert.False(t, remote.IsValid())
+	})
+}
@@ -0,0 +1,109 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	socks4Version    = 0x04
+	socks4CmdConnect = 0x01
+	socks4Granted    = 0x5A
+)
+
+// SOCKS4Dialer speaks SOCKS4, falling back to SOCKS4a for hostnames. It is
+// also usable as an http.Transport DialContext.
+type SOCKS4Dialer struct {
+	proxyAddr string
+	userID    string
+	timeout   time.Duration
+}
+
+func NewSOCKS4Dialer(proxyAddr, userID string, timeout time.Duration) *SOCKS4Dialer {
+	return &SOCKS4Dialer{
+		proxyAddr: proxyAddr,
+		userID:    userID,
+		timeout:   timeout,
+	}
+}
+
+func (d *SOCKS4Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	req, err := d.buildRequest(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: d.timeout}
+	conn, err := dialer.DialContext(ctx, network, d.proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := d.handshake(conn, req); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (d *SOCKS4Dialer) buildRequest(address string) ([]byte, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("socks4 target %q: %w", address, err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("socks4 target %q: invalid port", address)
+	}
+
+	req := []byte{socks4Version, socks4CmdConnect, 0, 0}
+	binary.BigEndian.PutUint16(req[2:], uint16(port))
+
+	ip := net.ParseIP(host)
+	switch {
+	case ip != nil && ip.To4() != nil:
+		req = append(req, ip.To4()...)
+		req = append(req, d.userID...)
+		req = append(req, 0)
+	case ip != nil:
+		return nil, fmt.Errorf("socks4 target %q: ipv6 not supported", address)
+	default:
+		// SOCKS4a: an invalid 0.0.0.x address tells the proxy to resolve the domain itself
+		req = append(req, 0, 0, 0, 1)
+		req = append(req, d.userID...)
+		req = append(req, 0)
+		req = append(req, host...)
+		req = append(req, 0)
+	}
+
+	return req, nil
+}
+
+func (d *SOCKS4Dialer) handshake(conn net.Conn, req []byte) error {
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("socks4 write request: %w", err)
+	}
+
+	reply := make([]byte, 8)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("socks4 read reply: %w", err)
+	}
+
+	if reply[1] != socks4Granted {
+		return fmt.Errorf("socks4 request rejected with code 0x%02x", reply[1])
+	}
+
+	return nil
+}
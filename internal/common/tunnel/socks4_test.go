@@ -0,0 +1,153 @@
+package tunnel_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+)
+
+const socks4Granted = 0x5A
+
+type socks4Request struct {
+	target string
+	userID string
+}
+
+type socks4Server struct {
+	addr     string
+	reply    byte
+	mu       sync.Mutex
+	requests []socks4Request
+}
+
+func newSOCKS4Server(t *testing.T, reply byte) *socks4Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &socks4Server{addr: ln.Addr().String(), reply: reply}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *socks4Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	userID, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	host := net.IP(header[4:8]).String()
+	if header[4] == 0 && header[5] == 0 && header[6] == 0 && header[7] != 0 {
+		domain, err := r.ReadString(0)
+		if err != nil {
+			return
+		}
+		host = domain[:len(domain)-1]
+	}
+	target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(header[2:4]))))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, socks4Request{target: target, userID: userID[:len(userID)-1]})
+	s.mu.Unlock()
+
+	if s.reply != socks4Granted {
+		_, _ = conn.Write([]byte{0, s.reply, 0, 0, 0, 0, 0, 0})
+		return
+	}
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_, _ = conn.Write([]byte{0, 0x5B, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	_, _ = conn.Write([]byte{0, socks4Granted, 0, 0, 0, 0, 0, 0})
+
+	go func() { _, _ = io.Copy(upstream, r) }()
+	_, _ = io.Copy(conn, upstream)
+}
+
+func (s *socks4Server) received() []socks4Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]socks4Request(nil), s.requests...)
+}
+
+func TestSOCKS4Dialer(t *testing.T) {
+	target := newEchoServer(t)
+	_, port, err := net.SplitHostPort(target)
+	require.NoError(t, err)
+
+	t.Run("sends domain for socks4a", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, socks4Granted)
+		dialer := tunnel.NewSOCKS4Dialer(proxySrv.addr, "", time.Second)
+
+		conn, err := dialer.DialContext(context.Background(), "tcp", net.JoinHostPort("localhost", port))
+		require.NoError(t, err)
+		conn.Close()
+
+		requests := proxySrv.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, net.JoinHostPort("localhost", port), requests[0].target)
+	})
+
+	t.Run("sends user id", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, socks4Granted)
+		dialer := tunnel.NewSOCKS4Dialer(proxySrv.addr, "alice", time.Second)
+
+		conn, err := dialer.DialContext(context.Background(), "tcp", target)
+		require.NoError(t, err)
+		conn.Close()
+
+		requests := proxySrv.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "alice", requests[0].userID)
+	})
+
+	t.Run("fails when the proxy rejects the request", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, 0x5B)
+
+		_, err := tunnel.Dial(context.Background(), &url.URL{Scheme: "socks4", Host: proxySrv.addr}, target, time.Second)
+		assert.ErrorContains(t, err, "rejected")
+	})
+
+	t.Run("rejects ipv6 targets", func(t *testing.T) {
+		dialer := tunnel.NewSOCKS4Dialer("127.0.0.1:1", "", time.Second)
+
+		_, err := dialer.DialContext(context.Background(), "tcp", "[::1]:80")
+		assert.Error(t, err)
+	})
+}
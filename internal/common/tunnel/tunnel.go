@@ -0,0 +1,112 @@
+// Package tunnel opens raw TCP tunnels through an upstream proxy, the common
+// ground between the gateway and the verifier's CONNECT probes.
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+var ErrUnsupportedScheme = errors.New("unsupported proxy scheme")
+
+// Dial connects to target through the proxy described by proxyURL, picking
+// the handshake from its scheme: CONNECT for http and https, SOCKS4(a) or
+// SOCKS5 otherwise.
+func Dial(ctx context.Context, proxyURL *url.URL, target string, timeout time.Duration) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "http", "https":
+		return dialConnect(ctx, proxyURL, target, timeout)
+	case "socks4":
+		return NewSOCKS4Dialer(proxyURL.Host, proxyURL.User.Username(), timeout).DialContext(ctx, "tcp", target)
+	case "socks5":
+		return dialSOCKS5(ctx, proxyURL, target, timeout)
+	default:
+		return nil, fmt.Errorf("%s: %w", proxyURL.Scheme, ErrUnsupportedScheme)
+	}
+}
+
+func dialConnect(ctx context.Context, proxyURL *url.URL, target string, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("dial proxy: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	req.Header.Set("User-Agent", "ProxyEngine/1.0")
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write connect: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read connect: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("connect status %d", resp.StatusCode)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: br}, nil
+	}
+	return conn, nil
+}
+
+func dialSOCKS5(ctx context.Context, proxyURL *url.URL, target string, timeout time.Duration) (net.Conn, error) {
+	var auth *xproxy.Auth
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth = &xproxy.Auth{User: proxyURL.User.Username(), Password: password}
+	}
+
+	dialer, err := xproxy.SOCKS5("tcp", proxyURL.Host, auth, &net.Dialer{Timeout: timeout})
+	if err != nil {
+		return nil, fmt.Errorf("socks5: %w", err)
+	}
+
+	conn, err := dialer.(xproxy.ContextDialer).DialContext(ctx, "tcp", target)
+	if err != nil {
+		return nil, fmt.Errorf("socks5 dial: %w", err)
+	}
+	return conn, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
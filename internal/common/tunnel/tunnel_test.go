@@ -0,0 +1,139 @@
+package tunnel_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+)
+
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+// newConnectProxy answers CONNECT requests with status and records the
+// Proxy-Authorization header it saw.
+func newConnectProxy(t *testing.T, status int) (string, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	auth := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+
+				br := bufio.NewReader(conn)
+				req, err := http.ReadRequest(br)
+				if err != nil {
+					return
+				}
+				auth <- req.Header.Get("Proxy-Authorization")
+
+				if status != http.StatusOK {
+					resp := &http.Response{StatusCode: status, ProtoMajor: 1, ProtoMinor: 1}
+					_ = resp.Write(conn)
+					return
+				}
+
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+
+				_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+				go func() { _, _ = io.Copy(upstream, br) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+
+	return ln.Addr().String(), auth
+}
+
+func roundTrip(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+	_, err := conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestDial(t *testing.T) {
+	target := newEchoServer(t)
+	ctx := context.Background()
+
+	t.Run("tunnels through http connect with credentials", func(t *testing.T) {
+		addr, auth := newConnectProxy(t, http.StatusOK)
+
+		conn, err := tunnel.Dial(ctx, &url.URL{Scheme: "http", Host: addr, User: url.UserPassword("alice", "s3cret")}, target, time.Second)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		roundTrip(t, conn)
+		assert.Equal(t, "Basic YWxpY2U6czNjcmV0", <-auth)
+	})
+
+	t.Run("fails when connect is refused", func(t *testing.T) {
+		addr, _ := newConnectProxy(t, http.StatusForbidden)
+
+		_, err := tunnel.Dial(ctx, &url.URL{Scheme: "https", Host: addr}, target, time.Second)
+		assert.ErrorContains(t, err, "connect status 403")
+	})
+
+	t.Run("tunnels through socks4", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, socks4Granted)
+
+		conn, err := tunnel.Dial(ctx, &url.URL{Scheme: "socks4", Host: proxySrv.addr}, target, time.Second)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		roundTrip(t, conn)
+	})
+
+	t.Run("rejects unknown schemes", func(t *testing.T) {
+		_, err := tunnel.Dial(ctx, &url.URL{Scheme: "ftp", Host: "127.0.0.1:1"}, target, time.Second)
+		assert.ErrorIs(t, err, tunnel.ErrUnsupportedScheme)
+	})
+}
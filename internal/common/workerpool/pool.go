@@ -3,12 +3,14 @@ package workerpool
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/panjf2000/ants/v2"
 )
 
 type Pool struct {
 	pool *ants.Pool
+	busy atomic.Int64
 }
 
 func New(size int) (*Pool, error) {
@@ -24,6 +26,8 @@ func (p *Pool) Submit(ctx context.Context, job func(ctx context.Context)) error
 		if ctx.Err() != nil {
 			return
 		}
+		p.busy.Add(1)
+		defer p.busy.Add(-1)
 		job(ctx)
 	})
 
@@ -40,3 +44,11 @@ func (p *Pool) Stop() {
 func (p *Pool) Workers() int {
 	return p.pool.Cap()
 }
+
+func (p *Pool) Running() int {
+	return int(p.busy.Load())
+}
+
+func (p *Pool) Waiting() int {
+	return p.pool.Waiting()
+}
@@ -95,3 +95,32 @@ func TestPool_Stop(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestPool_Saturation(t *testing.T) {
+	pool, err := workerpool.New(1)
+	require.NoError(t, err)
+	defer pool.Stop()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	require.NoError(t, pool.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	assert.Equal(t, 1, pool.Running())
+
+	submitted := make(chan struct{})
+	go func() {
+		_ = pool.Submit(context.Background(), func(ctx context.Context) {})
+		close(submitted)
+	}()
+
+	assert.Eventually(t, func() bool { return pool.Waiting() == 1 }, time.Second, 10*time.Millisecond)
+
+	close(release)
+	<-submitted
+	assert.Eventually(t, func() bool { return pool.Running() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,41 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+)
+
+// credentials guard a gateway listener. An empty username lets every client
+// in, which turns the gateway into an open relay.
+type credentials struct {
+	username string
+	password string
+}
+
+func (c credentials) required() bool {
+	return c.username != ""
+}
+
+func (c credentials) check(username, password string) bool {
+	if !c.required() {
+		return true
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(c.username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(c.password)) == 1
+	return userOK && passOK
+}
+
+// parseProxyAuthorization reads the Basic credentials of a
+// Proxy-Authorization header.
+func parseProxyAuthorization(header string) (username, password string, ok bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
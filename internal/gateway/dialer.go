@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const defaultDialTimeout = 10 * time.Second
+
+type UpstreamDialer struct {
+	timeout time.Duration
+}
+
+func NewUpstreamDialer(timeout time.Duration) *UpstreamDialer {
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+	return &UpstreamDialer{timeout: timeout}
+}
+
+func (d *UpstreamDialer) DialThrough(ctx context.Context, upstream *proxy.Proxy, target string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	conn, err := tunnel.Dial(ctx, upstream.URL(), target, d.timeout)
+	if errors.Is(err, tunnel.ErrUnsupportedScheme) {
+		return nil, fmt.Errorf("upstream %s: %s: %w", upstream.Address(), upstream.Protocol, ErrUnsupportedUpstream)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("upstream %s: %w", upstream.Address(), err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,11 @@
+package gateway
+
+import "errors"
+
+var (
+	ErrUnsupportedUpstream = errors.New("unsupported upstream protocol")
+	ErrUpstreamsExhausted  = errors.New("all upstream attempts failed")
+	ErrSOCKSVersion        = errors.New("unsupported socks version")
+	ErrSOCKSAuth           = errors.New("socks authentication failed")
+	ErrProxyAuth           = errors.New("proxy authentication failed")
+)
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+func parseFilter(base proxy.FilterOptions, protocol, anonymity, maxLatencyMs string) (proxy.FilterOptions, error) {
+	filter := base
+
+	if protocol != "" {
+		if _, ok := proxy.ParseProtocol(protocol); !ok {
+			return filter, errors.New("protocol must be one of: http, https, socks4, socks5")
+		}
+		filter.Protocol = protocol
+	}
+
+	if anonymity != "" {
+		if _, ok := proxy.ParseAnonymity(anonymity); !ok {
+			return filter, errors.New("anonymity must be one of: transparent, anonymous, elite")
+		}
+		filter.Anonymity = anonymity
+	}
+
+	if maxLatencyMs != "" {
+		val, err := strconv.ParseInt(maxLatencyMs, 10, 64)
+		if err != nil || val <= 0 {
+			return filter, errors.New("max latency must be a positive integer")
+		}
+		filter.MaxLatency = time.Duration(val) * time.Millisecond
+	}
+
+	return filter, nil
+}
+
+// parseUsername splits "user-proto-http-anon-elite" into the user and its
+// filters. Filters are read from the right, so the user itself may contain
+// dashes.
+func parseUsername(base proxy.FilterOptions, username string) (string, proxy.FilterOptions, error) {
+	parts := strings.Split(username, "-")
+	values := make(map[string]string, 3)
+
+	for len(parts) >= 3 {
+		key, value := parts[len(parts)-2], parts[len(parts)-1]
+		if _, seen := values[key]; seen || !isFilterKey(key) {
+			break
+		}
+		values[key] = value
+		parts = parts[:len(parts)-2]
+	}
+
+	user := strings.Join(parts, "-")
+	filter, err := parseFilter(base, values["proto"], values["anon"], values["latency"])
+	return user, filter, err
+}
+
+func isFilterKey(key string) bool {
+	switch key {
+	case "proto", "anon", "latency":
+		return true
+	}
+	return false
+}
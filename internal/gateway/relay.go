@@ -0,0 +1,32 @@
+package gateway
+
+import (
+	"io"
+	"net"
+	"sync"
+)
+
+func relay(client, upstream net.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		client.Close()
+		upstream.Close()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(upstream, client)
+		once.Do(closeBoth)
+	}()
+
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(client, upstream)
+		once.Do(closeBoth)
+	}()
+
+	wg.Wait()
+}
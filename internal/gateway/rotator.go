@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const (
+	defaultMaxAttempts = 3
+	// picksPerAttempt bounds how often the picker may hand back upstreams that
+	// were already tried before the pool is considered exhausted.
+	picksPerAttempt = 5
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Debug(msg string, args ...any)
+}
+
+type ProxyPicker interface {
+	Execute(ctx context.Context, input proxy.GetRandomProxyInput) (*proxy.Proxy, error)
+}
+
+type Dialer interface {
+	DialThrough(ctx context.Context, upstream *proxy.Proxy, target string) (net.Conn, error)
+}
+
+type rotator struct {
+	picker      ProxyPicker
+	logger      Logger
+	maxAttempts int
+}
+
+func newRotator(picker ProxyPicker, logger Logger, maxAttempts int) rotator {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	return rotator{
+		picker:      picker,
+		logger:      logger,
+		maxAttempts: maxAttempts,
+	}
+}
+
+func (r rotator) withUpstream(ctx context.Context, filter proxy.FilterOptions, fn func(upstream *proxy.Proxy) error) error {
+	tried := make(map[string]bool)
+	var lastErr error
+
+	for picks := 0; len(tried) < r.maxAttempts && picks < r.maxAttempts*picksPerAttempt; picks++ {
+		upstream, err := r.picker.Execute(ctx, proxy.GetRandomProxyInput{
+			Protocol:   filter.Protocol,
+			Anonymity:  filter.Anonymity,
+			Capability: filter.Capability,
+			MaxLatency: filter.MaxLatency,
+		})
+		if err != nil {
+			return err
+		}
+
+		// a repeat pick is not an attempt: only distinct upstreams count
+		if tried[upstream.Address()] {
+			continue
+		}
+		tried[upstream.Address()] = true
+		attempt := len(tried)
+
+		if err := fn(upstream); err != nil {
+			r.logger.Debug("upstream failed", "upstream", upstream.Address(), "attempt", attempt, "error", err)
+			lastErr = err
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		r.logger.Debug("upstream selected", "upstream", upstream.Address(), "attempt", attempt)
+		return nil
+	}
+
+	if lastErr == nil {
+		return ErrUpstreamsExhausted
+	}
+	return fmt.Errorf("%w: %w", ErrUpstreamsExhausted, lastErr)
+}
@@ -0,0 +1,269 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const (
+	maxBufferedBody = 10 << 20
+
+	HeaderProtocol   = "X-Proxy-Protocol"
+	HeaderAnonymity  = "X-Proxy-Anonymity"
+	HeaderMaxLatency = "X-Proxy-Max-Latency-Ms"
+)
+
+var hopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	HeaderProtocol,
+	HeaderAnonymity,
+	HeaderMaxLatency,
+}
+
+type Server struct {
+	rotator
+	dialer         Dialer
+	logger         Logger
+	filter         proxy.FilterOptions
+	creds          credentials
+	requireConnect bool
+	timeout        time.Duration
+}
+
+func NewServer(picker ProxyPicker, dialer Dialer, logger Logger, maxAttempts int, timeout time.Duration) *Server {
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+	return &Server{
+		rotator: newRotator(picker, logger, maxAttempts),
+		dialer:  dialer,
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+func (s *Server) WithDefaultFilter(filter proxy.FilterOptions) *Server {
+	s.filter = filter
+	return s
+}
+
+// WithCredentials makes clients authenticate with Proxy-Authorization Basic.
+// As on the SOCKS5 listener, the username may carry filters, e.g.
+// "user-proto-http-anon-elite".
+func (s *Server) WithCredentials(username, password string) *Server {
+	s.creds = credentials{username: username, password: password}
+	return s
+}
+
+// WithRequireConnect limits tunnelled requests to upstreams whose CONNECT
+// tunnel passed the capability probe. Plain http forwards are not tunnelled
+// and may use any upstream.
+func (s *Server) WithRequireConnect(require bool) *Server {
+	s.requireConnect = require
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(s.filter, r.Header.Get(HeaderProtocol), r.Header.Get(HeaderAnonymity), r.Header.Get(HeaderMaxLatency))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err = s.authenticate(r, filter)
+	if errors.Is(err, ErrProxyAuth) {
+		w.Header().Set("Proxy-Authenticate", `Basic realm="proxy-engine"`)
+		http.Error(w, "proxy authentication required", http.StatusProxyAuthRequired)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tunnelled := r.Method == http.MethodConnect || r.URL.Scheme == "https"
+	if s.requireConnect && tunnelled {
+		filter.Capability = string(proxy.CapabilityConnect)
+	}
+
+	if r.Method == http.MethodConnect {
+		s.handleConnect(w, r, filter)
+		return
+	}
+	s.handleForward(w, r, filter)
+}
+
+func (s *Server) authenticate(r *http.Request, filter proxy.FilterOptions) (proxy.FilterOptions, error) {
+	header := r.Header.Get("Proxy-Authorization")
+	if header == "" {
+		if s.creds.required() {
+			return filter, ErrProxyAuth
+		}
+		return filter, nil
+	}
+
+	username, password, ok := parseProxyAuthorization(header)
+	if !ok {
+		return filter, ErrProxyAuth
+	}
+
+	user, filter, err := parseUsername(filter, username)
+	if err != nil {
+		return filter, err
+	}
+	if !s.creds.check(user, password) {
+		return filter, ErrProxyAuth
+	}
+	return filter, nil
+}
+
+func (s *Server) handleConnect(w http.ResponseWriter, r *http.Request, filter proxy.FilterOptions) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var upstreamConn net.Conn
+	err := s.withUpstream(r.Context(), filter, func(upstream *proxy.Proxy) error {
+		conn, err := s.dialer.DialThrough(r.Context(), upstream, r.Host)
+		if err != nil {
+			return err
+		}
+		upstreamConn = conn
+		return nil
+	})
+	if err != nil {
+		s.writeUpstreamError(w, r, err)
+		return
+	}
+
+	clientConn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		upstreamConn.Close()
+		s.logger.Warn("failed to hijack connection", "error", err)
+		return
+	}
+
+	if _, err := clientConn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		clientConn.Close()
+		upstreamConn.Close()
+		return
+	}
+
+	if n := buffered.Reader.Buffered(); n > 0 {
+		pending, _ := buffered.Reader.Peek(n)
+		if _, err := upstreamConn.Write(pending); err != nil {
+			clientConn.Close()
+			upstreamConn.Close()
+			return
+		}
+	}
+
+	relay(clientConn, upstreamConn)
+}
+
+func (s *Server) handleForward(w http.ResponseWriter, r *http.Request, filter proxy.FilterOptions) {
+	if !r.URL.IsAbs() {
+		http.Error(w, "absolute URL required", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBufferedBody+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxBufferedBody {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var resp *http.Response
+	err = s.withUpstream(r.Context(), filter, func(upstream *proxy.Proxy) error {
+		out := r.Clone(r.Context())
+		out.RequestURI = ""
+		out.ContentLength = int64(len(body))
+		out.Body = http.NoBody
+		if len(body) > 0 {
+			out.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		removeHopHeaders(out.Header)
+
+		transport := s.forwardTransport(upstream)
+
+		upstreamResp, err := transport.RoundTrip(out)
+		if err != nil {
+			return err
+		}
+		resp = upstreamResp
+		return nil
+	})
+	if err != nil {
+		s.writeUpstreamError(w, r, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	removeHopHeaders(resp.Header)
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// forwardTransport sends requests the way the upstream expects them: http
+// proxies get the absolute URL, SOCKS proxies carry a tunnel the request is
+// written through. https upstreams are plain CONNECT proxies, as for tunnels.
+func (s *Server) forwardTransport(upstream *proxy.Proxy) *http.Transport {
+	transport := &http.Transport{
+		TLSHandshakeTimeout:   s.timeout,
+		ResponseHeaderTimeout: s.timeout,
+		DisableKeepAlives:     true,
+	}
+
+	switch upstreamURL := upstream.URL(); upstreamURL.Scheme {
+	case "http", "https":
+		upstreamURL.Scheme = "http"
+		transport.Proxy = http.ProxyURL(upstreamURL)
+		transport.DialContext = (&net.Dialer{Timeout: s.timeout}).DialContext
+	default:
+		transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return s.dialer.DialThrough(ctx, upstream, addr)
+		}
+	}
+	return transport
+}
+
+func (s *Server) writeUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, proxy.ErrNoProxiesAvailable) {
+		http.Error(w, "no proxies available", http.StatusServiceUnavailable)
+		return
+	}
+	s.logger.Warn("gateway request failed", "method", r.Method, "host", r.Host, "error", err)
+	http.Error(w, "bad gateway", http.StatusBadGateway)
+}
+
+func removeHopHeaders(h http.Header) {
+	for _, key := range hopHeaders {
+		h.Del(key)
+	}
+}
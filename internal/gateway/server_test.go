@@ -0,0 +1,497 @@
+package gateway_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/gateway"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+type testLogger struct{}
+
+func (l testLogger) Info(msg string, args ...any)  {}
+func (l testLogger) Warn(msg string, args ...any)  {}
+func (l testLogger) Debug(msg string, args ...any) {}
+
+type sequencePicker struct {
+	mu      sync.Mutex
+	proxies []*proxy.Proxy
+	inputs  []proxy.GetRandomProxyInput
+	calls   int
+}
+
+func (p *sequencePicker) Execute(ctx context.Context, input proxy.GetRandomProxyInput) (*proxy.Proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inputs = append(p.inputs, input)
+	if len(p.proxies) == 0 {
+		return nil, proxy.ErrNoProxiesAvailable
+	}
+	selected := p.proxies[p.calls%len(p.proxies)]
+	p.calls++
+	return selected, nil
+}
+
+func newUpstreamProxy(t *testing.T) *proxy.Proxy {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			target, err := net.Dial("tcp", r.Host)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			client, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				target.Close()
+				return
+			}
+			_, _ = client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+			go func() {
+				_, _ = io.Copy(target, client)
+				target.Close()
+			}()
+			_, _ = io.Copy(client, target)
+			client.Close()
+			return
+		}
+
+		r.RequestURI = ""
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.Header().Set("X-Upstream", "fake")
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return toProxy(t, srv.Listener.Addr().String())
+}
+
+func toProxy(t *testing.T, addr string) *proxy.Proxy {
+	t.Helper()
+
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return proxy.NewProxy(host, port, proxy.HTTP, "test")
+}
+
+func deadProxy(t *testing.T) *proxy.Proxy {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	return toProxy(t, addr)
+}
+
+// newSOCKS4Upstream serves a minimal SOCKS4 proxy, the scheme net/http has
+// no proxy support for.
+func newSOCKS4Upstream(t *testing.T) *proxy.Proxy {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			client, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer client.Close()
+
+				br := bufio.NewReader(client)
+				req := make([]byte, 8)
+				if _, err := io.ReadFull(br, req); err != nil {
+					return
+				}
+				if _, err := br.ReadString(0); err != nil {
+					return
+				}
+
+				addr := net.JoinHostPort(net.IP(req[4:8]).String(), strconv.Itoa(int(req[2])<<8|int(req[3])))
+				target, err := net.Dial("tcp", addr)
+				if err != nil {
+					_, _ = client.Write([]byte{0, 0x5B, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+
+				_, _ = client.Write([]byte{0, 0x5A, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(target, br) }()
+				_, _ = io.Copy(client, target)
+			}()
+		}
+	}()
+
+	p := toProxy(t, ln.Addr().String())
+	p.Protocol = proxy.SOCKS4
+	return p
+}
+
+func newGateway(t *testing.T, picker gateway.ProxyPicker) *httptest.Server {
+	t.Helper()
+
+	gw := gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 3, 2*time.Second)
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func clientFor(t *testing.T, gw *httptest.Server) *http.Client {
+	t.Helper()
+
+	gwURL, err := url.Parse(gw.URL)
+	require.NoError(t, err)
+	return &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(gwURL)},
+		Timeout:   5 * time.Second,
+	}
+}
+
+func TestServer_Forward(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s", r.Method, body)
+	}))
+	defer target.Close()
+
+	t.Run("forwards request through upstream", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := newGateway(t, picker)
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "fake", resp.Header.Get("X-Upstream"))
+		assert.Equal(t, "GET ", string(body))
+	})
+
+	t.Run("retries on another upstream when the first fails", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{deadProxy(t), newUpstreamProxy(t)}}
+		gw := newGateway(t, picker)
+
+		req, err := http.NewRequest(http.MethodPost, target.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+
+		resp, err := clientFor(t, gw).Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "POST payload", string(body))
+		assert.Equal(t, 2, picker.calls)
+	})
+
+	t.Run("returns bad gateway when all upstreams fail", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{deadProxy(t)}}
+		gw := newGateway(t, picker)
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+
+	t.Run("returns service unavailable when pool is empty", func(t *testing.T) {
+		gw := newGateway(t, &sequencePicker{})
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	})
+
+	t.Run("passes filter headers to picker", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := newGateway(t, picker)
+
+		req, err := http.NewRequest(http.MethodGet, target.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(gateway.HeaderProtocol, "http")
+		req.Header.Set(gateway.HeaderAnonymity, "elite")
+		req.Header.Set(gateway.HeaderMaxLatency, "250")
+
+		resp, err := clientFor(t, gw).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Len(t, picker.inputs, 1)
+		assert.Equal(t, "http", picker.inputs[0].Protocol)
+		assert.Equal(t, "elite", picker.inputs[0].Anonymity)
+		assert.Equal(t, 250*time.Millisecond, picker.inputs[0].MaxLatency)
+	})
+
+	t.Run("does not count repeat picks as attempts", func(t *testing.T) {
+		dead := deadProxy(t)
+		picker := &sequencePicker{proxies: []*proxy.Proxy{dead, dead, dead, newUpstreamProxy(t)}}
+		srv := httptest.NewServer(gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 2, 2*time.Second))
+		defer srv.Close()
+
+		resp, err := clientFor(t, srv).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 4, picker.calls)
+	})
+
+	t.Run("gives up when the picker only repeats tried upstreams", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{deadProxy(t)}}
+		gw := newGateway(t, picker)
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Equal(t, 15, picker.calls)
+	})
+
+	t.Run("passes the default capability to picker", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 3, 2*time.Second).
+			WithDefaultFilter(proxy.FilterOptions{Capability: string(proxy.CapabilityConnect)})
+		srv := httptest.NewServer(gw)
+		defer srv.Close()
+
+		resp, err := clientFor(t, srv).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Len(t, picker.inputs, 1)
+		assert.Equal(t, "connect", picker.inputs[0].Capability)
+	})
+
+	t.Run("forwards through a socks4 upstream", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newSOCKS4Upstream(t)}}
+		gw := newGateway(t, picker)
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "GET ", string(body))
+		assert.Equal(t, 1, picker.calls)
+	})
+
+	t.Run("forwards through an https upstream as a plain proxy", func(t *testing.T) {
+		upstream := newUpstreamProxy(t)
+		upstream.Protocol = proxy.HTTPS
+		picker := &sequencePicker{proxies: []*proxy.Proxy{upstream}}
+		gw := newGateway(t, picker)
+
+		resp, err := clientFor(t, gw).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "fake", resp.Header.Get("X-Upstream"))
+	})
+
+	t.Run("does not require connect for plain forwards", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 3, 2*time.Second).
+			WithRequireConnect(true)
+		srv := httptest.NewServer(gw)
+		defer srv.Close()
+
+		resp, err := clientFor(t, srv).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Len(t, picker.inputs, 1)
+		assert.Empty(t, picker.inputs[0].Capability)
+	})
+
+	t.Run("rejects invalid filter headers", func(t *testing.T) {
+		gw := newGateway(t, &sequencePicker{})
+
+		req, err := http.NewRequest(http.MethodGet, target.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(gateway.HeaderProtocol, "ftp")
+
+		resp, err := clientFor(t, gw).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_Connect(t *testing.T) {
+	echo := newEchoServer(t)
+
+	t.Run("tunnels through upstream after retry", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{deadProxy(t), newUpstreamProxy(t)}}
+		gw := newGateway(t, picker)
+
+		conn, err := net.Dial("tcp", gw.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", echo.Addr(), echo.Addr())
+		require.NoError(t, err)
+
+		br := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(br, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 4)
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, err = io.ReadFull(br, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+	})
+
+	t.Run("requires connect support when configured", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 3, 2*time.Second).
+			WithRequireConnect(true)
+		srv := httptest.NewServer(gw)
+		defer srv.Close()
+
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = fmt.Fprintf(conn, "CONNECT %s HTTP/1.1\r\nHost: %s\r\n\r\n", echo.Addr(), echo.Addr())
+		require.NoError(t, err)
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		require.Len(t, picker.inputs, 1)
+		assert.Equal(t, "connect", picker.inputs[0].Capability)
+	})
+
+	t.Run("authenticates to upstream with stored credentials", func(t *testing.T) {
+		authHeader := make(chan string, 1)
+		upstreamSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader <- r.Header.Get("Proxy-Authorization")
+			w.WriteHeader(http.StatusProxyAuthRequired)
+		}))
+		t.Cleanup(upstreamSrv.Close)
+
+		upstream := toProxy(t, upstreamSrv.Listener.Addr().String())
+		upstream.Username = "alice"
+		upstream.Password = "s3cret"
+
+		dialer := gateway.NewUpstreamDialer(2 * time.Second)
+		_, err := dialer.DialThrough(context.Background(), upstream, echo.Addr().String())
+		assert.Error(t, err)
+
+		assert.Equal(t, "Basic YWxpY2U6czNjcmV0", <-authHeader)
+	})
+}
+
+func TestServer_Authentication(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Proxy-Authorization"))
+	}))
+	defer target.Close()
+
+	newAuthGateway := func(t *testing.T, picker gateway.ProxyPicker) *httptest.Server {
+		t.Helper()
+
+		gw := gateway.NewServer(picker, gateway.NewUpstreamDialer(2*time.Second), testLogger{}, 3, 2*time.Second).
+			WithCredentials("team-a", "secret")
+		srv := httptest.NewServer(gw)
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	clientAs := func(t *testing.T, gw *httptest.Server, user *url.Userinfo) *http.Client {
+		t.Helper()
+
+		gwURL, err := url.Parse(gw.URL)
+		require.NoError(t, err)
+		gwURL.User = user
+		return &http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyURL(gwURL)},
+			Timeout:   5 * time.Second,
+		}
+	}
+
+	t.Run("requires credentials when configured", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := newAuthGateway(t, picker)
+
+		resp, err := clientAs(t, gw, nil).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusProxyAuthRequired, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Proxy-Authenticate"), "Basic")
+		assert.Empty(t, picker.inputs)
+	})
+
+	t.Run("rejects wrong password", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := newAuthGateway(t, picker)
+
+		resp, err := clientAs(t, gw, url.UserPassword("team-a", "wrong")).Get(target.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, http.StatusProxyAuthRequired, resp.StatusCode)
+		assert.Empty(t, picker.inputs)
+	})
+
+	t.Run("reads filters from username and strips the credentials", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		gw := newAuthGateway(t, picker)
+
+		resp, err := clientAs(t, gw, url.UserPassword("team-a-anon-elite", "secret")).Get(target.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, string(body))
+		require.Len(t, picker.inputs, 1)
+		assert.Equal(t, "elite", picker.inputs[0].Anonymity)
+	})
+}
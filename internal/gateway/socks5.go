@@ -0,0 +1,268 @@
+package gateway
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const (
+	socks5Version   = 0x05
+	userPassVersion = 0x01
+
+	methodNoAuth       = 0x00
+	methodUserPass     = 0x02
+	methodNoAcceptable = 0xFF
+
+	cmdConnect = 0x01
+
+	atypIPv4   = 0x01
+	atypDomain = 0x03
+	atypIPv6   = 0x04
+
+	repSucceeded           = 0x00
+	repGeneralFailure      = 0x01
+	repHostUnreachable     = 0x04
+	repCommandNotSupported = 0x07
+	repAddrTypeNotSupport  = 0x08
+
+	authSuccess = 0x00
+	authFailure = 0x01
+
+	handshakeTimeout = 10 * time.Second
+)
+
+type SOCKS5Server struct {
+	rotator
+	dialer Dialer
+	logger Logger
+	filter proxy.FilterOptions
+	creds  credentials
+}
+
+func NewSOCKS5Server(picker ProxyPicker, dialer Dialer, logger Logger, maxAttempts int) *SOCKS5Server {
+	return &SOCKS5Server{
+		rotator: newRotator(picker, logger, maxAttempts),
+		dialer:  dialer,
+		logger:  logger,
+	}
+}
+
+func (s *SOCKS5Server) WithDefaultFilter(filter proxy.FilterOptions) *SOCKS5Server {
+	s.filter = filter
+	return s
+}
+
+func (s *SOCKS5Server) WithCredentials(username, password string) *SOCKS5Server {
+	s.creds = credentials{username: username, password: password}
+	return s
+}
+
+func (s *SOCKS5Server) Serve(ctx context.Context, ln net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		go s.handleConn(ctx, conn)
+	}
+}
+
+func (s *SOCKS5Server) handleConn(ctx context.Context, conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	filter, err := s.negotiate(conn)
+	if err != nil {
+		s.logger.Debug("socks5 handshake failed", "client", conn.RemoteAddr().String(), "error", err)
+		conn.Close()
+		return
+	}
+
+	target, err := s.readRequest(conn)
+	if err != nil {
+		s.logger.Debug("socks5 request rejected", "client", conn.RemoteAddr().String(), "error", err)
+		conn.Close()
+		return
+	}
+
+	var upstreamConn net.Conn
+	err = s.withUpstream(ctx, filter, func(upstream *proxy.Proxy) error {
+		c, err := s.dialer.DialThrough(ctx, upstream, target)
+		if err != nil {
+			return err
+		}
+		upstreamConn = c
+		return nil
+	})
+	if err != nil {
+		rep := byte(repHostUnreachable)
+		if errors.Is(err, proxy.ErrNoProxiesAvailable) {
+			rep = repGeneralFailure
+		}
+		s.logger.Warn("socks5 connect failed", "target", target, "error", err)
+		_ = writeReply(conn, rep)
+		conn.Close()
+		return
+	}
+
+	if err := writeReply(conn, repSucceeded); err != nil {
+		conn.Close()
+		upstreamConn.Close()
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	relay(conn, upstreamConn)
+}
+
+func (s *SOCKS5Server) negotiate(conn net.Conn) (proxy.FilterOptions, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return s.filter, fmt.Errorf("read greeting: %w", err)
+	}
+	if header[0] != socks5Version {
+		return s.filter, fmt.Errorf("version %d: %w", header[0], ErrSOCKSVersion)
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return s.filter, fmt.Errorf("read methods: %w", err)
+	}
+
+	method := s.selectMethod(methods)
+	if _, err := conn.Write([]byte{socks5Version, method}); err != nil {
+		return s.filter, fmt.Errorf("write method: %w", err)
+	}
+
+	switch method {
+	case methodNoAuth:
+		return s.filter, nil
+	case methodUserPass:
+		return s.authenticate(conn)
+	default:
+		return s.filter, fmt.Errorf("no acceptable method: %w", ErrSOCKSAuth)
+	}
+}
+
+func (s *SOCKS5Server) selectMethod(methods []byte) byte {
+	offered := make(map[byte]bool, len(methods))
+	for _, m := range methods {
+		offered[m] = true
+	}
+
+	switch {
+	case offered[methodUserPass]:
+		return methodUserPass
+	case offered[methodNoAuth] && !s.creds.required():
+		return methodNoAuth
+	default:
+		return methodNoAcceptable
+	}
+}
+
+func (s *SOCKS5Server) authenticate(conn net.Conn) (proxy.FilterOptions, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return s.filter, fmt.Errorf("read auth: %w", err)
+	}
+	if header[0] != userPassVersion {
+		return s.filter, fmt.Errorf("auth version %d: %w", header[0], ErrSOCKSVersion)
+	}
+
+	username := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, username); err != nil {
+		return s.filter, fmt.Errorf("read username: %w", err)
+	}
+
+	plen := make([]byte, 1)
+	if _, err := io.ReadFull(conn, plen); err != nil {
+		return s.filter, fmt.Errorf("read password length: %w", err)
+	}
+	password := make([]byte, plen[0])
+	if _, err := io.ReadFull(conn, password); err != nil {
+		return s.filter, fmt.Errorf("read password: %w", err)
+	}
+
+	user, filter, err := parseUsername(s.filter, string(username))
+	if err == nil && !s.creds.check(user, string(password)) {
+		err = ErrSOCKSAuth
+	}
+
+	status := byte(authSuccess)
+	if err != nil {
+		status = authFailure
+	}
+	if _, werr := conn.Write([]byte{userPassVersion, status}); werr != nil {
+		return s.filter, fmt.Errorf("write auth: %w", werr)
+	}
+
+	return filter, err
+}
+
+func (s *SOCKS5Server) readRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("read request: %w", err)
+	}
+	if header[0] != socks5Version {
+		return "", fmt.Errorf("version %d: %w", header[0], ErrSOCKSVersion)
+	}
+	if header[1] != cmdConnect {
+		_ = writeReply(conn, repCommandNotSupported)
+		return "", fmt.Errorf("command %d not supported", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case atypIPv4, atypIPv6:
+		size := net.IPv4len
+		if header[3] == atypIPv6 {
+			size = net.IPv6len
+		}
+		addr := make([]byte, size)
+		if _, err := io.ReadFull(conn, addr); err != nil {
+			return "", fmt.Errorf("read address: %w", err)
+		}
+		host = net.IP(addr).String()
+	case atypDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", fmt.Errorf("read domain length: %w", err)
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", fmt.Errorf("read domain: %w", err)
+		}
+		host = string(domain)
+	default:
+		_ = writeReply(conn, repAddrTypeNotSupport)
+		return "", fmt.Errorf("address type %d not supported", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", fmt.Errorf("read port: %w", err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func writeReply(conn net.Conn, rep byte) error {
+	_, err := conn.Write([]byte{socks5Version, rep, 0x00, atypIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
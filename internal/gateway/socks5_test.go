@@ -0,0 +1,174 @@
+package gateway_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	xproxy "golang.org/x/net/proxy"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/gateway"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+func newEchoServer(t *testing.T) net.Listener {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+func newSOCKS5(t *testing.T, srv *gateway.SOCKS5Server) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() { _ = srv.Serve(ctx, ln) }()
+
+	return ln.Addr().String()
+}
+
+func dialSOCKS5(t *testing.T, addr string, auth *xproxy.Auth, target string) (net.Conn, error) {
+	t.Helper()
+
+	dialer, err := xproxy.SOCKS5("tcp", addr, auth, &net.Dialer{Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	return dialer.Dial("tcp", target)
+}
+
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+
+	_, err := conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestSOCKS5Server(t *testing.T) {
+	echo := newEchoServer(t)
+	dialer := gateway.NewUpstreamDialer(2 * time.Second)
+
+	t.Run("tunnels without authentication", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		addr := newSOCKS5(t, gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3))
+
+		conn, err := dialSOCKS5(t, addr, nil, echo.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assertEcho(t, conn)
+	})
+
+	t.Run("retries on another upstream when the first fails", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{deadProxy(t), newUpstreamProxy(t)}}
+		addr := newSOCKS5(t, gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3))
+
+		conn, err := dialSOCKS5(t, addr, nil, echo.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assertEcho(t, conn)
+		assert.Equal(t, 2, picker.calls)
+	})
+
+	t.Run("reads filters from username", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		srv := gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3).WithCredentials("user", "secret")
+		addr := newSOCKS5(t, srv)
+
+		auth := &xproxy.Auth{User: "user-proto-http-anon-elite-latency-300", Password: "secret"}
+		conn, err := dialSOCKS5(t, addr, auth, echo.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assertEcho(t, conn)
+		require.Len(t, picker.inputs, 1)
+		assert.Equal(t, "http", picker.inputs[0].Protocol)
+		assert.Equal(t, "elite", picker.inputs[0].Anonymity)
+		assert.Equal(t, 300*time.Millisecond, picker.inputs[0].MaxLatency)
+	})
+
+	t.Run("accepts usernames containing dashes", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t), newUpstreamProxy(t)}}
+		srv := gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3).WithCredentials("team-a-user", "secret")
+		addr := newSOCKS5(t, srv)
+
+		conn, err := dialSOCKS5(t, addr, &xproxy.Auth{User: "team-a-user", Password: "secret"}, echo.Addr().String())
+		require.NoError(t, err)
+		assertEcho(t, conn)
+		conn.Close()
+
+		auth := &xproxy.Auth{User: "team-a-user-anon-elite", Password: "secret"}
+		conn, err = dialSOCKS5(t, addr, auth, echo.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assertEcho(t, conn)
+		require.Len(t, picker.inputs, 2)
+		assert.Empty(t, picker.inputs[0].Anonymity)
+		assert.Equal(t, "elite", picker.inputs[1].Anonymity)
+	})
+
+	t.Run("rejects wrong password", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		srv := gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3).WithCredentials("user", "secret")
+		addr := newSOCKS5(t, srv)
+
+		_, err := dialSOCKS5(t, addr, &xproxy.Auth{User: "user", Password: "wrong"}, echo.Addr().String())
+		assert.Error(t, err)
+		assert.Empty(t, picker.inputs)
+	})
+
+	t.Run("requires credentials when configured", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		srv := gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3).WithCredentials("user", "secret")
+		addr := newSOCKS5(t, srv)
+
+		_, err := dialSOCKS5(t, addr, nil, echo.Addr().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid filter in username", func(t *testing.T) {
+		picker := &sequencePicker{proxies: []*proxy.Proxy{newUpstreamProxy(t)}}
+		addr := newSOCKS5(t, gateway.NewSOCKS5Server(picker, dialer, testLogger{}, 3))
+
+		_, err := dialSOCKS5(t, addr, &xproxy.Auth{User: "user-proto-ftp", Password: "x"}, echo.Addr().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("fails when pool is empty", func(t *testing.T) {
+		addr := newSOCKS5(t, gateway.NewSOCKS5Server(&sequencePicker{}, dialer, testLogger{}, 3))
+
+		_, err := dialSOCKS5(t, addr, nil, echo.Addr().String())
+		assert.Error(t, err)
+	})
+}
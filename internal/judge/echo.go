@@ -0,0 +1,119 @@
+package judge
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	NonceParam     = "nonce"
+	MaxNonceLength = 128
+	minKeyLength   = 16
+)
+
+var (
+	ErrInvalidKey       = errors.New("judge key must be at least 16 bytes")
+	ErrInvalidNonce     = errors.New("invalid nonce")
+	ErrNonceMismatch    = errors.New("judge nonce mismatch")
+	ErrInvalidSignature = errors.New("invalid judge signature")
+)
+
+type Echo struct {
+	Origin    string            `json:"origin"`
+	Headers   map[string]string `json:"headers"`
+	Nonce     string            `json:"nonce"`
+	IssuedAt  int64             `json:"issued_at"`
+	Signature string            `json:"signature"`
+}
+
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) (*Signer, error) {
+	if len(key) < minKeyLength {
+		return nil, ErrInvalidKey
+	}
+	return &Signer{key: key}, nil
+}
+
+func NewSignerFromBase64(encoded string) (*Signer, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode judge key: %w", err)
+	}
+	return NewSigner(key)
+}
+
+func (s *Signer) Sign(e *Echo) {
+	e.Signature = hex.EncodeToString(s.mac(*e))
+}
+
+func (s *Signer) Verify(e Echo, nonce string) error {
+	if e.Nonce != nonce {
+		return ErrNonceMismatch
+	}
+
+	sig, err := hex.DecodeString(e.Signature)
+	if err != nil || !hmac.Equal(sig, s.mac(e)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (s *Signer) mac(e Echo) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(canonical(e))
+	return h.Sum(nil)
+}
+
+func canonical(e Echo) []byte {
+	keys := make([]string, 0, len(e.Headers))
+	for k := range e.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(e.Nonce)
+	b.WriteByte('\n')
+	b.WriteString(strconv.FormatInt(e.IssuedAt, 10))
+	b.WriteByte('\n')
+	b.WriteString(e.Origin)
+	b.WriteByte('\n')
+	for _, k := range keys {
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte(':')
+		b.WriteString(strconv.Quote(e.Headers[k]))
+		b.WriteByte('\n')
+	}
+
+	return []byte(b.String())
+}
+
+func NewNonce() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func ValidNonce(nonce string) bool {
+	if nonce == "" || len(nonce) > MaxNonceLength {
+		return false
+	}
+	for _, r := range nonce {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
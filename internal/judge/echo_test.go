@@ -0,0 +1,91 @@
+package judge_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+)
+
+func newSigner(t *testing.T, key string) *judge.Signer {
+	t.Helper()
+	s, err := judge.NewSigner([]byte(key))
+	require.NoError(t, err)
+	return s
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Run("rejects short keys", func(t *testing.T) {
+		_, err := judge.NewSigner([]byte("short"))
+		assert.ErrorIs(t, err, judge.ErrInvalidKey)
+	})
+
+	t.Run("decodes base64 keys", func(t *testing.T) {
+		s, err := judge.NewSignerFromBase64(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+		require.NoError(t, err)
+		assert.NotNil(t, s)
+	})
+
+	t.Run("rejects invalid base64", func(t *testing.T) {
+		_, err := judge.NewSignerFromBase64("!!!")
+		assert.Error(t, err)
+	})
+}
+
+func TestSigner_Verify(t *testing.T) {
+	signer := newSigner(t, "0123456789abcdef")
+
+	signed := func() judge.Echo {
+		e := judge.Echo{
+			Origin:   "1.2.3.4",
+			Headers:  map[string]string{"Host": "judge", "User-Agent": "ProxyEngine/1.0"},
+			Nonce:    "abc123",
+			IssuedAt: 1700000000,
+		}
+		signer.Sign(&e)
+		return e
+	}
+
+	t.Run("accepts untouched echo", func(t *testing.T) {
+		assert.NoError(t, signer.Verify(signed(), "abc123"))
+	})
+
+	t.Run("rejects replayed nonce", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify(signed(), "other"), judge.ErrNonceMismatch)
+	})
+
+	t.Run("rejects modified origin", func(t *testing.T) {
+		e := signed()
+		e.Origin = "9.9.9.9"
+		assert.ErrorIs(t, signer.Verify(e, "abc123"), judge.ErrInvalidSignature)
+	})
+
+	t.Run("rejects stripped header", func(t *testing.T) {
+		e := signed()
+		delete(e.Headers, "User-Agent")
+		assert.ErrorIs(t, signer.Verify(e, "abc123"), judge.ErrInvalidSignature)
+	})
+
+	t.Run("rejects signature from another key", func(t *testing.T) {
+		e := signed()
+		assert.ErrorIs(t, newSigner(t, "fedcba9876543210").Verify(e, "abc123"), judge.ErrInvalidSignature)
+	})
+
+	t.Run("rejects garbage signature", func(t *testing.T) {
+		e := signed()
+		e.Signature = "not-hex"
+		assert.ErrorIs(t, signer.Verify(e, "abc123"), judge.ErrInvalidSignature)
+	})
+}
+
+func TestValidNonce(t *testing.T) {
+	assert.True(t, judge.ValidNonce(judge.NewNonce()))
+	assert.True(t, judge.ValidNonce("abc-DEF_123"))
+	assert.False(t, judge.ValidNonce(""))
+	assert.False(t, judge.ValidNonce("has space"))
+	assert.False(t, judge.ValidNonce(string(make([]byte, judge.MaxNonceLength+1))))
+	assert.NotEqual(t, judge.NewNonce(), judge.NewNonce())
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+)
+
+type Logger interface {
+	Warn(msg string, args ...any)
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type Handler struct {
+	signer *judge.Signer
+	logger Logger
+	now    func() time.Time
+}
+
+func NewHandler(signer *judge.Signer, logger Logger) *Handler {
+	return &Handler{
+		signer: signer,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func NewRouter(h *Handler) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recoverer)
+
+	r.Get("/health", h.Health)
+	r.Get("/echo", h.Echo)
+
+	return r
+}
+
+func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("OK"))
+}
+
+func (h *Handler) Echo(w http.ResponseWriter, r *http.Request) {
+	nonce := r.URL.Query().Get(judge.NonceParam)
+	if nonce == "" {
+		nonce = judge.NewNonce()
+	} else if !judge.ValidNonce(nonce) {
+		h.logger.Warn("rejected malformed nonce", "remote", r.RemoteAddr)
+		writeError(w, http.StatusBadRequest, judge.ErrInvalidNonce.Error())
+		return
+	}
+
+	echo := judge.Echo{
+		Origin:   remoteIP(r),
+		Headers:  echoHeaders(r),
+		Nonce:    nonce,
+		IssuedAt: h.now().Unix(),
+	}
+	h.signer.Sign(&echo)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(echo)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func echoHeaders(r *http.Request) map[string]string {
+	headers := make(map[string]string, len(r.Header)+1)
+	for key, values := range r.Header {
+		headers[key] = strings.Join(values, ",")
+	}
+	headers["Host"] = r.Host
+	return headers
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: message})
+}
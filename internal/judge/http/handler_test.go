@@ -0,0 +1,66 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	judgehttp "github.com/JulianoL13/app-proxy-engine/internal/judge/http"
+)
+
+type testLogger struct{}
+
+func (testLogger) Warn(msg string, args ...any) {}
+
+func TestHandler_Echo(t *testing.T) {
+	signer, err := judge.NewSigner([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	router := judgehttp.NewRouter(judgehttp.NewHandler(signer, testLogger{}))
+
+	t.Run("echoes origin, headers and signed nonce", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/echo?nonce=abc123", nil)
+		req.RemoteAddr = "5.6.7.8:41000"
+		req.Header.Set("User-Agent", "ProxyEngine/1.0")
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var echo judge.Echo
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&echo))
+		assert.Equal(t, "5.6.7.8", echo.Origin)
+		assert.Equal(t, "abc123", echo.Nonce)
+		assert.Equal(t, "ProxyEngine/1.0", echo.Headers["User-Agent"])
+		assert.Equal(t, "10.0.0.1", echo.Headers["X-Forwarded-For"])
+		assert.Equal(t, "example.com", echo.Headers["Host"])
+		assert.NoError(t, signer.Verify(echo, "abc123"))
+	})
+
+	t.Run("generates a nonce when none is given", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/echo", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var echo judge.Echo
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&echo))
+		assert.NotEmpty(t, echo.Nonce)
+		assert.NoError(t, signer.Verify(echo, echo.Nonce))
+	})
+
+	t.Run("rejects malformed nonce", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/echo?nonce=%3Cscript%3E", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,77 @@
+package judge
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const pinPrefix = "sha256/"
+
+var ErrInvalidPin = errors.New("invalid certificate pin")
+
+type Pin [sha256.Size]byte
+
+func ParsePin(s string) (Pin, error) {
+	var pin Pin
+
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(s), pinPrefix)
+	if !ok {
+		return pin, fmt.Errorf("%q: missing %s prefix: %w", s, pinPrefix, ErrInvalidPin)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != sha256.Size {
+		return pin, fmt.Errorf("%q: %w", s, ErrInvalidPin)
+	}
+
+	copy(pin[:], raw)
+	return pin, nil
+}
+
+func ParsePins(s string) ([]Pin, error) {
+	var pins []Pin
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		pin, err := ParsePin(part)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	if len(pins) == 0 {
+		return nil, fmt.Errorf("no pins given: %w", ErrInvalidPin)
+	}
+	return pins, nil
+}
+
+func PublicKeyPin(cert *x509.Certificate) Pin {
+	return sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+}
+
+func CertificatePin(cert *x509.Certificate) Pin {
+	return sha256.Sum256(cert.Raw)
+}
+
+func (p Pin) String() string {
+	return pinPrefix + base64.StdEncoding.EncodeToString(p[:])
+}
+
+func MatchesPins(chain []*x509.Certificate, pins []Pin) bool {
+	for _, cert := range chain {
+		spki := PublicKeyPin(cert)
+		full := CertificatePin(cert)
+		for _, pin := range pins {
+			if subtle.ConstantTimeCompare(spki[:], pin[:]) == 1 || subtle.ConstantTimeCompare(full[:], pin[:]) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
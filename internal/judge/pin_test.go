@@ -0,0 +1,78 @@
+package judge_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+)
+
+func TestPins(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	cert := srv.Certificate()
+
+	other := newCertificate(t)
+
+	t.Run("round trips through its string form", func(t *testing.T) {
+		pin := judge.PublicKeyPin(cert)
+
+		parsed, err := judge.ParsePin(pin.String())
+		require.NoError(t, err)
+		assert.Equal(t, pin, parsed)
+	})
+
+	t.Run("rejects malformed pins", func(t *testing.T) {
+		for _, s := range []string{"", "abc", "sha256/not-base64!", "sha256/AAAA"} {
+			_, err := judge.ParsePin(s)
+			assert.ErrorIs(t, err, judge.ErrInvalidPin, s)
+		}
+	})
+
+	t.Run("parses a comma separated list", func(t *testing.T) {
+		pins, err := judge.ParsePins(judge.PublicKeyPin(cert).String() + ", " + judge.CertificatePin(cert).String())
+		require.NoError(t, err)
+		assert.Len(t, pins, 2)
+
+		_, err = judge.ParsePins(" , ")
+		assert.ErrorIs(t, err, judge.ErrInvalidPin)
+	})
+
+	t.Run("matches public key or certificate pins", func(t *testing.T) {
+		chain := []*x509.Certificate{cert}
+
+		assert.True(t, judge.MatchesPins(chain, []judge.Pin{judge.PublicKeyPin(cert)}))
+		assert.True(t, judge.MatchesPins(chain, []judge.Pin{judge.CertificatePin(cert)}))
+		assert.False(t, judge.MatchesPins(chain, []judge.Pin{judge.CertificatePin(other), judge.PublicKeyPin(other)}))
+		assert.False(t, judge.MatchesPins(nil, []judge.Pin{judge.PublicKeyPin(cert)}))
+	})
+}
+
+func newCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,505 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+)
+
+var (
+	bucketData     = []byte("data")
+	bucketIndexes  = []byte("idx")
+	bucketMITM     = []byte("mitm")
+	bucketSessions = []byte("sessions")
+)
+
+const (
+	defaultTTL = 30 * time.Minute
+	tracerName = "github.com/JulianoL13/app-proxy-engine/internal/proxy/bolt"
+
+	// checkedIndex scores proxies by their last check for GetStale.
+	checkedIndex = "checked"
+)
+
+// Repository keeps proxies in a bbolt file with the same indexes and cursor
+// semantics as the Redis repository. bbolt has no expiry, so every record
+// carries its own deadline and Cleanup drops what has passed it.
+type Repository struct {
+	db    *bbolt.DB
+	ttl   time.Duration
+	codec storage.Codec
+}
+
+func NewRepository(db *bbolt.DB) *Repository {
+	return &Repository{
+		db:  db,
+		ttl: defaultTTL,
+	}
+}
+
+func (r *Repository) WithTTL(ttl time.Duration) *Repository {
+	r.ttl = ttl
+	return r
+}
+
+func (r *Repository) WithCipher(c storage.Cipher) *Repository {
+	r.codec = storage.Codec{Cipher: c}
+	return r
+}
+
+func (r *Repository) Save(ctx context.Context, p *proxy.Proxy) error {
+	_, span := otel.Tracer(tracerName).Start(ctx, "proxy.save",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "bbolt"),
+			attribute.String("proxy.address", p.Address()),
+		),
+	)
+	defer span.End()
+
+	if err := r.db.Update(func(tx *bbolt.Tx) error { return r.save(tx, p) }); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) save(tx *bbolt.Tx, p *proxy.Proxy) error {
+	address := p.Address()
+
+	if mitm := tx.Bucket(bucketMITM); mitm != nil && mitm.Get([]byte(address)) != nil {
+		return fmt.Errorf("save proxy %s: %w", address, proxy.ErrIntercepted)
+	}
+
+	data, err := r.codec.Encode(p)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(r.ttl).Unix()
+
+	if err := r.put(tx, address, data, expiresAt); err != nil {
+		return fmt.Errorf("save proxy: %w", err)
+	}
+	if err := r.index(tx, p, expiresAt); err != nil {
+		return fmt.Errorf("save proxy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) index(tx *bbolt.Tx, p *proxy.Proxy, expiresAt int64) error {
+	address := p.Address()
+
+	indexes, err := tx.CreateBucketIfNotExists(bucketIndexes)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range storage.ExpiringIndexes(p) {
+		z, err := createZSet(indexes, name)
+		if err != nil {
+			return err
+		}
+		if err := z.add(address, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	for _, capability := range storage.Capabilities {
+		z, err := createZSet(indexes, storage.CapabilityIndex(capability))
+		if err != nil {
+			return err
+		}
+		if p.Supports(capability) {
+			err = z.add(address, expiresAt)
+		} else {
+			err = z.rem(address)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	latency, err := createZSet(indexes, storage.LatencyIndex)
+	if err != nil {
+		return err
+	}
+	if err := latency.add(address, p.Latency.Milliseconds()); err != nil {
+		return err
+	}
+
+	checked, err := createZSet(indexes, checkedIndex)
+	if err != nil {
+		return err
+	}
+	return checked.add(address, p.LastCheckAt.Unix())
+}
+
+func (r *Repository) unindex(tx *bbolt.Tx, p *proxy.Proxy) error {
+	address := p.Address()
+	indexes := tx.Bucket(bucketIndexes)
+
+	names := storage.ExpiringIndexes(p)
+	for _, capability := range storage.Capabilities {
+		names = append(names, storage.CapabilityIndex(capability))
+	}
+	names = append(names, storage.LatencyIndex, checkedIndex)
+
+	for _, name := range names {
+		z, ok := openZSet(indexes, name)
+		if !ok {
+			continue
+		}
+		if err := z.rem(address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordFailure takes the proxy out of the serving indexes and keeps it until
+// its cooldown ends, scored in the check index at the cooldown deadline so
+// GetStale hands it back for another check once it is over.
+func (r *Repository) RecordFailure(_ context.Context, p *proxy.Proxy) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		stored, _, err := r.get(tx, p.Address(), time.Now())
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return nil
+		}
+
+		stored.MarkFailure()
+		*p = *stored
+
+		data, err := r.codec.Encode(stored)
+		if err != nil {
+			return err
+		}
+
+		if err := r.put(tx, stored.Address(), data, stored.CooldownUntil.Add(r.ttl).Unix()); err != nil {
+			return fmt.Errorf("record failure: %w", err)
+		}
+		if err := r.unindex(tx, stored); err != nil {
+			return fmt.Errorf("record failure: %w", err)
+		}
+
+		indexes, err := tx.CreateBucketIfNotExists(bucketIndexes)
+		if err != nil {
+			return fmt.Errorf("record failure: %w", err)
+		}
+		checked, err := createZSet(indexes, checkedIndex)
+		if err != nil {
+			return fmt.Errorf("record failure: %w", err)
+		}
+		if err := checked.add(stored.Address(), stored.CooldownUntil.Unix()); err != nil {
+			return fmt.Errorf("record failure: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *Repository) RecordMITM(_ context.Context, p *proxy.Proxy) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		stored, _, err := r.get(tx, p.Address(), time.Now())
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			stored = p
+		}
+
+		stored.MarkMITM()
+		*p = *stored
+
+		data, err := r.codec.Encode(stored)
+		if err != nil {
+			return err
+		}
+
+		mitm, err := tx.CreateBucketIfNotExists(bucketMITM)
+		if err != nil {
+			return fmt.Errorf("record mitm: %w", err)
+		}
+		if err := mitm.Put([]byte(stored.Address()), []byte{}); err != nil {
+			return fmt.Errorf("record mitm: %w", err)
+		}
+		if err := r.put(tx, stored.Address(), data, time.Now().Add(r.ttl).Unix()); err != nil {
+			return fmt.Errorf("record mitm: %w", err)
+		}
+		if err := r.unindex(tx, stored); err != nil {
+			return fmt.Errorf("record mitm: %w", err)
+		}
+		return nil
+	})
+}
+
+// put stores data behind its expiration so reads can tell a record that
+// Cleanup has not reached yet from a live one.
+func (r *Repository) put(tx *bbolt.Tx, address string, data []byte, expiresAt int64) error {
+	bucket, err := tx.CreateBucketIfNotExists(bucketData)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(address), append(encodeInt(expiresAt), data...))
+}
+
+// get returns the stored proxy and its expiration, or nil when it is missing
+// or expired.
+func (r *Repository) get(tx *bbolt.Tx, address string, now time.Time) (*proxy.Proxy, int64, error) {
+	bucket := tx.Bucket(bucketData)
+	if bucket == nil {
+		return nil, 0, nil
+	}
+
+	value := bucket.Get([]byte(address))
+	if len(value) < 8 {
+		return nil, 0, nil
+	}
+
+	expiresAt := decodeInt(value[:8])
+	if expiresAt <= now.Unix() {
+		return nil, 0, nil
+	}
+
+	p, err := r.codec.Decode(value[8:])
+	if err != nil {
+		return nil, 0, err
+	}
+	return p, expiresAt, nil
+}
+
+func (r *Repository) load(tx *bbolt.Tx, addresses []string) []*proxy.Proxy {
+	now := time.Now()
+
+	proxies := make([]*proxy.Proxy, 0, len(addresses))
+	for _, address := range addresses {
+		p, _, err := r.get(tx, address, now)
+		if err != nil || p == nil {
+			continue
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies
+}
+
+func (r *Repository) GetAlive(_ context.Context, cursor float64, limit int, filter proxy.FilterOptions) ([]*proxy.Proxy, float64, int, error) {
+	var (
+		proxies    []*proxy.Proxy
+		nextCursor float64
+		total      int
+	)
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		z, ok := openZSet(tx.Bucket(bucketIndexes), storage.SelectIndex(filter))
+		if !ok {
+			return nil
+		}
+
+		now := time.Now().Unix()
+
+		total = z.count(now)
+		if total == 0 {
+			return nil
+		}
+
+		min := now
+		if cursor > 0 {
+			min = int64(math.Floor(cursor)) + 1
+		}
+
+		var results []scored
+		if limit > 0 {
+			results = z.rangeByScore(min, math.MaxInt64, limit)
+		} else {
+			results = z.rangeByScore(now, math.MaxInt64, 0)
+		}
+
+		if len(results) == 0 {
+			return nil
+		}
+
+		addresses := make([]string, len(results))
+		for i, s := range results {
+			addresses[i] = s.member
+		}
+
+		for _, p := range r.load(tx, addresses) {
+			if p.MITM || !p.IsReady() || !storage.Matches(p, filter) {
+				continue
+			}
+			proxies = append(proxies, p)
+		}
+
+		if limit > 0 && len(results) == limit {
+			nextCursor = float64(results[len(results)-1].score)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("get alive: %w", err)
+	}
+
+	return proxies, nextCursor, total, nil
+}
+
+func (r *Repository) GetSession(_ context.Context, session string) (string, error) {
+	var address string
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(bucketSessions)
+		if bucket == nil {
+			return nil
+		}
+
+		value := bucket.Get([]byte(session))
+		if len(value) < 8 || decodeInt(value[:8]) <= time.Now().UnixMilli() {
+			return nil
+		}
+		address = string(value[8:])
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+	return address, nil
+}
+
+func (r *Repository) SetSession(_ context.Context, session, address string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl).UnixMilli()
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketSessions)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(session), append(encodeInt(expiresAt), address...))
+	})
+	if err != nil {
+		return fmt.Errorf("set session: %w", err)
+	}
+	return nil
+}
+
+// GetStale returns up to limit proxies last checked before checkedBefore and
+// bumps their check score to now, so a recheck that is still queued is not
+// scheduled again on the next tick. Entries whose record expired are dropped.
+func (r *Repository) GetStale(_ context.Context, checkedBefore time.Time, limit int) ([]*proxy.Proxy, error) {
+	var stale []*proxy.Proxy
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		z, ok := openZSet(tx.Bucket(bucketIndexes), checkedIndex)
+		if !ok {
+			return nil
+		}
+
+		now := time.Now()
+		for _, s := range z.rangeByScore(math.MinInt64, checkedBefore.Unix()-1, limit) {
+			p, _, err := r.get(tx, s.member, now)
+			if err != nil || p == nil {
+				if err := z.rem(s.member); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := z.add(s.member, now.Unix()); err != nil {
+				return err
+			}
+			stale = append(stale, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get stale: %w", err)
+	}
+
+	return stale, nil
+}
+
+// Cleanup does what key expiry and the index cleaner do for Redis: it drops
+// expired records and sessions, expired index entries, and latency and
+// last-check entries whose record is gone.
+func (r *Repository) Cleanup(_ context.Context) error {
+	now := time.Now()
+
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		if err := deleteExpired(tx.Bucket(bucketData), now.Unix()); err != nil {
+			return err
+		}
+		if err := deleteExpired(tx.Bucket(bucketSessions), now.UnixMilli()); err != nil {
+			return err
+		}
+
+		indexes := tx.Bucket(bucketIndexes)
+		if indexes == nil {
+			return nil
+		}
+
+		return indexes.ForEachBucket(func(name []byte) error {
+			z, _ := openZSet(indexes, string(name))
+			if string(name) != storage.LatencyIndex && string(name) != checkedIndex {
+				return z.remRangeByScore(now.Unix())
+			}
+
+			var orphaned []string
+			data := tx.Bucket(bucketData)
+			err := z.members.ForEach(func(member, _ []byte) error {
+				if data == nil || data.Get(member) == nil {
+					orphaned = append(orphaned, string(member))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, member := range orphaned {
+				if err := z.rem(member); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("cleanup: %w", err)
+	}
+	return nil
+}
+
+func deleteExpired(bucket *bbolt.Bucket, now int64) error {
+	if bucket == nil {
+		return nil
+	}
+
+	var expired [][]byte
+	err := bucket.ForEach(func(k, v []byte) error {
+		if len(v) < 8 || decodeInt(v[:8]) <= now {
+			expired = append(expired, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package bolt_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	proxybolt "github.com/JulianoL13/app-proxy-engine/internal/proxy/bolt"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/proxytest"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+)
+
+func openDB(t *testing.T, path string) *bbolt.DB {
+	t.Helper()
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newCipher(t *testing.T) *crypt.AESGCM {
+	t.Helper()
+
+	cipher, err := crypt.NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+	return cipher
+}
+
+func TestRepository_Conformance(t *testing.T) {
+	proxytest.Run(t, func(t *testing.T) proxytest.Repository {
+		db := openDB(t, filepath.Join(t.TempDir(), "proxies.db"))
+		return proxybolt.NewRepository(db).WithCipher(newCipher(t))
+	})
+}
+
+func TestRepository_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "proxies.db")
+
+	db, err := bbolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+
+	repo := proxybolt.NewRepository(db)
+
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	require.NoError(t, repo.Save(ctx, p))
+	require.NoError(t, repo.RecordMITM(ctx, proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")))
+	require.NoError(t, repo.SetSession(ctx, "login", p.Address(), time.Hour))
+	require.NoError(t, repo.RecordScrape(ctx, "s1", 4, 2))
+	require.NoError(t, db.Close())
+
+	repo = proxybolt.NewRepository(openDB(t, path))
+
+	proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Protocol: "http", Anonymity: "elite"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, proxies, 1)
+	assert.Equal(t, "1.1.1.1:8080", proxies[0].Address())
+	assert.Equal(t, 100*time.Millisecond, proxies[0].Latency)
+
+	address, err := repo.GetSession(ctx, "login")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.1.1:8080", address)
+
+	blocked := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")
+	blocked.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	assert.ErrorIs(t, repo.Save(ctx, blocked), proxy.ErrIntercepted)
+
+	stats, err := repo.ListSourceStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []proxy.SourceStats{{Name: "s1", Scraped: 4, Published: 2}}, stats)
+}
+
+func TestRepository_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, filepath.Join(t.TempDir(), "proxies.db"))
+
+	expired := proxybolt.NewRepository(db).WithTTL(-time.Minute)
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p.Country = "BR"
+	require.NoError(t, expired.Save(ctx, p))
+	require.NoError(t, expired.SetSession(ctx, "old", p.Address(), -time.Minute))
+
+	repo := proxybolt.NewRepository(db)
+	live := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")
+	live.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	require.NoError(t, repo.Save(ctx, live))
+
+	_, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	require.NoError(t, repo.Cleanup(ctx))
+
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte("data"))
+		assert.Nil(t, data.Get([]byte("1.1.1.1:8080")))
+		assert.NotNil(t, data.Get([]byte("2.2.2.2:8080")))
+		assert.Nil(t, tx.Bucket([]byte("sessions")).Get([]byte("old")))
+
+		indexes := tx.Bucket([]byte("idx"))
+		for _, name := range []string{"alive", "proto:http", "country:BR", "latency"} {
+			members := indexes.Bucket([]byte(name)).Bucket([]byte("members"))
+			assert.Nil(t, members.Get([]byte("1.1.1.1:8080")), name)
+		}
+		assert.NotNil(t, indexes.Bucket([]byte("latency")).Bucket([]byte("members")).Get([]byte("2.2.2.2:8080")))
+		return nil
+	}))
+}
+
+func TestRepository_Credentials(t *testing.T) {
+	ctx := context.Background()
+	db := openDB(t, filepath.Join(t.TempDir(), "proxies.db"))
+
+	t.Run("encrypts credentials at rest", func(t *testing.T) {
+		repo := proxybolt.NewRepository(db).WithCipher(newCipher(t))
+
+		p := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+		p.Username = "alice"
+		p.Password = "s3cret"
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+			raw := string(tx.Bucket([]byte("data")).Get([]byte("3.3.3.3:8080")))
+			assert.NotContains(t, raw, "alice")
+			assert.NotContains(t, raw, "s3cret")
+			return nil
+		}))
+	})
+
+	t.Run("refuses to store credentials without a cipher", func(t *testing.T) {
+		repo := proxybolt.NewRepository(db)
+
+		p := proxy.NewProxy("4.4.4.4", 8080, proxy.HTTP, "s1")
+		p.Username = "bob"
+		p.Password = "pw"
+
+		assert.ErrorIs(t, repo.Save(ctx, p), storage.ErrNoCipher)
+	})
+}
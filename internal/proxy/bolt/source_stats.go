@@ -0,0 +1,218 @@
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const (
+	fieldScraped        = "scraped"
+	fieldPublished      = "published"
+	fieldVerified       = "verified"
+	fieldLatencyTotalMs = "latency_total_ms"
+
+	fieldStrikes          = "strikes"
+	fieldQuarantines      = "quarantines"
+	fieldQuarantinedUntil = "quarantined_until"
+	fieldForced           = "forced"
+	fieldLastVerified     = "last_verified"
+	fieldLastCheckedAt    = "last_checked_at"
+)
+
+// bucketSources holds one nested bucket of counters per source, the
+// counterpart of the per-source Redis hashes.
+var bucketSources = []byte("sources")
+
+func (r *Repository) RecordScrape(_ context.Context, source string, scraped, published int) error {
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := sourceBucket(tx, source)
+		if err != nil {
+			return err
+		}
+		if err := incrField(bucket, fieldScraped, int64(scraped)); err != nil {
+			return err
+		}
+		return incrField(bucket, fieldPublished, int64(published))
+	})
+	if err != nil {
+		return fmt.Errorf("record scrape stats: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) RecordVerified(_ context.Context, source string, latency time.Duration) error {
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := sourceBucket(tx, source)
+		if err != nil {
+			return err
+		}
+		if err := incrField(bucket, fieldVerified, 1); err != nil {
+			return err
+		}
+		return incrField(bucket, fieldLatencyTotalMs, latency.Milliseconds())
+	})
+	if err != nil {
+		return fmt.Errorf("record verified stats: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListSourceStats(_ context.Context) ([]proxy.SourceStats, error) {
+	var stats []proxy.SourceStats
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		sources := tx.Bucket(bucketSources)
+		if sources == nil {
+			return nil
+		}
+
+		return sources.ForEachBucket(func(name []byte) error {
+			stats = append(stats, readSourceStats(string(name), sources.Bucket(name)))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list source stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetSourceStats reads the named sources, returning zero stats for the ones
+// never recorded.
+func (r *Repository) GetSourceStats(_ context.Context, sources []string) (map[string]proxy.SourceStats, error) {
+	stats := make(map[string]proxy.SourceStats, len(sources))
+	if len(sources) == 0 {
+		return stats, nil
+	}
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		parent := tx.Bucket(bucketSources)
+		for _, source := range sources {
+			var bucket *bbolt.Bucket
+			if parent != nil {
+				bucket = parent.Bucket([]byte(source))
+			}
+			stats[source] = readSourceStats(source, bucket)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get source stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// SaveSourceHealth writes the quarantine fields of s and leaves the counters
+// and the operator override alone.
+func (r *Repository) SaveSourceHealth(_ context.Context, s proxy.SourceStats) error {
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := sourceBucket(tx, s.Name)
+		if err != nil {
+			return err
+		}
+
+		values := map[string]int64{
+			fieldLastVerified:     s.LastVerified,
+			fieldStrikes:          int64(s.Strikes),
+			fieldQuarantines:      int64(s.Quarantines),
+			fieldQuarantinedUntil: formatUnix(s.QuarantinedUntil),
+			fieldLastCheckedAt:    formatUnix(s.LastCheckedAt),
+		}
+		for field, value := range values {
+			if err := bucket.Put([]byte(field), encodeInt(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("save source health: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) SetSourceForced(_ context.Context, source string, forced bool) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		sources := tx.Bucket(bucketSources)
+		if sources == nil || sources.Bucket([]byte(source)) == nil {
+			return proxy.ErrSourceNotFound
+		}
+		bucket := sources.Bucket([]byte(source))
+
+		values := map[string]int64{fieldForced: 0}
+		if forced {
+			values = map[string]int64{fieldForced: 1, fieldStrikes: 0, fieldQuarantines: 0, fieldQuarantinedUntil: 0}
+		}
+
+		for field, value := range values {
+			if err := bucket.Put([]byte(field), encodeInt(value)); err != nil {
+				return fmt.Errorf("set source override: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func sourceBucket(tx *bbolt.Tx, source string) (*bbolt.Bucket, error) {
+	sources, err := tx.CreateBucketIfNotExists(bucketSources)
+	if err != nil {
+		return nil, err
+	}
+	return sources.CreateBucketIfNotExists([]byte(source))
+}
+
+// readSourceStats decodes a source bucket; a nil bucket reads as zero stats.
+func readSourceStats(source string, bucket *bbolt.Bucket) proxy.SourceStats {
+	s := proxy.SourceStats{
+		Name:             source,
+		Scraped:          getField(bucket, fieldScraped),
+		Published:        getField(bucket, fieldPublished),
+		Verified:         getField(bucket, fieldVerified),
+		Strikes:          int(getField(bucket, fieldStrikes)),
+		Quarantines:      int(getField(bucket, fieldQuarantines)),
+		QuarantinedUntil: parseUnix(getField(bucket, fieldQuarantinedUntil)),
+		Forced:           getField(bucket, fieldForced) == 1,
+		LastVerified:     getField(bucket, fieldLastVerified),
+		LastCheckedAt:    parseUnix(getField(bucket, fieldLastCheckedAt)),
+	}
+	if s.Verified > 0 {
+		s.AvgLatency = time.Duration(getField(bucket, fieldLatencyTotalMs)/s.Verified) * time.Millisecond
+	}
+	return s
+}
+
+func getField(bucket *bbolt.Bucket, field string) int64 {
+	if bucket == nil {
+		return 0
+	}
+	value := bucket.Get([]byte(field))
+	if len(value) != 8 {
+		return 0
+	}
+	return decodeInt(value)
+}
+
+func parseUnix(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(n, 0)
+}
+
+func formatUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func incrField(bucket *bbolt.Bucket, field string, delta int64) error {
+	return bucket.Put([]byte(field), encodeInt(getField(bucket, field)+delta))
+}
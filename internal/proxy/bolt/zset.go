@@ -0,0 +1,128 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"go.etcd.io/bbolt"
+)
+
+var (
+	bucketScores  = []byte("scores")
+	bucketMembers = []byte("members")
+)
+
+// zset stands in for a Redis sorted set: scores holds score+member keys so
+// ranges come out in score order, members maps each member to its score so
+// re-adding or removing one does not need a scan.
+type zset struct {
+	scores  *bbolt.Bucket
+	members *bbolt.Bucket
+}
+
+type scored struct {
+	member string
+	score  int64
+}
+
+func createZSet(parent *bbolt.Bucket, name string) (zset, error) {
+	b, err := parent.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return zset{}, fmt.Errorf("create index %s: %w", name, err)
+	}
+	scores, err := b.CreateBucketIfNotExists(bucketScores)
+	if err != nil {
+		return zset{}, fmt.Errorf("create index %s: %w", name, err)
+	}
+	members, err := b.CreateBucketIfNotExists(bucketMembers)
+	if err != nil {
+		return zset{}, fmt.Errorf("create index %s: %w", name, err)
+	}
+	return zset{scores: scores, members: members}, nil
+}
+
+func openZSet(parent *bbolt.Bucket, name string) (zset, bool) {
+	if parent == nil {
+		return zset{}, false
+	}
+	b := parent.Bucket([]byte(name))
+	if b == nil {
+		return zset{}, false
+	}
+	return zset{scores: b.Bucket(bucketScores), members: b.Bucket(bucketMembers)}, true
+}
+
+func (z zset) add(member string, score int64) error {
+	if err := z.rem(member); err != nil {
+		return err
+	}
+	if err := z.scores.Put(scoreKey(score, member), []byte{}); err != nil {
+		return err
+	}
+	return z.members.Put([]byte(member), encodeInt(score))
+}
+
+func (z zset) rem(member string) error {
+	old := z.members.Get([]byte(member))
+	if old == nil {
+		return nil
+	}
+	if err := z.scores.Delete(scoreKey(decodeInt(old), member)); err != nil {
+		return err
+	}
+	return z.members.Delete([]byte(member))
+}
+
+// rangeByScore returns members scored within [min, max] in ascending order,
+// at most limit of them when limit is positive.
+func (z zset) rangeByScore(min, max int64, limit int) []scored {
+	var results []scored
+	c := z.scores.Cursor()
+	for k, _ := c.Seek(encodeInt(min)); k != nil; k, _ = c.Next() {
+		score := decodeInt(k[:8])
+		if score > max {
+			break
+		}
+		results = append(results, scored{member: string(k[8:]), score: score})
+		if limit > 0 && len(results) == limit {
+			break
+		}
+	}
+	return results
+}
+
+func (z zset) count(min int64) int {
+	var n int
+	c := z.scores.Cursor()
+	for k, _ := c.Seek(encodeInt(min)); k != nil; k, _ = c.Next() {
+		n++
+	}
+	return n
+}
+
+func (z zset) remRangeByScore(max int64) error {
+	expired := z.rangeByScore(math.MinInt64, max, 0)
+	for _, s := range expired {
+		if err := z.rem(s.member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeInt flips the sign bit so negative values still sort before
+// positive ones byte-wise.
+func encodeInt(v int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v)^(1<<63))
+	return b
+}
+
+func decodeInt(b []byte) int64 {
+	return int64(binary.BigEndian.Uint64(b) ^ (1 << 63))
+}
+
+func scoreKey(score int64, member string) []byte {
+	return append(encodeInt(score), member...)
+}
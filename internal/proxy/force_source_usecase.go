@@ -0,0 +1,37 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrSourceNotFound = errors.New("source not found")
+
+type ForceSourceLogger interface {
+	Info(msg string, args ...any)
+}
+
+type SourceOverrideWriter interface {
+	SetSourceForced(ctx context.Context, source string, forced bool) error
+}
+
+type ForceSourceUseCase struct {
+	writer SourceOverrideWriter
+	logger ForceSourceLogger
+}
+
+func NewForceSourceUseCase(writer SourceOverrideWriter, logger ForceSourceLogger) *ForceSourceUseCase {
+	return &ForceSourceUseCase{
+		writer: writer,
+		logger: logger,
+	}
+}
+
+func (uc *ForceSourceUseCase) Execute(ctx context.Context, source string, forced bool) error {
+	if err := uc.writer.SetSourceForced(ctx, source, forced); err != nil {
+		return err
+	}
+
+	uc.logger.Info("source override updated", "source", source, "forced", forced)
+	return nil
+}
@@ -0,0 +1,34 @@
+package proxy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/mocks"
+)
+
+func TestForceSourceUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	logger := getProxiesTestLogger{}
+
+	t.Run("sets override", func(t *testing.T) {
+		writer := mocks.NewSourceOverrideWriter(t)
+		writer.EXPECT().SetSourceForced(ctx, "s1", true).Return(nil)
+
+		err := proxy.NewForceSourceUseCase(writer, logger).Execute(ctx, "s1", true)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("propagates not found", func(t *testing.T) {
+		writer := mocks.NewSourceOverrideWriter(t)
+		writer.EXPECT().SetSourceForced(ctx, "missing", false).Return(proxy.ErrSourceNotFound)
+
+		err := proxy.NewForceSourceUseCase(writer, logger).Execute(ctx, "missing", false)
+
+		assert.ErrorIs(t, err, proxy.ErrSourceNotFound)
+	})
+}
@@ -12,6 +12,9 @@ type GetProxiesLogger interface {
 type FilterOptions struct {
 	Protocol   string
 	Anonymity  string
+	Capability string
+	Country    string
+	ASN        uint
 	MaxLatency time.Duration
 }
 
@@ -24,6 +27,9 @@ type GetProxiesInput struct {
 	Limit      int
 	Protocol   string
 	Anonymity  string
+	Capability string
+	Country    string
+	ASN        uint
 	MaxLatency time.Duration
 }
 
@@ -49,6 +55,9 @@ func (uc *GetProxiesUseCase) Execute(ctx context.Context, input GetProxiesInput)
 	filters := FilterOptions{
 		Protocol:   input.Protocol,
 		Anonymity:  input.Anonymity,
+		Capability: input.Capability,
+		Country:    input.Country,
+		ASN:        input.ASN,
 		MaxLatency: input.MaxLatency,
 	}
 
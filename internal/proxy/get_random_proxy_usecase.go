@@ -15,15 +15,25 @@ type GetRandomProxyLogger interface {
 	Debug(msg string, args ...any)
 }
 
+type SessionStore interface {
+	GetSession(ctx context.Context, session string) (string, error)
+	SetSession(ctx context.Context, session, address string, ttl time.Duration) error
+}
+
 type GetRandomProxyInput struct {
-	Protocol   string
-	Anonymity  string
-	MaxLatency time.Duration
+	Protocol     string
+	Anonymity    string
+	Capability   string
+	MaxLatency   time.Duration
+	Session      string
+	DistinctExit bool
 }
 
 type GetRandomProxyUseCase struct {
-	reader Reader
-	logger GetRandomProxyLogger
+	reader     Reader
+	sessions   SessionStore
+	sessionTTL time.Duration
+	logger     GetRandomProxyLogger
 }
 
 func NewGetRandomProxyUseCase(reader Reader, logger GetRandomProxyLogger) *GetRandomProxyUseCase {
@@ -33,14 +43,49 @@ func NewGetRandomProxyUseCase(reader Reader, logger GetRandomProxyLogger) *GetRa
 	}
 }
 
+func (uc *GetRandomProxyUseCase) WithSessions(store SessionStore, ttl time.Duration) *GetRandomProxyUseCase {
+	uc.sessions = store
+	uc.sessionTTL = ttl
+	return uc
+}
+
 func (uc *GetRandomProxyUseCase) Execute(ctx context.Context, input GetRandomProxyInput) (*Proxy, error) {
-	filters := FilterOptions(input)
+	filters := FilterOptions{
+		Protocol:   input.Protocol,
+		Anonymity:  input.Anonymity,
+		Capability: input.Capability,
+		MaxLatency: input.MaxLatency,
+	}
 
 	proxies, _, _, err := uc.reader.GetAlive(ctx, 0, 0, filters)
 	if err != nil {
 		return nil, err
 	}
 
+	sticky := input.Session != "" && uc.sessions != nil
+
+	if sticky {
+		pinned, err := uc.sessions.GetSession(ctx, input.Session)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range proxies {
+			if pinned != "" && p.Address() == pinned {
+				// each use extends the session, so it only rotates once idle
+				if err := uc.sessions.SetSession(ctx, input.Session, pinned, uc.sessionTTL); err != nil {
+					return nil, err
+				}
+				uc.logger.Debug("reusing pinned proxy", "session", input.Session, "address", pinned)
+				return p, nil
+			}
+		}
+	}
+
+	if input.DistinctExit {
+		proxies = DistinctExit(proxies)
+	}
+
 	if len(proxies) == 0 {
 		return nil, ErrNoProxiesAvailable
 	}
@@ -52,5 +97,12 @@ func (uc *GetRandomProxyUseCase) Execute(ctx context.Context, input GetRandomPro
 	selected := proxies[n.Int64()]
 	uc.logger.Debug("selected random proxy", "address", selected.Address())
 
+	if sticky {
+		if err := uc.sessions.SetSession(ctx, input.Session, selected.Address(), uc.sessionTTL); err != nil {
+			return nil, err
+		}
+		uc.logger.Debug("pinned proxy to session", "session", input.Session, "address", selected.Address())
+	}
+
 	return selected, nil
 }
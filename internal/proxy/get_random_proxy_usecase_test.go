@@ -40,6 +40,40 @@ func TestGetRandomProxyUseCase_Execute(t *testing.T) {
 		assert.Equal(t, "1.1.1.1:8080", result.Address())
 	})
 
+	t.Run("filters by capability", func(t *testing.T) {
+		p1 := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
+
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, proxy.FilterOptions{Protocol: "http", Capability: "connect"}).
+			Return([]*proxy.Proxy{p1}, float64(0), 1, nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger)
+		_, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Protocol: "http", Capability: "connect"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("picks one proxy per exit ip when distinct", func(t *testing.T) {
+		slow := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
+		slow.MarkSuccess(300*time.Millisecond, proxy.Elite)
+		slow.ExitIP = "9.9.9.9"
+		fast := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "source1")
+		fast.MarkSuccess(50*time.Millisecond, proxy.Elite)
+		fast.ExitIP = "9.9.9.9"
+
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{slow, fast}, float64(0), 2, nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger)
+		result, err := uc.Execute(ctx, proxy.GetRandomProxyInput{DistinctExit: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, "2.2.2.2:8080", result.Address())
+	})
+
 	t.Run("returns error when no proxies", func(t *testing.T) {
 		reader := mocks.NewReader(t)
 		reader.EXPECT().
@@ -64,3 +98,92 @@ func TestGetRandomProxyUseCase_Execute(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestGetRandomProxyUseCase_Execute_Session(t *testing.T) {
+	ctx := context.Background()
+	logger := getRandomTestLogger{}
+
+	p1 := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
+	p2 := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "source1")
+
+	t.Run("reuses pinned proxy while alive and extends the session", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{p1, p2}, float64(0), 2, nil)
+
+		sessions := mocks.NewSessionStore(t)
+		sessions.EXPECT().GetSession(ctx, "login").Return("2.2.2.2:8080", nil)
+		sessions.EXPECT().SetSession(ctx, "login", "2.2.2.2:8080", time.Minute).Return(nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger).WithSessions(sessions, time.Minute)
+		result, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Session: "login"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "2.2.2.2:8080", result.Address())
+	})
+
+	t.Run("pins new proxy when session is empty", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{p1}, float64(0), 1, nil)
+
+		sessions := mocks.NewSessionStore(t)
+		sessions.EXPECT().GetSession(ctx, "login").Return("", nil)
+		sessions.EXPECT().SetSession(ctx, "login", "1.1.1.1:8080", time.Minute).Return(nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger).WithSessions(sessions, time.Minute)
+		result, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Session: "login"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.1.1:8080", result.Address())
+	})
+
+	t.Run("repins when pinned proxy is no longer alive", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{p1}, float64(0), 1, nil)
+
+		sessions := mocks.NewSessionStore(t)
+		sessions.EXPECT().GetSession(ctx, "login").Return("9.9.9.9:8080", nil)
+		sessions.EXPECT().SetSession(ctx, "login", "1.1.1.1:8080", time.Minute).Return(nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger).WithSessions(sessions, time.Minute)
+		result, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Session: "login"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.1.1:8080", result.Address())
+	})
+
+	t.Run("returns error when pinned proxy is gone and pool is empty", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{}, float64(0), 0, nil)
+
+		sessions := mocks.NewSessionStore(t)
+		sessions.EXPECT().GetSession(ctx, "login").Return("1.1.1.1:8080", nil)
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger).WithSessions(sessions, time.Minute)
+		_, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Session: "login"})
+
+		assert.ErrorIs(t, err, proxy.ErrNoProxiesAvailable)
+	})
+
+	t.Run("propagates session store error", func(t *testing.T) {
+		reader := mocks.NewReader(t)
+		reader.EXPECT().
+			GetAlive(ctx, float64(0), 0, mock.AnythingOfType("proxy.FilterOptions")).
+			Return([]*proxy.Proxy{p1}, float64(0), 1, nil)
+
+		sessions := mocks.NewSessionStore(t)
+		sessions.EXPECT().GetSession(ctx, "login").Return("", errors.New("redis down"))
+
+		uc := proxy.NewGetRandomProxyUseCase(reader, logger).WithSessions(sessions, time.Minute)
+		_, err := uc.Execute(ctx, proxy.GetRandomProxyInput{Session: "login"})
+
+		assert.Error(t, err)
+	})
+}
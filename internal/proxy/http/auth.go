@@ -0,0 +1,211 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+)
+
+const apiKeyCtxKey ctxKey = "api_key"
+
+type Authenticator interface {
+	Execute(ctx context.Context, token string, scope auth.Scope) (*auth.APIKey, error)
+}
+
+type KeyManager interface {
+	Create(ctx context.Context, input auth.CreateKeyInput) (auth.CreateKeyOutput, error)
+	List(ctx context.Context) ([]auth.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+}
+
+type CreateKeyRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	PerMinute int      `json:"per_minute"`
+	PerDay    int      `json:"per_day"`
+}
+
+type KeyResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	PerMinute int       `json:"per_minute"`
+	PerDay    int       `json:"per_day"`
+	CreatedAt time.Time `json:"created_at"`
+	Key       string    `json:"key,omitempty"`
+}
+
+type KeysResponse struct {
+	Data []KeyResponse `json:"data"`
+}
+
+func toKeyResponse(k auth.APIKey) KeyResponse {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+	return KeyResponse{
+		ID:        k.ID,
+		Name:      k.Name,
+		Scopes:    scopes,
+		PerMinute: k.PerMinute,
+		PerDay:    k.PerDay,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+func APIKeyFromContext(ctx context.Context) *auth.APIKey {
+	if k, ok := ctx.Value(apiKeyCtxKey).(*auth.APIKey); ok {
+		return k
+	}
+	return nil
+}
+
+func (h *Handler) RequireScope(scope auth.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if h.authenticator == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, "missing api key")
+				return
+			}
+
+			key, err := h.authenticator.Execute(r.Context(), token, scope)
+			if err != nil {
+				h.writeAuthError(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), apiKeyCtxKey, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func (h *Handler) writeAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	var quotaErr *auth.QuotaError
+
+	switch {
+	case errors.Is(err, auth.ErrInvalidKey):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "invalid api key")
+	case errors.Is(err, auth.ErrInsufficientScope):
+		writeError(w, http.StatusForbidden, "insufficient scope")
+	case errors.As(err, &quotaErr):
+		retryAfter := int(math.Ceil(quotaErr.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		writeError(w, http.StatusTooManyRequests, quotaErr.Error())
+	default:
+		h.getLogger(r).Error("failed to authenticate request", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+	}
+}
+
+func (h *Handler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+
+	var req CreateKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeValidationError(w, []FieldError{{Field: "body", Message: "must be valid JSON"}})
+		return
+	}
+
+	var errs []FieldError
+	if strings.TrimSpace(req.Name) == "" {
+		errs = append(errs, FieldError{Field: "name", Message: "is required"})
+	}
+
+	scopes := make([]auth.Scope, 0, len(req.Scopes))
+	for _, s := range req.Scopes {
+		scope, err := auth.ParseScope(s)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "scopes", Message: "must be one of: read-list, read-random, admin"})
+			break
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(req.Scopes) == 0 {
+		errs = append(errs, FieldError{Field: "scopes", Message: "at least one scope is required"})
+	}
+
+	if req.PerMinute < 0 {
+		errs = append(errs, FieldError{Field: "per_minute", Message: "must be zero (unlimited) or positive"})
+	}
+	if req.PerDay < 0 {
+		errs = append(errs, FieldError{Field: "per_day", Message: "must be zero (unlimited) or positive"})
+	}
+
+	if len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	out, err := h.keys.Create(r.Context(), auth.CreateKeyInput{
+		Name:      req.Name,
+		Scopes:    scopes,
+		PerMinute: req.PerMinute,
+		PerDay:    req.PerDay,
+	})
+	if err != nil {
+		logger.Error("failed to create api key", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := toKeyResponse(out.Key)
+	resp.Key = out.Token
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+
+	keys, err := h.keys.List(r.Context())
+	if err != nil {
+		logger.Error("failed to list api keys", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	data := make([]KeyResponse, len(keys))
+	for i, k := range keys {
+		data[i] = toKeyResponse(k)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(KeysResponse{Data: data})
+}
+
+func (h *Handler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+	id := chi.URLParam(r, "id")
+
+	if err := h.keys.Revoke(r.Context(), id); err != nil {
+		if errors.Is(err, auth.ErrKeyNotFound) {
+			writeError(w, http.StatusNotFound, "api key not found")
+			return
+		}
+		logger.Error("failed to revoke api key", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,292 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/http/mocks"
+)
+
+func newAuthRouter(t *testing.T, authenticator proxyhttp.Authenticator, keys proxyhttp.KeyManager, p *proxy.Proxy) http.Handler {
+	t.Helper()
+
+	logger := testLogger{}
+	handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{proxy: p}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+		WithAuth(authenticator)
+	if keys != nil {
+		handler.WithKeys(keys)
+	}
+	return proxyhttp.NewRouter(handler, logger)
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var body proxyhttp.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Error
+}
+
+func TestHandler_Auth(t *testing.T) {
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+
+	reader := &auth.APIKey{ID: "reader", Scopes: []auth.Scope{auth.ScopeReadRandom}}
+
+	t.Run("leaves health public", func(t *testing.T) {
+		router := newAuthRouter(t, mocks.NewAuthenticator(t), nil, p)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("returns 401 without bearer token", func(t *testing.T) {
+		router := newAuthRouter(t, mocks.NewAuthenticator(t), nil, p)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, "missing api key", decodeError(t, rec))
+	})
+
+	t.Run("returns 401 for unknown key", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "bad", auth.ScopeReadRandom).Return(nil, auth.ErrInvalidKey)
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set("Authorization", "Bearer bad")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "invalid api key", decodeError(t, rec))
+	})
+
+	t.Run("returns 403 for missing scope", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeReadList).Return(reader, auth.ErrInsufficientScope)
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "insufficient scope", decodeError(t, rec))
+	})
+
+	t.Run("requires admin scope for sources", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeAdmin).Return(reader, auth.ErrInsufficientScope)
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/sources/s1/force", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("returns 429 with retry-after when quota is exceeded", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeReadRandom).
+			Return(reader, &auth.QuotaError{Window: "per-minute", Limit: 10, RetryAfter: 1500 * time.Millisecond})
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "per-minute quota of 10 requests exceeded", decodeError(t, rec))
+	})
+
+	t.Run("returns 500 when authentication fails unexpectedly", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeReadRandom).Return(nil, errors.New("redis down"))
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("serves request with valid key", func(t *testing.T) {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeReadRandom).Return(reader, nil)
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestHandler_Credentials(t *testing.T) {
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
+	p.Username = "alice"
+	p.Password = "s3cret"
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+
+	fetch := func(t *testing.T, key *auth.APIKey) proxyhttp.ProxyResponse {
+		t.Helper()
+
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "k", auth.ScopeReadRandom).Return(key, nil)
+		router := newAuthRouter(t, authenticator, nil, p)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set("Authorization", "Bearer k")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result proxyhttp.ProxyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		return result
+	}
+
+	t.Run("hides credentials from non-admin keys", func(t *testing.T) {
+		result := fetch(t, &auth.APIKey{ID: "r", Scopes: []auth.Scope{auth.ScopeReadRandom}})
+		assert.True(t, result.HasAuth)
+		assert.Empty(t, result.Username)
+		assert.Empty(t, result.Password)
+	})
+
+	t.Run("returns credentials to admin keys", func(t *testing.T) {
+		result := fetch(t, &auth.APIKey{ID: "a", Scopes: []auth.Scope{auth.ScopeAdmin}})
+		assert.Equal(t, "alice", result.Username)
+		assert.Equal(t, "s3cret", result.Password)
+	})
+
+	t.Run("hides credentials when auth is disabled", func(t *testing.T) {
+		logger := testLogger{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{proxy: p}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result proxyhttp.ProxyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Empty(t, result.Username)
+	})
+}
+
+func TestHandler_Keys(t *testing.T) {
+	admin := &auth.APIKey{ID: "admin", Scopes: []auth.Scope{auth.ScopeAdmin}}
+
+	adminAuth := func(t *testing.T) proxyhttp.Authenticator {
+		authenticator := mocks.NewAuthenticator(t)
+		authenticator.EXPECT().Execute(mock.Anything, "admin", auth.ScopeAdmin).Return(admin, nil)
+		return authenticator
+	}
+
+	do := func(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("creates key and returns the token once", func(t *testing.T) {
+		keys := mocks.NewKeyManager(t)
+		keys.EXPECT().Create(mock.Anything, auth.CreateKeyInput{
+			Name:      "bot",
+			Scopes:    []auth.Scope{auth.ScopeReadRandom},
+			PerMinute: 60,
+		}).Return(auth.CreateKeyOutput{
+			Key:   auth.APIKey{ID: "abc", Name: "bot", Scopes: []auth.Scope{auth.ScopeReadRandom}, PerMinute: 60},
+			Token: "pe_token",
+		}, nil)
+		router := newAuthRouter(t, adminAuth(t), keys, nil)
+
+		rec := do(router, http.MethodPost, "/api/v1/keys", `{"name":"bot","scopes":["read-random"],"per_minute":60}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		var resp proxyhttp.KeyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "abc", resp.ID)
+		assert.Equal(t, "pe_token", resp.Key)
+		assert.Equal(t, []string{"read-random"}, resp.Scopes)
+	})
+
+	t.Run("validates create request", func(t *testing.T) {
+		router := newAuthRouter(t, adminAuth(t), mocks.NewKeyManager(t), nil)
+
+		rec := do(router, http.MethodPost, "/api/v1/keys", `{"scopes":["write"],"per_day":-1}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp proxyhttp.ValidationError
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		fields := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			fields[i] = e.Field
+		}
+		assert.ElementsMatch(t, []string{"name", "scopes", "per_day"}, fields)
+	})
+
+	t.Run("lists keys without tokens", func(t *testing.T) {
+		keys := mocks.NewKeyManager(t)
+		keys.EXPECT().List(mock.Anything).Return([]auth.APIKey{{ID: "abc", Name: "bot"}}, nil)
+		router := newAuthRouter(t, adminAuth(t), keys, nil)
+
+		rec := do(router, http.MethodGet, "/api/v1/keys", "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), `"key"`)
+		var resp proxyhttp.KeysResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "abc", resp.Data[0].ID)
+	})
+
+	t.Run("revokes key", func(t *testing.T) {
+		keys := mocks.NewKeyManager(t)
+		keys.EXPECT().Revoke(mock.Anything, "abc").Return(nil)
+		router := newAuthRouter(t, adminAuth(t), keys, nil)
+
+		rec := do(router, http.MethodDelete, "/api/v1/keys/abc", "")
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("returns 404 for unknown key", func(t *testing.T) {
+		keys := mocks.NewKeyManager(t)
+		keys.EXPECT().Revoke(mock.Anything, "missing").Return(auth.ErrKeyNotFound)
+		router := newAuthRouter(t, adminAuth(t), keys, nil)
+
+		rec := do(router, http.MethodDelete, "/api/v1/keys/missing", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
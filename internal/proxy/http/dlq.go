@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	ErrDeadLetterNotFound  = errors.New("dead letter not found")
+	ErrInvalidDeadLetterID = errors.New("invalid dead letter id")
+
+	// deadLetterIDPattern accepts both Redis stream ids and the sequence
+	// numbers of the other queue backends; the backend rejects the other
+	// kind with ErrInvalidDeadLetterID.
+	deadLetterIDPattern = regexp.MustCompile(`^\d+(-\d+)?$`)
+)
+
+type DeadLetter struct {
+	ID         string
+	SourceID   string
+	Reason     string
+	Deliveries int64
+	FailedAt   time.Time
+	Payload    []byte
+}
+
+type DeadLetterQueue interface {
+	List(ctx context.Context, after string, limit int) ([]DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+}
+
+type DeadLetterResponse struct {
+	ID         string    `json:"id"`
+	SourceID   string    `json:"source_id"`
+	Reason     string    `json:"reason"`
+	Deliveries int64     `json:"deliveries"`
+	FailedAt   time.Time `json:"failed_at"`
+	Payload    string    `json:"payload"`
+}
+
+type DeadLettersResponse struct {
+	Data       []DeadLetterResponse `json:"data"`
+	NextCursor *string              `json:"next_cursor,omitempty"`
+	Limit      int                  `json:"limit"`
+}
+
+func (h *Handler) WithDeadLetters(dlq DeadLetterQueue) *Handler {
+	h.deadLetters = dlq
+	return h
+}
+
+func (h *Handler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+	q := r.URL.Query()
+
+	var errs []FieldError
+	cursor := q.Get("cursor")
+	if cursor != "" && !deadLetterIDPattern.MatchString(cursor) {
+		errs = append(errs, FieldError{Field: "cursor", Message: "invalid cursor format"})
+	}
+
+	limit := defaultLimit
+	if l := q.Get("limit"); l != "" {
+		val, err := strconv.Atoi(l)
+		if err != nil || val <= 0 {
+			errs = append(errs, FieldError{Field: "limit", Message: "must be a positive integer"})
+		} else {
+			limit = min(val, maxLimit)
+		}
+	}
+
+	if len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	letters, err := h.deadLetters.List(r.Context(), cursor, limit)
+	if errors.Is(err, ErrInvalidDeadLetterID) {
+		writeValidationError(w, []FieldError{{Field: "cursor", Message: "invalid cursor format"}})
+		return
+	}
+	if err != nil {
+		logger.Error("failed to list dead letters", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	response := DeadLettersResponse{
+		Data:  make([]DeadLetterResponse, len(letters)),
+		Limit: limit,
+	}
+	for i, dl := range letters {
+		response.Data[i] = DeadLetterResponse{
+			ID:         dl.ID,
+			SourceID:   dl.SourceID,
+			Reason:     dl.Reason,
+			Deliveries: dl.Deliveries,
+			FailedAt:   dl.FailedAt,
+			Payload:    string(dl.Payload),
+		}
+	}
+	if len(letters) == limit {
+		next := letters[len(letters)-1].ID
+		response.NextCursor = &next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+	id := chi.URLParam(r, "id")
+
+	if !deadLetterIDPattern.MatchString(id) {
+		writeValidationError(w, []FieldError{{Field: "id", Message: "invalid dead letter id"}})
+		return
+	}
+
+	if err := h.deadLetters.Replay(r.Context(), id); err != nil {
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			writeError(w, http.StatusNotFound, "dead letter not found")
+			return
+		}
+		if errors.Is(err, ErrInvalidDeadLetterID) {
+			writeValidationError(w, []FieldError{{Field: "id", Message: "invalid dead letter id"}})
+			return
+		}
+		logger.Error("failed to replay dead letter", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/http/mocks"
+)
+
+func newDLQRouter(dlq proxyhttp.DeadLetterQueue) http.Handler {
+	logger := testLogger{}
+	handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+		WithAuth(adminAuthenticator{}).
+		WithDeadLetters(dlq)
+	return proxyhttp.NewRouter(handler, logger)
+}
+
+func TestHandler_DeadLetters(t *testing.T) {
+	failedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("lists dead letters with next cursor on full page", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().List(mock.Anything, "1-0", 1).Return([]proxyhttp.DeadLetter{{
+			ID:         "2-0",
+			SourceID:   "1-5",
+			Reason:     "save failed",
+			Deliveries: 5,
+			FailedAt:   failedAt,
+			Payload:    []byte(`{"ip":"1.1.1.1"}`),
+		}}, nil)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodGet, "/api/v1/dlq?cursor=1-0&limit=1"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp proxyhttp.DeadLettersResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "save failed", resp.Data[0].Reason)
+		assert.Equal(t, int64(5), resp.Data[0].Deliveries)
+		assert.Equal(t, `{"ip":"1.1.1.1"}`, resp.Data[0].Payload)
+		require.NotNil(t, resp.NextCursor)
+		assert.Equal(t, "2-0", *resp.NextCursor)
+	})
+
+	t.Run("omits next cursor on last page", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().List(mock.Anything, "", 25).Return(nil, nil)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodGet, "/api/v1/dlq"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp proxyhttp.DeadLettersResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Empty(t, resp.Data)
+		assert.Nil(t, resp.NextCursor)
+	})
+
+	t.Run("rejects invalid cursor", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newDLQRouter(mocks.NewDeadLetterQueue(t)).ServeHTTP(rec, adminRequest(http.MethodGet, "/api/v1/dlq?cursor=abc"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("rejects cursor of another backend", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().List(mock.Anything, "1-0", 25).Return(nil, proxyhttp.ErrInvalidDeadLetterID)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodGet, "/api/v1/dlq?cursor=1-0"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("replays dead letter by sequence", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().Replay(mock.Anything, "7").Return(nil)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodPost, "/api/v1/dlq/7/replay"))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("replays dead letter", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().Replay(mock.Anything, "2-0").Return(nil)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodPost, "/api/v1/dlq/2-0/replay"))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("returns 404 for unknown dead letter", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().Replay(mock.Anything, "2-0").Return(proxyhttp.ErrDeadLetterNotFound)
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodPost, "/api/v1/dlq/2-0/replay"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("returns 500 on replay failure", func(t *testing.T) {
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().Replay(mock.Anything, "2-0").Return(errors.New("redis down"))
+
+		rec := httptest.NewRecorder()
+		newDLQRouter(dlq).ServeHTTP(rec, adminRequest(http.MethodPost, "/api/v1/dlq/2-0/replay"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("is not routed without authentication", func(t *testing.T) {
+		logger := testLogger{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+			WithDeadLetters(mocks.NewDeadLetterQueue(t))
+
+		rec := httptest.NewRecorder()
+		proxyhttp.NewRouter(handler, logger).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/dlq", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("is not routed without a queue", func(t *testing.T) {
+		logger := testLogger{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+			WithAuth(adminAuthenticator{})
+
+		rec := httptest.NewRecorder()
+		proxyhttp.NewRouter(handler, logger).ServeHTTP(rec, adminRequest(http.MethodGet, "/api/v1/dlq"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -8,8 +8,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
 	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
 )
 
@@ -50,6 +54,9 @@ type GetProxiesInput struct {
 	Limit      int
 	Protocol   string
 	Anonymity  string
+	Capability string
+	Country    string
+	ASN        uint
 	MaxLatency time.Duration
 }
 
@@ -64,33 +71,77 @@ type GetProxiesUseCase interface {
 }
 
 type GetRandomProxyInput struct {
-	Protocol   string
-	Anonymity  string
-	MaxLatency time.Duration
+	Protocol     string
+	Anonymity    string
+	MaxLatency   time.Duration
+	Session      string
+	DistinctExit bool
 }
 
 type GetRandomProxyUseCase interface {
 	Execute(ctx context.Context, input GetRandomProxyInput) (*proxy.Proxy, error)
 }
 
+type ListSourcesUseCase interface {
+	Execute(ctx context.Context) ([]proxy.SourceStats, error)
+}
+
+type ForceSourceUseCase interface {
+	Execute(ctx context.Context, source string, forced bool) error
+}
+
 type Handler struct {
 	getProxies     GetProxiesUseCase
 	getRandomProxy GetRandomProxyUseCase
+	listSources    ListSourcesUseCase
+	forceSource    ForceSourceUseCase
 	logger         Logger
+
+	authenticator Authenticator
+	keys          KeyManager
+	deadLetters   DeadLetterQueue
+
+	metrics         RequestMetrics
+	metricsExporter http.Handler
 }
 
 func NewHandler(
 	getProxies GetProxiesUseCase,
 	getRandomProxy GetRandomProxyUseCase,
+	listSources ListSourcesUseCase,
+	forceSource ForceSourceUseCase,
 	logger Logger,
 ) *Handler {
 	return &Handler{
 		getProxies:     getProxies,
 		getRandomProxy: getRandomProxy,
+		listSources:    listSources,
+		forceSource:    forceSource,
 		logger:         logger,
 	}
 }
 
+func (h *Handler) WithAuth(authenticator Authenticator) *Handler {
+	h.authenticator = authenticator
+	return h
+}
+
+func (h *Handler) WithKeys(keys KeyManager) *Handler {
+	h.keys = keys
+	return h
+}
+
+func (h *Handler) WithMetrics(metrics RequestMetrics, exporter http.Handler) *Handler {
+	h.metrics = metrics
+	h.metricsExporter = exporter
+	return h
+}
+
+func (h *Handler) canViewCredentials(r *http.Request) bool {
+	key := APIKeyFromContext(r.Context())
+	return key != nil && key.HasScope(auth.ScopeAdmin)
+}
+
 func (h *Handler) getLogger(r *http.Request) Logger {
 	if l := LoggerFromContext(r.Context()); l != nil {
 		return l
@@ -104,16 +155,57 @@ type ProxyResponse struct {
 	Anonymity string `json:"anonymity"`
 	Latency   int64  `json:"latency_ms"`
 	Source    string `json:"source"`
+	HasAuth   bool   `json:"has_auth"`
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+
+	SupportsConnect   bool `json:"supports_connect"`
+	SupportsPlainHTTP bool `json:"supports_plain_http"`
+
+	ExitIP  string `json:"exit_ip,omitempty"`
+	Country string `json:"country,omitempty"`
+	City    string `json:"city,omitempty"`
+	ASN     uint   `json:"asn,omitempty"`
 }
 
-func toResponse(p *proxy.Proxy) ProxyResponse {
-	return ProxyResponse{
+func toResponse(p *proxy.Proxy, withCredentials bool) ProxyResponse {
+	resp := ProxyResponse{
 		Address:   p.Address(),
 		Protocol:  string(p.Protocol),
 		Anonymity: string(p.Anonymity),
 		Latency:   p.Latency.Milliseconds(),
 		Source:    p.Source,
+		HasAuth:   p.HasAuth(),
+
+		SupportsConnect:   p.SupportsConnect,
+		SupportsPlainHTTP: p.SupportsPlainHTTP,
+
+		ExitIP:  p.ExitIP,
+		Country: p.Country,
+		City:    p.City,
+		ASN:     p.ASN,
+	}
+	if withCredentials {
+		resp.Username = p.Username
+		resp.Password = p.Password
 	}
+	return resp
+}
+
+type SourceResponse struct {
+	Name             string     `json:"name"`
+	Scraped          int64      `json:"scraped"`
+	Published        int64      `json:"published"`
+	Verified         int64      `json:"verified"`
+	AvgLatencyMs     int64      `json:"avg_latency_ms"`
+	Quarantined      bool       `json:"quarantined"`
+	QuarantinedUntil *time.Time `json:"quarantined_until,omitempty"`
+	Strikes          int        `json:"strikes"`
+	Forced           bool       `json:"forced"`
+}
+
+type SourcesResponse struct {
+	Data []SourceResponse `json:"data"`
 }
 
 type PaginatedResponse struct {
@@ -124,24 +216,13 @@ type PaginatedResponse struct {
 }
 
 const (
-	defaultLimit = 25
-	maxLimit     = 100
-)
+	defaultLimit     = 25
+	maxLimit         = 100
+	maxSessionLength = 128
 
-var (
-	validProtocols   = []string{"http", "https", "socks4", "socks5"}
-	validAnonymities = []string{"transparent", "anonymous", "elite"}
+	SessionHeader = "X-Proxy-Session"
 )
 
-func isValidEnum(value string, validValues []string) bool {
-	for _, v := range validValues {
-		if value == v {
-			return true
-		}
-	}
-	return false
-}
-
 func encodeCursor(score float64) string {
 	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%f", score)))
 }
@@ -190,7 +271,7 @@ func parseFilters(r *http.Request) (protocol, anonymity string, maxLatency time.
 	q := r.URL.Query()
 
 	if p := q.Get("protocol"); p != "" {
-		if !isValidEnum(p, validProtocols) {
+		if _, ok := proxy.ParseProtocol(p); !ok {
 			errs = append(errs, FieldError{Field: "protocol", Message: "must be one of: http, https, socks4, socks5"})
 		} else {
 			protocol = p
@@ -198,7 +279,7 @@ func parseFilters(r *http.Request) (protocol, anonymity string, maxLatency time.
 	}
 
 	if a := q.Get("anonymity"); a != "" {
-		if !isValidEnum(a, validAnonymities) {
+		if _, ok := proxy.ParseAnonymity(a); !ok {
 			errs = append(errs, FieldError{Field: "anonymity", Message: "must be one of: transparent, anonymous, elite"})
 		} else {
 			anonymity = a
@@ -219,6 +300,82 @@ func parseFilters(r *http.Request) (protocol, anonymity string, maxLatency time.
 	return protocol, anonymity, maxLatency, errs
 }
 
+func parseCapability(r *http.Request) (capability string, errs []FieldError) {
+	c := r.URL.Query().Get("capability")
+	if c == "" {
+		return "", nil
+	}
+
+	if _, ok := proxy.ParseCapability(c); !ok {
+		errs = append(errs, FieldError{Field: "capability", Message: "must be one of: connect, plain_http"})
+		return "", errs
+	}
+	return c, nil
+}
+
+func parseLocation(r *http.Request) (country string, asn uint, errs []FieldError) {
+	q := r.URL.Query()
+
+	if c := q.Get("country"); c != "" {
+		if !isCountryCode(c) {
+			errs = append(errs, FieldError{Field: "country", Message: "must be a two-letter ISO 3166 country code"})
+		} else {
+			country = strings.ToUpper(c)
+		}
+	}
+
+	if a := q.Get("asn"); a != "" {
+		val, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(a), "AS"), 10, 32)
+		if err != nil || val == 0 {
+			errs = append(errs, FieldError{Field: "asn", Message: "must be a positive AS number"})
+		} else {
+			asn = uint(val)
+		}
+	}
+
+	return country, asn, errs
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func parseDistinctExit(r *http.Request) (distinct bool, errs []FieldError) {
+	v := r.URL.Query().Get("distinct_exit")
+	if v == "" {
+		return false, nil
+	}
+
+	distinct, err := strconv.ParseBool(v)
+	if err != nil {
+		errs = append(errs, FieldError{Field: "distinct_exit", Message: "must be true or false"})
+		return false, errs
+	}
+	return distinct, nil
+}
+
+func parseSession(r *http.Request) (session string, errs []FieldError) {
+	session = r.URL.Query().Get("session")
+	if session == "" {
+		session = r.Header.Get(SessionHeader)
+	}
+
+	if len(session) > maxSessionLength {
+		errs = append(errs, FieldError{Field: "session", Message: fmt.Sprintf("must be at most %d characters", maxSessionLength)})
+		return "", errs
+	}
+
+	return session, nil
+}
+
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
@@ -229,8 +386,16 @@ func (h *Handler) GetProxies(w http.ResponseWriter, r *http.Request) {
 
 	cursor, limit, paginationErrs := parsePagination(r)
 	protocol, anonymity, maxLatency, filterErrs := parseFilters(r)
+	capability, capabilityErrs := parseCapability(r)
+	country, asn, locationErrs := parseLocation(r)
 
 	allErrs := append(paginationErrs, filterErrs...)
+	allErrs = append(allErrs, capabilityErrs...)
+	allErrs = append(allErrs, locationErrs...)
+	if r.URL.Query().Has("distinct_exit") {
+		// listings page by score; distinct exits are only enforced on the random pick
+		allErrs = append(allErrs, FieldError{Field: "distinct_exit", Message: "only supported on /api/v1/proxies/random"})
+	}
 	if len(allErrs) > 0 {
 		writeValidationError(w, allErrs)
 		return
@@ -241,6 +406,9 @@ func (h *Handler) GetProxies(w http.ResponseWriter, r *http.Request) {
 		Limit:      limit,
 		Protocol:   protocol,
 		Anonymity:  anonymity,
+		Capability: capability,
+		Country:    country,
+		ASN:        asn,
 		MaxLatency: maxLatency,
 	})
 	if err != nil {
@@ -249,9 +417,10 @@ func (h *Handler) GetProxies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	withCredentials := h.canViewCredentials(r)
 	data := make([]ProxyResponse, len(output.Proxies))
 	for i, p := range output.Proxies {
-		data[i] = toResponse(p)
+		data[i] = toResponse(p, withCredentials)
 	}
 
 	response := PaginatedResponse{
@@ -272,16 +441,23 @@ func (h *Handler) GetProxies(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetRandomProxy(w http.ResponseWriter, r *http.Request) {
 	logger := h.getLogger(r)
 
-	protocol, anonymity, maxLatency, errs := parseFilters(r)
-	if len(errs) > 0 {
-		writeValidationError(w, errs)
+	protocol, anonymity, maxLatency, filterErrs := parseFilters(r)
+	session, sessionErrs := parseSession(r)
+	distinctExit, distinctErrs := parseDistinctExit(r)
+
+	allErrs := append(filterErrs, sessionErrs...)
+	allErrs = append(allErrs, distinctErrs...)
+	if len(allErrs) > 0 {
+		writeValidationError(w, allErrs)
 		return
 	}
 
 	p, err := h.getRandomProxy.Execute(r.Context(), GetRandomProxyInput{
-		Protocol:   protocol,
-		Anonymity:  anonymity,
-		MaxLatency: maxLatency,
+		Protocol:     protocol,
+		Anonymity:    anonymity,
+		MaxLatency:   maxLatency,
+		Session:      session,
+		DistinctExit: distinctExit,
 	})
 	if err != nil {
 		if errors.Is(err, proxy.ErrNoProxiesAvailable) {
@@ -294,5 +470,63 @@ func (h *Handler) GetRandomProxy(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(toResponse(p))
+	_ = json.NewEncoder(w).Encode(toResponse(p, h.canViewCredentials(r)))
+}
+
+func (h *Handler) ListSources(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+
+	stats, err := h.listSources.Execute(r.Context())
+	if err != nil {
+		logger.Error("failed to list sources", "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	now := time.Now()
+	data := make([]SourceResponse, len(stats))
+	for i, s := range stats {
+		data[i] = SourceResponse{
+			Name:         s.Name,
+			Scraped:      s.Scraped,
+			Published:    s.Published,
+			Verified:     s.Verified,
+			AvgLatencyMs: s.AvgLatency.Milliseconds(),
+			Quarantined:  s.IsQuarantined(now),
+			Strikes:      s.Strikes,
+			Forced:       s.Forced,
+		}
+		if data[i].Quarantined {
+			until := s.QuarantinedUntil
+			data[i].QuarantinedUntil = &until
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SourcesResponse{Data: data})
+}
+
+func (h *Handler) ForceSource(w http.ResponseWriter, r *http.Request) {
+	h.setSourceForced(w, r, true)
+}
+
+func (h *Handler) UnforceSource(w http.ResponseWriter, r *http.Request) {
+	h.setSourceForced(w, r, false)
+}
+
+func (h *Handler) setSourceForced(w http.ResponseWriter, r *http.Request, forced bool) {
+	logger := h.getLogger(r)
+	name := chi.URLParam(r, "name")
+
+	if err := h.forceSource.Execute(r.Context(), name, forced); err != nil {
+		if errors.Is(err, proxy.ErrSourceNotFound) {
+			writeError(w, http.StatusNotFound, "source not found")
+			return
+		}
+		logger.Error("failed to update source override", "source", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -5,14 +5,18 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
 	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
 	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/http/mocks"
 )
 
 type testLogger struct{}
@@ -25,6 +29,20 @@ func (l testLogger) With(args ...any) proxyhttp.Logger {
 	return l
 }
 
+// adminAuthenticator accepts any token as an admin key, since admin routes
+// are only served with authentication.
+type adminAuthenticator struct{}
+
+func (adminAuthenticator) Execute(ctx context.Context, token string, scope auth.Scope) (*auth.APIKey, error) {
+	return &auth.APIKey{ID: "admin", Scopes: []auth.Scope{auth.ScopeAdmin}}, nil
+}
+
+func adminRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	return req
+}
+
 type mockGetProxiesUseCase struct {
 	proxies    []*proxy.Proxy
 	nextCursor float64
@@ -48,6 +66,15 @@ func (m *mockGetProxiesUseCase) Execute(ctx context.Context, input proxyhttp.Get
 		if input.MaxLatency > 0 && p.Latency > input.MaxLatency {
 			continue
 		}
+		if input.Capability != "" && !p.Supports(proxy.Capability(input.Capability)) {
+			continue
+		}
+		if input.Country != "" && p.Country != input.Country {
+			continue
+		}
+		if input.ASN != 0 && p.ASN != input.ASN {
+			continue
+		}
 		filtered = append(filtered, p)
 	}
 
@@ -61,15 +88,38 @@ func (m *mockGetProxiesUseCase) Execute(ctx context.Context, input proxyhttp.Get
 type mockGetRandomProxyUseCase struct {
 	proxy *proxy.Proxy
 	err   error
+	input proxyhttp.GetRandomProxyInput
 }
 
 func (m *mockGetRandomProxyUseCase) Execute(ctx context.Context, input proxyhttp.GetRandomProxyInput) (*proxy.Proxy, error) {
+	m.input = input
 	return m.proxy, m.err
 }
 
+type mockListSourcesUseCase struct {
+	stats []proxy.SourceStats
+	err   error
+}
+
+func (m *mockListSourcesUseCase) Execute(ctx context.Context) ([]proxy.SourceStats, error) {
+	return m.stats, m.err
+}
+
+type mockForceSourceUseCase struct {
+	source string
+	forced bool
+	err    error
+}
+
+func (m *mockForceSourceUseCase) Execute(ctx context.Context, source string, forced bool) error {
+	m.source = source
+	m.forced = forced
+	return m.err
+}
+
 func TestHandler_Health(t *testing.T) {
 	logger := testLogger{}
-	handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, logger)
+	handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 	router := proxyhttp.NewRouter(handler, logger)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -84,6 +134,8 @@ func TestHandler_Health(t *testing.T) {
 func TestHandler_GetProxies(t *testing.T) {
 	p1 := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
 	p1.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p1.SupportsConnect = true
+	p1.Country, p1.City, p1.ASN = "BR", "São Paulo", 28573
 
 	p2 := proxy.NewProxy("2.2.2.2", 3128, proxy.SOCKS5, "source2")
 	p2.MarkSuccess(200*time.Millisecond, proxy.Anonymous)
@@ -96,7 +148,7 @@ func TestHandler_GetProxies(t *testing.T) {
 			total:   2,
 		}
 
-		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, logger)
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies", nil)
@@ -121,7 +173,7 @@ func TestHandler_GetProxies(t *testing.T) {
 			total:   2,
 		}
 
-		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, logger)
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?protocol=http", nil)
@@ -142,7 +194,7 @@ func TestHandler_GetProxies(t *testing.T) {
 			total:   2,
 		}
 
-		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, logger)
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?anonymity=elite", nil)
@@ -163,7 +215,7 @@ func TestHandler_GetProxies(t *testing.T) {
 			total:   2,
 		}
 
-		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, logger)
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?max_latency_ms=150", nil)
@@ -177,18 +229,105 @@ func TestHandler_GetProxies(t *testing.T) {
 		assert.Len(t, result.Data, 1)
 		assert.Equal(t, int64(100), result.Data[0].Latency)
 	})
+
+	t.Run("filters by capability", func(t *testing.T) {
+		getProxiesUC := &mockGetProxiesUseCase{
+			proxies: []*proxy.Proxy{p1, p2},
+			total:   2,
+		}
+
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?capability=connect", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		var result proxyhttp.PaginatedResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &result)
+
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, "1.1.1.1:8080", result.Data[0].Address)
+		assert.True(t, result.Data[0].SupportsConnect)
+		assert.False(t, result.Data[0].SupportsPlainHTTP)
+	})
+
+	t.Run("rejects unknown capability", func(t *testing.T) {
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?capability=udp", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "capability")
+	})
+
+	t.Run("filters by country and asn", func(t *testing.T) {
+		getProxiesUC := &mockGetProxiesUseCase{
+			proxies: []*proxy.Proxy{p1, p2},
+			total:   2,
+		}
+
+		handler := proxyhttp.NewHandler(getProxiesUC, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?country=br&asn=AS28573", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		var result proxyhttp.PaginatedResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &result)
+
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, "BR", result.Data[0].Country)
+		assert.Equal(t, "São Paulo", result.Data[0].City)
+		assert.Equal(t, uint(28573), result.Data[0].ASN)
+	})
+
+	t.Run("rejects invalid country and asn", func(t *testing.T) {
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?country=BRA&asn=0", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "country")
+		assert.Contains(t, rec.Body.String(), "asn")
+	})
+
+	t.Run("rejects distinct exit on the listing", func(t *testing.T) {
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies?distinct_exit=true", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "distinct_exit")
+	})
 }
 
 func TestHandler_GetRandomProxy(t *testing.T) {
 	p1 := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "source1")
 	p1.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p1.ExitIP = "9.9.9.9"
 
 	logger := testLogger{}
 
 	t.Run("returns a proxy", func(t *testing.T) {
 		getRandomUC := &mockGetRandomProxyUseCase{proxy: p1}
 
-		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, logger)
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
@@ -208,7 +347,7 @@ func TestHandler_GetRandomProxy(t *testing.T) {
 	t.Run("returns 404 when no proxies", func(t *testing.T) {
 		getRandomUC := &mockGetRandomProxyUseCase{err: proxy.ErrNoProxiesAvailable}
 
-		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, logger)
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
 		router := proxyhttp.NewRouter(handler, logger)
 
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
@@ -218,4 +357,227 @@ func TestHandler_GetRandomProxy(t *testing.T) {
 
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
+	t.Run("passes session from query param", func(t *testing.T) {
+		getRandomUC := &mockGetRandomProxyUseCase{proxy: p1}
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random?session=login-flow", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "login-flow", getRandomUC.input.Session)
+	})
+
+	t.Run("passes session from header", func(t *testing.T) {
+		getRandomUC := &mockGetRandomProxyUseCase{proxy: p1}
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random", nil)
+		req.Header.Set(proxyhttp.SessionHeader, "header-session")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "header-session", getRandomUC.input.Session)
+	})
+
+	t.Run("rejects oversized session", func(t *testing.T) {
+		getRandomUC := &mockGetRandomProxyUseCase{proxy: p1}
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random?session="+strings.Repeat("a", 129), nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("passes distinct exit and returns exit ip", func(t *testing.T) {
+		getRandomUC := &mockGetRandomProxyUseCase{proxy: p1}
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, getRandomUC, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random?distinct_exit=true", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, getRandomUC.input.DistinctExit)
+
+		var result proxyhttp.ProxyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+		assert.Equal(t, "9.9.9.9", result.ExitIP)
+	})
+
+	t.Run("rejects invalid distinct exit", func(t *testing.T) {
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{proxy: p1}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/proxies/random?distinct_exit=maybe", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "distinct_exit")
+	})
+}
+
+func TestHandler_ListSources(t *testing.T) {
+	logger := testLogger{}
+
+	t.Run("returns source stats", func(t *testing.T) {
+		listSources := &mockListSourcesUseCase{
+			stats: []proxy.SourceStats{
+				{Name: "good", Scraped: 100, Published: 90, Verified: 30, AvgLatency: 250 * time.Millisecond},
+				{Name: "noise", Scraped: 500, Published: 500, Strikes: 1, Quarantines: 1, QuarantinedUntil: time.Now().Add(time.Hour)},
+			},
+		}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, listSources, &mockForceSourceUseCase{}, logger).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := adminRequest(http.MethodGet, "/api/v1/sources")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response proxyhttp.SourcesResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, proxyhttp.SourceResponse{Name: "good", Scraped: 100, Published: 90, Verified: 30, AvgLatencyMs: 250}, response.Data[0])
+		assert.Equal(t, int64(0), response.Data[1].Verified)
+		assert.True(t, response.Data[1].Quarantined)
+		assert.NotNil(t, response.Data[1].QuarantinedUntil)
+		assert.Equal(t, 1, response.Data[1].Strikes)
+	})
+
+	t.Run("returns 500 on error", func(t *testing.T) {
+		listSources := &mockListSourcesUseCase{err: assert.AnError}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, listSources, &mockForceSourceUseCase{}, logger).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := adminRequest(http.MethodGet, "/api/v1/sources")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestHandler_ForceSource(t *testing.T) {
+	logger := testLogger{}
+
+	t.Run("forces source on", func(t *testing.T) {
+		forceSource := &mockForceSourceUseCase{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, forceSource, logger).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := adminRequest(http.MethodPost, "/api/v1/sources/TheSpeedX-HTTP/force")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "TheSpeedX-HTTP", forceSource.source)
+		assert.True(t, forceSource.forced)
+	})
+
+	t.Run("removes override", func(t *testing.T) {
+		forceSource := &mockForceSourceUseCase{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, forceSource, logger).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := adminRequest(http.MethodDelete, "/api/v1/sources/TheSpeedX-HTTP/force")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.False(t, forceSource.forced)
+	})
+
+	t.Run("returns 404 for unknown source", func(t *testing.T) {
+		forceSource := &mockForceSourceUseCase{err: proxy.ErrSourceNotFound}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, forceSource, logger).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := adminRequest(http.MethodPost, "/api/v1/sources/unknown/force")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("is not routed without authentication", func(t *testing.T) {
+		forceSource := &mockForceSourceUseCase{}
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, forceSource, logger)
+		router := proxyhttp.NewRouter(handler, logger)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/sources/TheSpeedX-HTTP/force", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, forceSource.source)
+	})
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	logger := testLogger{}
+
+	t.Run("observes requests by route pattern", func(t *testing.T) {
+		recorder := mocks.NewRequestMetrics(t)
+		recorder.EXPECT().ObserveRequest(http.MethodPost, "/api/v1/sources/{name}/force", http.StatusNoContent, mock.Anything).Return()
+		recorder.EXPECT().ObserveRequest(http.MethodGet, "unmatched", http.StatusNotFound, mock.Anything).Return()
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+			WithMetrics(recorder, nil).
+			WithAuth(adminAuthenticator{})
+		router := proxyhttp.NewRouter(handler, logger)
+
+		router.ServeHTTP(httptest.NewRecorder(), adminRequest(http.MethodPost, "/api/v1/sources/s1/force"))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+	})
+
+	t.Run("serves exporter on /metrics", func(t *testing.T) {
+		exporter := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("proxy_engine_up 1\n"))
+		})
+
+		recorder := mocks.NewRequestMetrics(t)
+		recorder.EXPECT().ObserveRequest(http.MethodGet, "/metrics", http.StatusOK, mock.Anything).Return()
+
+		handler := proxyhttp.NewHandler(&mockGetProxiesUseCase{}, &mockGetRandomProxyUseCase{}, &mockListSourcesUseCase{}, &mockForceSourceUseCase{}, logger).
+			WithMetrics(recorder, exporter)
+		handler.WithAuth(mocks.NewAuthenticator(t))
+		router := proxyhttp.NewRouter(handler, logger)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "proxy_engine_up 1\n", rec.Body.String())
+	})
 }
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/JulianoL13/app-proxy-engine/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Authenticator is an autogenerated mock type for the Authenticator type
+type Authenticator struct {
+	mock.Mock
+}
+
+type Authenticator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Authenticator) EXPECT() *Authenticator_Expecter {
+	return &Authenticator_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, token, scope
+func (_m *Authenticator) Execute(ctx context.Context, token string, scope auth.Scope) (*auth.APIKey, error) {
+	ret := _m.Called(ctx, token, scope)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 *auth.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, auth.Scope) (*auth.APIKey, error)); ok {
+		return rf(ctx, token, scope)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, auth.Scope) *auth.APIKey); ok {
+		r0 = rf(ctx, token, scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, auth.Scope) error); ok {
+		r1 = rf(ctx, token, scope)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Authenticator_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type Authenticator_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - scope auth.Scope
+func (_e *Authenticator_Expecter) Execute(ctx interface{}, token interface{}, scope interface{}) *Authenticator_Execute_Call {
+	return &Authenticator_Execute_Call{Call: _e.mock.On("Execute", ctx, token, scope)}
+}
+
+func (_c *Authenticator_Execute_Call) Run(run func(ctx context.Context, token string, scope auth.Scope)) *Authenticator_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(auth.Scope))
+	})
+	return _c
+}
+
+func (_c *Authenticator_Execute_Call) Return(_a0 *auth.APIKey, _a1 error) *Authenticator_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Authenticator_Execute_Call) RunAndReturn(run func(context.Context, string, auth.Scope) (*auth.APIKey, error)) *Authenticator_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuthenticator creates a new instance of Authenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthenticator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Authenticator {
+	mock := &Authenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	http "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+)
+
+// DeadLetterQueue is an autogenerated mock type for the DeadLetterQueue type
+type DeadLetterQueue struct {
+	mock.Mock
+}
+
+type DeadLetterQueue_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeadLetterQueue) EXPECT() *DeadLetterQueue_Expecter {
+	return &DeadLetterQueue_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function with given fields: ctx, after, limit
+func (_m *DeadLetterQueue) List(ctx context.Context, after string, limit int) ([]http.DeadLetter, error) {
+	ret := _m.Called(ctx, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []http.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]http.DeadLetter, error)); ok {
+		return rf(ctx, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []http.DeadLetter); ok {
+		r0 = rf(ctx, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]http.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeadLetterQueue_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type DeadLetterQueue_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - after string
+//   - limit int
+func (_e *DeadLetterQueue_Expecter) List(ctx interface{}, after interface{}, limit interface{}) *DeadLetterQueue_List_Call {
+	return &DeadLetterQueue_List_Call{Call: _e.mock.On("List", ctx, after, limit)}
+}
+
+func (_c *DeadLetterQueue_List_Call) Run(run func(ctx context.Context, after string, limit int)) *DeadLetterQueue_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DeadLetterQueue_List_Call) Return(_a0 []http.DeadLetter, _a1 error) *DeadLetterQueue_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DeadLetterQueue_List_Call) RunAndReturn(run func(context.Context, string, int) ([]http.DeadLetter, error)) *DeadLetterQueue_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replay provides a mock function with given fields: ctx, id
+func (_m *DeadLetterQueue) Replay(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replay")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeadLetterQueue_Replay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replay'
+type DeadLetterQueue_Replay_Call struct {
+	*mock.Call
+}
+
+// Replay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *DeadLetterQueue_Expecter) Replay(ctx interface{}, id interface{}) *DeadLetterQueue_Replay_Call {
+	return &DeadLetterQueue_Replay_Call{Call: _e.mock.On("Replay", ctx, id)}
+}
+
+func (_c *DeadLetterQueue_Replay_Call) Run(run func(ctx context.Context, id string)) *DeadLetterQueue_Replay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DeadLetterQueue_Replay_Call) Return(_a0 error) *DeadLetterQueue_Replay_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeadLetterQueue_Replay_Call) RunAndReturn(run func(context.Context, string) error) *DeadLetterQueue_Replay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeadLetterQueue creates a new instance of DeadLetterQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterQueue(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterQueue {
+	mock := &DeadLetterQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ForceSourceUseCase is an autogenerated mock type for the ForceSourceUseCase type
+type ForceSourceUseCase struct {
+	mock.Mock
+}
+
+type ForceSourceUseCase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ForceSourceUseCase) EXPECT() *ForceSourceUseCase_Expecter {
+	return &ForceSourceUseCase_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, source, forced
+func (_m *ForceSourceUseCase) Execute(ctx context.Context, source string, forced bool) error {
+	ret := _m.Called(ctx, source, forced)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, source, forced)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ForceSourceUseCase_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ForceSourceUseCase_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - forced bool
+func (_e *ForceSourceUseCase_Expecter) Execute(ctx interface{}, source interface{}, forced interface{}) *ForceSourceUseCase_Execute_Call {
+	return &ForceSourceUseCase_Execute_Call{Call: _e.mock.On("Execute", ctx, source, forced)}
+}
+
+func (_c *ForceSourceUseCase_Execute_Call) Run(run func(ctx context.Context, source string, forced bool)) *ForceSourceUseCase_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *ForceSourceUseCase_Execute_Call) Return(_a0 error) *ForceSourceUseCase_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ForceSourceUseCase_Execute_Call) RunAndReturn(run func(context.Context, string, bool) error) *ForceSourceUseCase_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewForceSourceUseCase creates a new instance of ForceSourceUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewForceSourceUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ForceSourceUseCase {
+	mock := &ForceSourceUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
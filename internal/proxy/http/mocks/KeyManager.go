@@ -0,0 +1,200 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/JulianoL13/app-proxy-engine/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyManager is an autogenerated mock type for the KeyManager type
+type KeyManager struct {
+	mock.Mock
+}
+
+type KeyManager_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeyManager) EXPECT() *KeyManager_Expecter {
+	return &KeyManager_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *KeyManager) Create(ctx context.Context, input auth.CreateKeyInput) (auth.CreateKeyOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 auth.CreateKeyOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, auth.CreateKeyInput) (auth.CreateKeyOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, auth.CreateKeyInput) auth.CreateKeyOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(auth.CreateKeyOutput)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, auth.CreateKeyInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyManager_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type KeyManager_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - input auth.CreateKeyInput
+func (_e *KeyManager_Expecter) Create(ctx interface{}, input interface{}) *KeyManager_Create_Call {
+	return &KeyManager_Create_Call{Call: _e.mock.On("Create", ctx, input)}
+}
+
+func (_c *KeyManager_Create_Call) Run(run func(ctx context.Context, input auth.CreateKeyInput)) *KeyManager_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(auth.CreateKeyInput))
+	})
+	return _c
+}
+
+func (_c *KeyManager_Create_Call) Return(_a0 auth.CreateKeyOutput, _a1 error) *KeyManager_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyManager_Create_Call) RunAndReturn(run func(context.Context, auth.CreateKeyInput) (auth.CreateKeyOutput, error)) *KeyManager_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *KeyManager) List(ctx context.Context) ([]auth.APIKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []auth.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]auth.APIKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []auth.APIKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]auth.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// KeyManager_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type KeyManager_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *KeyManager_Expecter) List(ctx interface{}) *KeyManager_List_Call {
+	return &KeyManager_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *KeyManager_List_Call) Run(run func(ctx context.Context)) *KeyManager_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *KeyManager_List_Call) Return(_a0 []auth.APIKey, _a1 error) *KeyManager_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *KeyManager_List_Call) RunAndReturn(run func(context.Context) ([]auth.APIKey, error)) *KeyManager_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, id
+func (_m *KeyManager) Revoke(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// KeyManager_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type KeyManager_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *KeyManager_Expecter) Revoke(ctx interface{}, id interface{}) *KeyManager_Revoke_Call {
+	return &KeyManager_Revoke_Call{Call: _e.mock.On("Revoke", ctx, id)}
+}
+
+func (_c *KeyManager_Revoke_Call) Run(run func(ctx context.Context, id string)) *KeyManager_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *KeyManager_Revoke_Call) Return(_a0 error) *KeyManager_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KeyManager_Revoke_Call) RunAndReturn(run func(context.Context, string) error) *KeyManager_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeyManager creates a new instance of KeyManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyManager(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyManager {
+	mock := &KeyManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
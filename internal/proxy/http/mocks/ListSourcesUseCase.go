@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	proxy "github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+// ListSourcesUseCase is an autogenerated mock type for the ListSourcesUseCase type
+type ListSourcesUseCase struct {
+	mock.Mock
+}
+
+type ListSourcesUseCase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ListSourcesUseCase) EXPECT() *ListSourcesUseCase_Expecter {
+	return &ListSourcesUseCase_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx
+func (_m *ListSourcesUseCase) Execute(ctx context.Context) ([]proxy.SourceStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 []proxy.SourceStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]proxy.SourceStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []proxy.SourceStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]proxy.SourceStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSourcesUseCase_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type ListSourcesUseCase_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ListSourcesUseCase_Expecter) Execute(ctx interface{}) *ListSourcesUseCase_Execute_Call {
+	return &ListSourcesUseCase_Execute_Call{Call: _e.mock.On("Execute", ctx)}
+}
+
+func (_c *ListSourcesUseCase_Execute_Call) Run(run func(ctx context.Context)) *ListSourcesUseCase_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ListSourcesUseCase_Execute_Call) Return(_a0 []proxy.SourceStats, _a1 error) *ListSourcesUseCase_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ListSourcesUseCase_Execute_Call) RunAndReturn(run func(context.Context) ([]proxy.SourceStats, error)) *ListSourcesUseCase_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewListSourcesUseCase creates a new instance of ListSourcesUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewListSourcesUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ListSourcesUseCase {
+	mock := &ListSourcesUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RequestMetrics is an autogenerated mock type for the RequestMetrics type
+type RequestMetrics struct {
+	mock.Mock
+}
+
+type RequestMetrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RequestMetrics) EXPECT() *RequestMetrics_Expecter {
+	return &RequestMetrics_Expecter{mock: &_m.Mock}
+}
+
+// ObserveRequest provides a mock function with given fields: method, route, status, duration
+func (_m *RequestMetrics) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	_m.Called(method, route, status, duration)
+}
+
+// RequestMetrics_ObserveRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveRequest'
+type RequestMetrics_ObserveRequest_Call struct {
+	*mock.Call
+}
+
+// ObserveRequest is a helper method to define mock.On call
+//   - method string
+//   - route string
+//   - status int
+//   - duration time.Duration
+func (_e *RequestMetrics_Expecter) ObserveRequest(method interface{}, route interface{}, status interface{}, duration interface{}) *RequestMetrics_ObserveRequest_Call {
+	return &RequestMetrics_ObserveRequest_Call{Call: _e.mock.On("ObserveRequest", method, route, status, duration)}
+}
+
+func (_c *RequestMetrics_ObserveRequest_Call) Run(run func(method string, route string, status int, duration time.Duration)) *RequestMetrics_ObserveRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *RequestMetrics_ObserveRequest_Call) Return() *RequestMetrics_ObserveRequest_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *RequestMetrics_ObserveRequest_Call) RunAndReturn(run func(string, string, int, time.Duration)) *RequestMetrics_ObserveRequest_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewRequestMetrics creates a new instance of RequestMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRequestMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RequestMetrics {
+	mock := &RequestMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
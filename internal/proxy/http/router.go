@@ -5,6 +5,8 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
 )
 
 func NewRouter(h *Handler, logger Logger) http.Handler {
@@ -12,16 +14,47 @@ func NewRouter(h *Handler, logger Logger) http.Handler {
 
 	r.Use(middleware.RequestID)
 	r.Use(CorrelationIDMiddleware)
+	r.Use(TracingMiddleware)
 	r.Use(middleware.RealIP)
 	r.Use(LoggerMiddleware(logger))
 	r.Use(RequestLoggerMiddleware(logger))
 	r.Use(middleware.Recoverer)
+	if h.metrics != nil {
+		r.Use(MetricsMiddleware(h.metrics))
+	}
 
 	r.Get("/health", h.Health)
+	if h.metricsExporter != nil {
+		r.Handle("/metrics", h.metricsExporter)
+	}
 
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Get("/proxies", h.GetProxies)
-		r.Get("/proxies/random", h.GetRandomProxy)
+		r.With(h.RequireScope(auth.ScopeReadList)).Get("/proxies", h.GetProxies)
+		r.With(h.RequireScope(auth.ScopeReadRandom)).Get("/proxies/random", h.GetRandomProxy)
+
+		// Admin endpoints are never served open.
+		if h.authenticator == nil {
+			return
+		}
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.RequireScope(auth.ScopeAdmin))
+
+			r.Get("/sources", h.ListSources)
+			r.Post("/sources/{name}/force", h.ForceSource)
+			r.Delete("/sources/{name}/force", h.UnforceSource)
+
+			if h.keys != nil {
+				r.Get("/keys", h.ListKeys)
+				r.Post("/keys", h.CreateKey)
+				r.Delete("/keys/{id}", h.RevokeKey)
+			}
+
+			if h.deadLetters != nil {
+				r.Get("/dlq", h.ListDeadLetters)
+				r.Post("/dlq/{id}/replay", h.ReplayDeadLetter)
+			}
+		})
 	})
 
 	return r
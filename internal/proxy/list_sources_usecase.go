@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+type ListSourcesLogger interface {
+	Info(msg string, args ...any)
+}
+
+type SourceStats struct {
+	Name             string
+	Scraped          int64
+	Published        int64
+	Verified         int64
+	AvgLatency       time.Duration
+	Strikes          int
+	Quarantines      int
+	QuarantinedUntil time.Time
+	Forced           bool
+	// LastVerified and LastCheckedAt are the scraper's quarantine
+	// bookkeeping: the verified count and time of its last health check.
+	LastVerified  int64
+	LastCheckedAt time.Time
+}
+
+func (s SourceStats) IsQuarantined(now time.Time) bool {
+	return !s.Forced && now.Before(s.QuarantinedUntil)
+}
+
+type SourceStatsReader interface {
+	ListSourceStats(ctx context.Context) ([]SourceStats, error)
+}
+
+type ListSourcesUseCase struct {
+	reader SourceStatsReader
+	logger ListSourcesLogger
+}
+
+func NewListSourcesUseCase(reader SourceStatsReader, logger ListSourcesLogger) *ListSourcesUseCase {
+	return &ListSourcesUseCase{
+		reader: reader,
+		logger: logger,
+	}
+}
+
+func (uc *ListSourcesUseCase) Execute(ctx context.Context) ([]SourceStats, error) {
+	stats, err := uc.reader.ListSourceStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	uc.logger.Info("fetched source stats", "count", len(stats))
+
+	return stats, nil
+}
@@ -0,0 +1,45 @@
+package proxy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/mocks"
+)
+
+func TestListSourcesUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	logger := getProxiesTestLogger{}
+
+	t.Run("returns stats sorted by name", func(t *testing.T) {
+		reader := mocks.NewSourceStatsReader(t)
+		reader.EXPECT().
+			ListSourceStats(ctx).
+			Return([]proxy.SourceStats{{Name: "zeta"}, {Name: "alpha"}}, nil)
+
+		uc := proxy.NewListSourcesUseCase(reader, logger)
+		stats, err := uc.Execute(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		assert.Equal(t, "alpha", stats[0].Name)
+		assert.Equal(t, "zeta", stats[1].Name)
+	})
+
+	t.Run("propagates reader error", func(t *testing.T) {
+		reader := mocks.NewSourceStatsReader(t)
+		reader.EXPECT().
+			ListSourceStats(ctx).
+			Return(nil, errors.New("redis connection failed"))
+
+		uc := proxy.NewListSourcesUseCase(reader, logger)
+		_, err := uc.Execute(ctx)
+
+		assert.Error(t, err)
+	})
+}
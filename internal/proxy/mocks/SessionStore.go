@@ -0,0 +1,144 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionStore is an autogenerated mock type for the SessionStore type
+type SessionStore struct {
+	mock.Mock
+}
+
+type SessionStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionStore) EXPECT() *SessionStore_Expecter {
+	return &SessionStore_Expecter{mock: &_m.Mock}
+}
+
+// GetSession provides a mock function with given fields: ctx, session
+func (_m *SessionStore) GetSession(ctx context.Context, session string) (string, error) {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSession")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionStore_GetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSession'
+type SessionStore_GetSession_Call struct {
+	*mock.Call
+}
+
+// GetSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session string
+func (_e *SessionStore_Expecter) GetSession(ctx interface{}, session interface{}) *SessionStore_GetSession_Call {
+	return &SessionStore_GetSession_Call{Call: _e.mock.On("GetSession", ctx, session)}
+}
+
+func (_c *SessionStore_GetSession_Call) Run(run func(ctx context.Context, session string)) *SessionStore_GetSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SessionStore_GetSession_Call) Return(_a0 string, _a1 error) *SessionStore_GetSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionStore_GetSession_Call) RunAndReturn(run func(context.Context, string) (string, error)) *SessionStore_GetSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetSession provides a mock function with given fields: ctx, session, address, ttl
+func (_m *SessionStore) SetSession(ctx context.Context, session string, address string, ttl time.Duration) error {
+	ret := _m.Called(ctx, session, address, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, session, address, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionStore_SetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSession'
+type SessionStore_SetSession_Call struct {
+	*mock.Call
+}
+
+// SetSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session string
+//   - address string
+//   - ttl time.Duration
+func (_e *SessionStore_Expecter) SetSession(ctx interface{}, session interface{}, address interface{}, ttl interface{}) *SessionStore_SetSession_Call {
+	return &SessionStore_SetSession_Call{Call: _e.mock.On("SetSession", ctx, session, address, ttl)}
+}
+
+func (_c *SessionStore_SetSession_Call) Run(run func(ctx context.Context, session string, address string, ttl time.Duration)) *SessionStore_SetSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *SessionStore_SetSession_Call) Return(_a0 error) *SessionStore_SetSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionStore_SetSession_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) error) *SessionStore_SetSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionStore creates a new instance of SessionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionStore {
+	mock := &SessionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
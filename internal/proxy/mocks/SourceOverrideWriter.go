@@ -0,0 +1,84 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SourceOverrideWriter is an autogenerated mock type for the SourceOverrideWriter type
+type SourceOverrideWriter struct {
+	mock.Mock
+}
+
+type SourceOverrideWriter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SourceOverrideWriter) EXPECT() *SourceOverrideWriter_Expecter {
+	return &SourceOverrideWriter_Expecter{mock: &_m.Mock}
+}
+
+// SetSourceForced provides a mock function with given fields: ctx, source, forced
+func (_m *SourceOverrideWriter) SetSourceForced(ctx context.Context, source string, forced bool) error {
+	ret := _m.Called(ctx, source, forced)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSourceForced")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, source, forced)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SourceOverrideWriter_SetSourceForced_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSourceForced'
+type SourceOverrideWriter_SetSourceForced_Call struct {
+	*mock.Call
+}
+
+// SetSourceForced is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - forced bool
+func (_e *SourceOverrideWriter_Expecter) SetSourceForced(ctx interface{}, source interface{}, forced interface{}) *SourceOverrideWriter_SetSourceForced_Call {
+	return &SourceOverrideWriter_SetSourceForced_Call{Call: _e.mock.On("SetSourceForced", ctx, source, forced)}
+}
+
+func (_c *SourceOverrideWriter_SetSourceForced_Call) Run(run func(ctx context.Context, source string, forced bool)) *SourceOverrideWriter_SetSourceForced_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *SourceOverrideWriter_SetSourceForced_Call) Return(_a0 error) *SourceOverrideWriter_SetSourceForced_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SourceOverrideWriter_SetSourceForced_Call) RunAndReturn(run func(context.Context, string, bool) error) *SourceOverrideWriter_SetSourceForced_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSourceOverrideWriter creates a new instance of SourceOverrideWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSourceOverrideWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SourceOverrideWriter {
+	mock := &SourceOverrideWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
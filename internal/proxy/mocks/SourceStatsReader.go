@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	proxy "github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+// SourceStatsReader is an autogenerated mock type for the SourceStatsReader type
+type SourceStatsReader struct {
+	mock.Mock
+}
+
+type SourceStatsReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SourceStatsReader) EXPECT() *SourceStatsReader_Expecter {
+	return &SourceStatsReader_Expecter{mock: &_m.Mock}
+}
+
+// ListSourceStats provides a mock function with given fields: ctx
+func (_m *SourceStatsReader) ListSourceStats(ctx context.Context) ([]proxy.SourceStats, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSourceStats")
+	}
+
+	var r0 []proxy.SourceStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]proxy.SourceStats, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []proxy.SourceStats); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]proxy.SourceStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SourceStatsReader_ListSourceStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSourceStats'
+type SourceStatsReader_ListSourceStats_Call struct {
+	*mock.Call
+}
+
+// ListSourceStats is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *SourceStatsReader_Expecter) ListSourceStats(ctx interface{}) *SourceStatsReader_ListSourceStats_Call {
+	return &SourceStatsReader_ListSourceStats_Call{Call: _e.mock.On("ListSourceStats", ctx)}
+}
+
+func (_c *SourceStatsReader_ListSourceStats_Call) Run(run func(ctx context.Context)) *SourceStatsReader_ListSourceStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *SourceStatsReader_ListSourceStats_Call) Return(_a0 []proxy.SourceStats, _a1 error) *SourceStatsReader_ListSourceStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SourceStatsReader_ListSourceStats_Call) RunAndReturn(run func(context.Context) ([]proxy.SourceStats, error)) *SourceStatsReader_ListSourceStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSourceStatsReader creates a new instance of SourceStatsReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSourceStatsReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SourceStatsReader {
+	mock := &SourceStatsReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
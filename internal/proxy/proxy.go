@@ -1,11 +1,19 @@
 package proxy
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
 )
 
+var ErrIntercepted = errors.New("proxy intercepts tls traffic")
+
+const (
+	baseCooldown = 5 * time.Minute
+	maxCooldown  = 6 * time.Hour
+)
+
 type Protocol string
 
 const (
@@ -24,6 +32,43 @@ const (
 	Unknown     AnonymityLevel = "unknown"
 )
 
+type Capability string
+
+const (
+	CapabilityConnect   Capability = "connect"
+	CapabilityPlainHTTP Capability = "plain_http"
+)
+
+func ParseCapability(s string) (Capability, bool) {
+	switch Capability(s) {
+	case CapabilityConnect, CapabilityPlainHTTP:
+		return Capability(s), true
+	default:
+		return "", false
+	}
+}
+
+func ParseProtocol(s string) (Protocol, bool) {
+	switch Protocol(s) {
+	case HTTP, HTTPS, SOCKS4, SOCKS5:
+		return Protocol(s), true
+	default:
+		return "", false
+	}
+}
+
+// ParseAnonymity accepts the levels a proxy can be filtered by; unlike
+// AnonymityLevelFromString it rejects anything else instead of mapping it
+// to Unknown.
+func ParseAnonymity(s string) (AnonymityLevel, bool) {
+	switch AnonymityLevel(s) {
+	case Transparent, Anonymous, Elite:
+		return AnonymityLevel(s), true
+	default:
+		return "", false
+	}
+}
+
 func AnonymityLevelFromString(s string) AnonymityLevel {
 	switch s {
 	case "elite":
@@ -38,16 +83,25 @@ func AnonymityLevelFromString(s string) AnonymityLevel {
 }
 
 type Proxy struct {
-	IP            string
-	Port          int
-	Protocol      Protocol
-	Anonymity     AnonymityLevel
-	Source        string
-	FirstSeenAt   time.Time
-	LastCheckAt   time.Time
-	Latency       time.Duration
-	FailCount     int
-	CooldownUntil time.Time
+	IP                string
+	Port              int
+	Protocol          Protocol
+	Anonymity         AnonymityLevel
+	Source            string
+	FirstSeenAt       time.Time
+	LastCheckAt       time.Time
+	Latency           time.Duration
+	FailCount         int
+	CooldownUntil     time.Time
+	SupportsConnect   bool
+	SupportsPlainHTTP bool
+	MITM              bool
+	ExitIP            string
+	Country           string
+	City              string
+	ASN               uint
+	Username          string `json:"-"`
+	Password          string `json:"-"`
 }
 
 func NewProxy(ip string, port int, protocol Protocol, source string) *Proxy {
@@ -65,10 +119,58 @@ func (p *Proxy) Address() string {
 	return fmt.Sprintf("%s:%d", p.IP, p.Port)
 }
 func (p *Proxy) URL() *url.URL {
-	return &url.URL{
+	u := &url.URL{
 		Scheme: string(p.Protocol),
 		Host:   p.Address(),
 	}
+	if p.HasAuth() {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+	return u
+}
+
+func (p *Proxy) HasAuth() bool {
+	return p.Username != ""
+}
+
+func (p *Proxy) Supports(c Capability) bool {
+	switch c {
+	case CapabilityConnect:
+		return p.SupportsConnect
+	case CapabilityPlainHTTP:
+		return p.SupportsPlainHTTP
+	default:
+		return false
+	}
+}
+
+func (p *Proxy) exitKey() string {
+	if p.ExitIP != "" {
+		return p.ExitIP
+	}
+	return p.Address()
+}
+
+// DistinctExit keeps the fastest proxy per exit IP, in the order the exits
+// first appear. Proxies whose exit is unknown are kept as their own exit.
+// It only dedupes the slice it is given, so it backs the random pick rather
+// than paginated listings, where an exit could repeat across pages.
+func DistinctExit(proxies []*Proxy) []*Proxy {
+	index := make(map[string]int, len(proxies))
+	distinct := make([]*Proxy, 0, len(proxies))
+	for _, p := range proxies {
+		key := p.exitKey()
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(distinct)
+			distinct = append(distinct, p)
+			continue
+		}
+		if p.Latency < distinct[i].Latency {
+			distinct[i] = p
+		}
+	}
+	return distinct
 }
 
 func (p *Proxy) IsReady() bool {
@@ -83,17 +185,20 @@ func (p *Proxy) MarkSuccess(latency time.Duration, anonymity AnonymityLevel) {
 	p.Anonymity = anonymity
 }
 
+// MarkFailure doubles the cooldown on every consecutive failure, starting at
+// baseCooldown and never exceeding maxCooldown.
 func (p *Proxy) MarkFailure() {
 	p.FailCount++
 	p.LastCheckAt = time.Now()
 
-	matchFail := p.FailCount
-	if matchFail < 1 {
-		matchFail = 1
+	duration := baseCooldown
+	for i := 1; i < p.FailCount && duration < maxCooldown; i++ {
+		duration *= 2
 	}
+	p.CooldownUntil = p.LastCheckAt.Add(min(duration, maxCooldown))
+}
 
-	backoffMinutes := 5 * (1 << (matchFail - 1))
-
-	duration := time.Duration(backoffMinutes) * time.Minute
-	p.CooldownUntil = time.Now().Add(duration)
+func (p *Proxy) MarkMITM() {
+	p.MITM = true
+	p.LastCheckAt = time.Now()
 }
@@ -0,0 +1,462 @@
+// Package proxytest holds the suite every proxy repository runs against, so
+// readers and writers behave the same whichever storage is configured.
+package proxytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+type Repository interface {
+	proxy.Reader
+	proxy.SessionStore
+	proxy.SourceStatsReader
+	proxy.SourceOverrideWriter
+	Save(ctx context.Context, p *proxy.Proxy) error
+	RecordFailure(ctx context.Context, p *proxy.Proxy) error
+	RecordMITM(ctx context.Context, p *proxy.Proxy) error
+	GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]*proxy.Proxy, error)
+	RecordScrape(ctx context.Context, source string, scraped, published int) error
+	RecordVerified(ctx context.Context, source string, latency time.Duration) error
+	GetSourceStats(ctx context.Context, sources []string) (map[string]proxy.SourceStats, error)
+	SaveSourceHealth(ctx context.Context, s proxy.SourceStats) error
+}
+
+// Run needs newRepo to return an empty repository with the default TTL and
+// a cipher configured for credentials.
+func Run(t *testing.T, newRepo func(t *testing.T) Repository) {
+	ctx := context.Background()
+
+	t.Run("serves saved proxies", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		proxies, nextCursor, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Len(t, proxies, 3)
+		assert.Zero(t, nextCursor)
+	})
+
+	t.Run("returns nothing when empty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		proxies, nextCursor, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, proxies)
+		assert.Zero(t, nextCursor)
+		assert.Zero(t, total)
+	})
+
+	t.Run("filters through secondary indexes", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		tests := []struct {
+			name   string
+			filter proxy.FilterOptions
+			total  int
+			want   []string
+		}{
+			{"protocol", proxy.FilterOptions{Protocol: "socks5"}, 1, []string{"2.2.2.2:1080"}},
+			{"anonymity", proxy.FilterOptions{Anonymity: "elite"}, 1, []string{"1.1.1.1:80"}},
+			{"protocol and anonymity", proxy.FilterOptions{Protocol: "http", Anonymity: "transparent"}, 1, []string{"3.3.3.3:8080"}},
+			{"capability", proxy.FilterOptions{Capability: "connect"}, 2, []string{"1.1.1.1:80", "2.2.2.2:1080"}},
+			{"capability within protocol", proxy.FilterOptions{Protocol: "http", Capability: "plain_http"}, 2, []string{"1.1.1.1:80"}},
+			{"country", proxy.FilterOptions{Country: "BR"}, 2, []string{"1.1.1.1:80", "2.2.2.2:1080"}},
+			{"asn", proxy.FilterOptions{ASN: 18881}, 1, []string{"2.2.2.2:1080"}},
+			{"protocol within country", proxy.FilterOptions{Country: "BR", Protocol: "http"}, 2, []string{"1.1.1.1:80"}},
+			{"max latency", proxy.FilterOptions{MaxLatency: 100 * time.Millisecond}, 3, []string{"1.1.1.1:80", "2.2.2.2:1080"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				proxies, _, total, err := repo.GetAlive(ctx, 0, 10, tt.filter)
+				require.NoError(t, err)
+				assert.Equal(t, tt.total, total)
+				assert.ElementsMatch(t, tt.want, addresses(proxies))
+			})
+		}
+	})
+
+	t.Run("ignores stale country entries after the exit moves", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("5.5.5.5", 3128, proxy.HTTP, "s1")
+		p.MarkSuccess(10*time.Millisecond, proxy.Elite)
+		p.Country = "AR"
+		require.NoError(t, repo.Save(ctx, p))
+
+		p.Country = "CL"
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Country: "AR"})
+		require.NoError(t, err)
+		assert.Empty(t, proxies)
+
+		proxies, _, _, err = repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Country: "CL"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"5.5.5.5:3128"}, addresses(proxies))
+	})
+
+	t.Run("drops capability when a later check loses it", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("4.4.4.4", 3128, proxy.HTTP, "s1")
+		p.MarkSuccess(10*time.Millisecond, proxy.Elite)
+		p.SupportsConnect = true
+		require.NoError(t, repo.Save(ctx, p))
+
+		p.SupportsConnect = false
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Capability: "connect"})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, proxies)
+	})
+
+	t.Run("keeps one index entry per proxy across saves", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("6.6.6.6", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(10*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Protocol: "http"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, proxies, 1)
+	})
+
+	t.Run("pages with an exclusive cursor", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		first, nextCursor, total, err := repo.GetAlive(ctx, 0, 2, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Len(t, first, 2)
+		assert.Greater(t, nextCursor, float64(time.Now().Unix()))
+
+		rest, _, _, err := repo.GetAlive(ctx, nextCursor, 2, proxy.FilterOptions{})
+		require.NoError(t, err)
+		for _, p := range rest {
+			assert.NotContains(t, addresses(first), p.Address())
+		}
+	})
+
+	t.Run("pins sessions until they expire", func(t *testing.T) {
+		repo := newRepo(t)
+
+		address, err := repo.GetSession(ctx, "missing")
+		require.NoError(t, err)
+		assert.Empty(t, address)
+
+		require.NoError(t, repo.SetSession(ctx, "login", "1.1.1.1:80", time.Minute))
+		address, err = repo.GetSession(ctx, "login")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.1.1:80", address)
+
+		require.NoError(t, repo.SetSession(ctx, "short", "1.1.1.1:80", 50*time.Millisecond))
+		time.Sleep(100 * time.Millisecond)
+		address, err = repo.GetSession(ctx, "short")
+		require.NoError(t, err)
+		assert.Empty(t, address)
+	})
+
+	t.Run("ignores failures of proxies that were never stored", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("9.9.9.9", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, p))
+		assert.Zero(t, p.FailCount)
+
+		_, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+
+	t.Run("persists cooldown and drops failed proxies from the indexes", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		failed := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, 1, failed.FailCount)
+		assert.False(t, failed.IsReady())
+
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, 2, failed.FailCount)
+
+		for _, filter := range []proxy.FilterOptions{{}, {Protocol: "http"}, {Anonymity: "elite"}, {Protocol: "http", Anonymity: "elite"}} {
+			proxies, _, total, err := repo.GetAlive(ctx, 0, 10, filter)
+			require.NoError(t, err)
+			assert.Zero(t, total)
+			assert.Empty(t, proxies)
+		}
+	})
+
+	t.Run("hands failed proxies back for a recheck after the cooldown", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		failed := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+
+		stale, err := repo.GetStale(ctx, time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+
+		stale, err = repo.GetStale(ctx, failed.CooldownUntil.Add(time.Second), 10)
+		require.NoError(t, err)
+		require.Len(t, stale, 1)
+		assert.Equal(t, 1, stale[0].FailCount)
+
+		stale[0].MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, stale[0]))
+
+		_, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+	})
+
+	t.Run("skips proxies in cooldown when reading", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		p.CooldownUntil = time.Now().Add(time.Hour)
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, proxies)
+	})
+
+	t.Run("refuses intercepted proxies", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		p.SupportsConnect = true
+		require.NoError(t, repo.Save(ctx, p))
+
+		intercepted := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordMITM(ctx, intercepted))
+		assert.True(t, intercepted.MITM)
+
+		for _, filter := range []proxy.FilterOptions{{}, {Protocol: "http"}, {Capability: "connect"}} {
+			_, _, total, err := repo.GetAlive(ctx, 0, 10, filter)
+			require.NoError(t, err)
+			assert.Zero(t, total)
+		}
+
+		clean := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		clean.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		assert.ErrorIs(t, repo.Save(ctx, clean), proxy.ErrIntercepted)
+
+		unknown := proxy.NewProxy("2.2.2.2", 8080, proxy.SOCKS5, "s1")
+		require.NoError(t, repo.RecordMITM(ctx, unknown))
+		assert.ErrorIs(t, repo.Save(ctx, unknown), proxy.ErrIntercepted)
+	})
+
+	t.Run("returns proxies checked before the cutoff", func(t *testing.T) {
+		repo := newRepo(t)
+		seed(t, repo)
+
+		stale, err := repo.GetStale(ctx, time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.Len(t, stale, 3)
+
+		stale, err = repo.GetStale(ctx, time.Now().Add(time.Minute), 1)
+		require.NoError(t, err)
+		assert.Len(t, stale, 1)
+
+		stale, err = repo.GetStale(ctx, time.Now().Add(-15*time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+	})
+
+	t.Run("claims stale proxies by their last check", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		p.LastCheckAt = time.Now().Add(-20 * time.Minute)
+		require.NoError(t, repo.Save(ctx, p))
+
+		fresh := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")
+		fresh.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, fresh))
+
+		stale, err := repo.GetStale(ctx, time.Now().Add(-10*time.Minute), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.1.1.1:8080"}, addresses(stale))
+
+		stale, err = repo.GetStale(ctx, time.Now().Add(-10*time.Minute), 10)
+		require.NoError(t, err)
+		assert.Empty(t, stale)
+	})
+
+	t.Run("accumulates source stats", func(t *testing.T) {
+		repo := newRepo(t)
+
+		stats, err := repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, stats)
+
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 10, 8))
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 5, 5))
+		require.NoError(t, repo.RecordVerified(ctx, "s1", 100*time.Millisecond))
+		require.NoError(t, repo.RecordVerified(ctx, "s1", 300*time.Millisecond))
+		require.NoError(t, repo.RecordScrape(ctx, "s2", 3, 3))
+
+		stats, err = repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+
+		byName := map[string]proxy.SourceStats{}
+		for _, s := range stats {
+			byName[s.Name] = s
+		}
+
+		assert.Equal(t, proxy.SourceStats{Name: "s1", Scraped: 15, Published: 13, Verified: 2, AvgLatency: 200 * time.Millisecond}, byName["s1"])
+		assert.Equal(t, proxy.SourceStats{Name: "s2", Scraped: 3, Published: 3}, byName["s2"])
+	})
+
+	t.Run("forces known sources only", func(t *testing.T) {
+		repo := newRepo(t)
+
+		assert.ErrorIs(t, repo.SetSourceForced(ctx, "missing", true), proxy.ErrSourceNotFound)
+
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 1, 1))
+		require.NoError(t, repo.SetSourceForced(ctx, "s1", true))
+
+		stats, err := repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.True(t, stats[0].Forced)
+
+		require.NoError(t, repo.SetSourceForced(ctx, "s1", false))
+
+		stats, err = repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.False(t, stats[0].Forced)
+	})
+
+	t.Run("keeps source health next to the stats", func(t *testing.T) {
+		repo := newRepo(t)
+
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 10, 10))
+		require.NoError(t, repo.RecordVerified(ctx, "s1", 100*time.Millisecond))
+
+		until := time.Now().Add(time.Hour).Truncate(time.Second)
+		checked := time.Now().Truncate(time.Second)
+		require.NoError(t, repo.SaveSourceHealth(ctx, proxy.SourceStats{
+			Name:             "s1",
+			LastVerified:     1,
+			Strikes:          3,
+			Quarantines:      1,
+			QuarantinedUntil: until,
+			LastCheckedAt:    checked,
+		}))
+
+		stats, err := repo.GetSourceStats(ctx, []string{"s1", "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), stats["s1"].Scraped)
+		assert.Equal(t, int64(1), stats["s1"].Verified)
+		assert.Equal(t, int64(1), stats["s1"].LastVerified)
+		assert.Equal(t, 3, stats["s1"].Strikes)
+		assert.Equal(t, 1, stats["s1"].Quarantines)
+		assert.True(t, stats["s1"].QuarantinedUntil.Equal(until))
+		assert.True(t, stats["s1"].LastCheckedAt.Equal(checked))
+		assert.Equal(t, proxy.SourceStats{Name: "unknown"}, stats["unknown"])
+
+		listed, err := repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.True(t, listed[0].IsQuarantined(time.Now()))
+
+		require.NoError(t, repo.SetSourceForced(ctx, "s1", true))
+
+		stats, err = repo.GetSourceStats(ctx, []string{"s1"})
+		require.NoError(t, err)
+		assert.True(t, stats["s1"].Forced)
+		assert.Zero(t, stats["s1"].Quarantines)
+		assert.True(t, stats["s1"].QuarantinedUntil.IsZero())
+
+		require.NoError(t, repo.SaveSourceHealth(ctx, proxy.SourceStats{Name: "s1", Strikes: 1}))
+
+		stats, err = repo.GetSourceStats(ctx, []string{"s1"})
+		require.NoError(t, err)
+		assert.True(t, stats["s1"].Forced)
+		assert.Equal(t, int64(10), stats["s1"].Scraped)
+	})
+
+	t.Run("restores credentials on read", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+		p.Username = "alice"
+		p.Password = "s3cret"
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "alice", proxies[0].Username)
+		assert.Equal(t, "s3cret", proxies[0].Password)
+
+		failed := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, "alice", failed.Username)
+		assert.Equal(t, "s3cret", failed.Password)
+	})
+}
+
+// seed stores three proxies spread over every secondary index.
+func seed(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	p1 := proxy.NewProxy("1.1.1.1", 80, proxy.HTTP, "s1")
+	p1.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p1.SupportsConnect = true
+	p1.SupportsPlainHTTP = true
+	p1.Country, p1.ASN = "BR", 28573
+	require.NoError(t, repo.Save(ctx, p1))
+
+	p2 := proxy.NewProxy("2.2.2.2", 1080, proxy.SOCKS5, "s1")
+	p2.MarkSuccess(50*time.Millisecond, proxy.Anonymous)
+	p2.SupportsConnect = true
+	p2.Country, p2.ASN = "BR", 18881
+	require.NoError(t, repo.Save(ctx, p2))
+
+	p3 := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+	p3.MarkSuccess(200*time.Millisecond, proxy.Transparent)
+	require.NoError(t, repo.Save(ctx, p3))
+}
+
+func addresses(proxies []*proxy.Proxy) []string {
+	result := make([]string, len(proxies))
+	for i, p := range proxies {
+		result[i] = p.Address()
+	}
+	return result
+}
@@ -2,23 +2,29 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
 )
 
 const (
 	defaultTTL = 30 * time.Minute
+	tracerName = "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
 )
 
 type Repository struct {
 	client    *redis.Client
 	ttl       time.Duration
 	keyPrefix string
+	codec     storage.Codec
 }
 
 func NewRepository(client *redis.Client, keyPrefix string) *Repository {
@@ -37,36 +43,69 @@ func (r *Repository) WithTTL(ttl time.Duration) *Repository {
 	return r
 }
 
+func (r *Repository) WithCipher(c storage.Cipher) *Repository {
+	r.codec = storage.Codec{Cipher: c}
+	return r
+}
+
 func (r *Repository) proxyKey(address string) string {
 	return fmt.Sprintf("%s:data:%s", r.keyPrefix, address)
 }
 
-func (r *Repository) aliveSetKey() string {
-	return fmt.Sprintf("%s:idx:alive", r.keyPrefix)
+// indexKey places a storage index name under the idx namespace, which the
+// scraper cleaner trims by expiry.
+func (r *Repository) indexKey(name string) string {
+	return fmt.Sprintf("%s:idx:%s", r.keyPrefix, name)
 }
 
-func (r *Repository) protocolSetKey(protocol string) string {
-	return fmt.Sprintf("%s:idx:proto:%s", r.keyPrefix, protocol)
+// checkedSetKey scores every stored proxy by its last check. It lives outside
+// the idx namespace so the scraper cleaner, which trims indexes by expiry,
+// leaves it alone; GetStale drops members whose data has expired.
+func (r *Repository) checkedSetKey() string {
+	return fmt.Sprintf("%s:checked", r.keyPrefix)
 }
 
-func (r *Repository) anonymitySetKey(anonymity string) string {
-	return fmt.Sprintf("%s:idx:anon:%s", r.keyPrefix, anonymity)
+func (r *Repository) mitmSetKey() string {
+	return fmt.Sprintf("%s:mitm", r.keyPrefix)
 }
 
-func (r *Repository) compositeSetKey(protocol, anonymity string) string {
-	return fmt.Sprintf("%s:idx:proto:%s:anon:%s", r.keyPrefix, protocol, anonymity)
+func (r *Repository) sessionKey(session string) string {
+	return fmt.Sprintf("%s:session:%s", r.keyPrefix, session)
 }
 
-func (r *Repository) latencySetKey() string {
-	return fmt.Sprintf("%s:idx:latency", r.keyPrefix)
+func (r *Repository) Save(ctx context.Context, p *proxy.Proxy) error {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "proxy.save",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "redis"),
+			attribute.String("proxy.address", p.Address()),
+		),
+	)
+	defer span.End()
+
+	if err := r.save(ctx, p); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
 }
 
-func (r *Repository) Save(ctx context.Context, p *proxy.Proxy) error {
+func (r *Repository) save(ctx context.Context, p *proxy.Proxy) error {
 	key := r.proxyKey(p.Address())
 
-	data, err := json.Marshal(p)
+	blocked, err := r.client.SIsMember(ctx, r.mitmSetKey(), p.Address()).Result()
 	if err != nil {
-		return fmt.Errorf("marshal proxy: %w", err)
+		return fmt.Errorf("check mitm: %w", err)
+	}
+	if blocked {
+		return fmt.Errorf("save proxy %s: %w", p.Address(), proxy.ErrIntercepted)
+	}
+
+	data, err := r.codec.Encode(p)
+	if err != nil {
+		return err
 	}
 
 	expirationScore := float64(time.Now().Add(r.ttl).Unix())
@@ -76,12 +115,20 @@ func (r *Repository) Save(ctx context.Context, p *proxy.Proxy) error {
 
 	pipe.Set(ctx, key, data, r.ttl)
 
-	pipe.ZAdd(ctx, r.aliveSetKey(), redis.Z{Score: expirationScore, Member: p.Address()})
-	pipe.ZAdd(ctx, r.protocolSetKey(string(p.Protocol)), redis.Z{Score: expirationScore, Member: p.Address()})
-	pipe.ZAdd(ctx, r.anonymitySetKey(string(p.Anonymity)), redis.Z{Score: expirationScore, Member: p.Address()})
-	pipe.ZAdd(ctx, r.compositeSetKey(string(p.Protocol), string(p.Anonymity)), redis.Z{Score: expirationScore, Member: p.Address()})
+	for _, name := range storage.ExpiringIndexes(p) {
+		pipe.ZAdd(ctx, r.indexKey(name), redis.Z{Score: expirationScore, Member: p.Address()})
+	}
 
-	pipe.ZAdd(ctx, r.latencySetKey(), redis.Z{Score: latencyScore, Member: p.Address()})
+	for _, capability := range storage.Capabilities {
+		if p.Supports(capability) {
+			pipe.ZAdd(ctx, r.indexKey(storage.CapabilityIndex(capability)), redis.Z{Score: expirationScore, Member: p.Address()})
+		} else {
+			pipe.ZRem(ctx, r.indexKey(storage.CapabilityIndex(capability)), p.Address())
+		}
+	}
+
+	pipe.ZAdd(ctx, r.indexKey(storage.LatencyIndex), redis.Z{Score: latencyScore, Member: p.Address()})
+	pipe.ZAdd(ctx, r.checkedSetKey(), redis.Z{Score: float64(p.LastCheckAt.Unix()), Member: p.Address()})
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -91,8 +138,98 @@ func (r *Repository) Save(ctx context.Context, p *proxy.Proxy) error {
 	return nil
 }
 
+// RecordFailure takes the proxy out of the serving indexes and keeps it until
+// its cooldown ends, scored in the check index at the cooldown deadline so
+// GetStale hands it back for another check once it is over.
+func (r *Repository) RecordFailure(ctx context.Context, p *proxy.Proxy) error {
+	stored, err := r.get(ctx, p.Address())
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return nil
+	}
+
+	stored.MarkFailure()
+	*p = *stored
+
+	data, err := r.codec.Encode(stored)
+	if err != nil {
+		return err
+	}
+
+	address := stored.Address()
+	pipe := r.client.Pipeline()
+
+	pipe.Set(ctx, r.proxyKey(address), data, time.Until(stored.CooldownUntil)+r.ttl)
+
+	r.unindex(ctx, pipe, stored)
+	pipe.ZAdd(ctx, r.checkedSetKey(), redis.Z{Score: float64(stored.CooldownUntil.Unix()), Member: address})
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record failure: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RecordMITM(ctx context.Context, p *proxy.Proxy) error {
+	stored, err := r.get(ctx, p.Address())
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = p
+	}
+
+	stored.MarkMITM()
+	*p = *stored
+
+	data, err := r.codec.Encode(stored)
+	if err != nil {
+		return err
+	}
+
+	pipe := r.client.Pipeline()
+
+	pipe.SAdd(ctx, r.mitmSetKey(), stored.Address())
+	pipe.Set(ctx, r.proxyKey(stored.Address()), data, r.ttl)
+	r.unindex(ctx, pipe, stored)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record mitm: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) unindex(ctx context.Context, pipe redis.Pipeliner, p *proxy.Proxy) {
+	address := p.Address()
+
+	for _, name := range storage.ExpiringIndexes(p) {
+		pipe.ZRem(ctx, r.indexKey(name), address)
+	}
+	for _, capability := range storage.Capabilities {
+		pipe.ZRem(ctx, r.indexKey(storage.CapabilityIndex(capability)), address)
+	}
+	pipe.ZRem(ctx, r.indexKey(storage.LatencyIndex), address)
+	pipe.ZRem(ctx, r.checkedSetKey(), address)
+}
+
+func (r *Repository) get(ctx context.Context, address string) (*proxy.Proxy, error) {
+	data, err := r.client.Get(ctx, r.proxyKey(address)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get proxy: %w", err)
+	}
+
+	return r.codec.Decode(data)
+}
+
 func (r *Repository) GetAlive(ctx context.Context, cursor float64, limit int, filter proxy.FilterOptions) ([]*proxy.Proxy, float64, int, error) {
-	targetKey := r.selectIndex(filter)
+	targetKey := r.indexKey(storage.SelectIndex(filter))
 	now := float64(time.Now().Unix())
 
 	total, err := r.client.ZCount(ctx, targetKey, fmt.Sprintf("%f", now), "+inf").Result()
@@ -142,6 +279,90 @@ func (r *Repository) GetAlive(ctx context.Context, cursor float64, limit int, fi
 		lastScore = z.Score
 	}
 
+	loaded, err := r.mget(ctx, addresses)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	proxies := make([]*proxy.Proxy, 0, len(loaded))
+	for _, p := range loaded {
+		if p.MITM || !p.IsReady() || !storage.Matches(p, filter) {
+			continue
+		}
+
+		proxies = append(proxies, p)
+	}
+
+	var nextCursor float64
+	if limit > 0 && len(results) == limit {
+		nextCursor = lastScore
+	}
+
+	return proxies, nextCursor, int(total), nil
+}
+
+func (r *Repository) GetSession(ctx context.Context, session string) (string, error) {
+	address, err := r.client.Get(ctx, r.sessionKey(session)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+	return address, nil
+}
+
+func (r *Repository) SetSession(ctx context.Context, session, address string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.sessionKey(session), address, ttl).Err(); err != nil {
+		return fmt.Errorf("set session: %w", err)
+	}
+	return nil
+}
+
+// GetStale returns up to limit proxies last checked before checkedBefore and
+// bumps their check score to now, so a recheck that is still queued is not
+// scheduled again on the next tick. Members whose data expired are dropped.
+func (r *Repository) GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]*proxy.Proxy, error) {
+	addresses, err := r.client.ZRangeByScore(ctx, r.checkedSetKey(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   fmt.Sprintf("(%d", checkedBefore.Unix()),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("zrangebyscore: %w", err)
+	}
+
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	stale, err := r.mget(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(stale))
+	for _, p := range stale {
+		found[p.Address()] = true
+	}
+
+	claimed := float64(time.Now().Unix())
+	pipe := r.client.Pipeline()
+	for _, address := range addresses {
+		if found[address] {
+			pipe.ZAddXX(ctx, r.checkedSetKey(), redis.Z{Score: claimed, Member: address})
+		} else {
+			pipe.ZRem(ctx, r.checkedSetKey(), address)
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("claim stale proxies: %w", err)
+	}
+
+	return stale, nil
+}
+
+func (r *Repository) mget(ctx context.Context, addresses []string) ([]*proxy.Proxy, error) {
 	keys := make([]string, len(addresses))
 	for i, addr := range addresses {
 		keys[i] = r.proxyKey(addr)
@@ -149,7 +370,7 @@ func (r *Repository) GetAlive(ctx context.Context, cursor float64, limit int, fi
 
 	values, err := r.client.MGet(ctx, keys...).Result()
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("mget proxies: %w", err)
+		return nil, fmt.Errorf("mget proxies: %w", err)
 	}
 
 	proxies := make([]*proxy.Proxy, 0, len(values))
@@ -163,38 +384,13 @@ func (r *Repository) GetAlive(ctx context.Context, cursor float64, limit int, fi
 			continue
 		}
 
-		var p proxy.Proxy
-		if err := json.Unmarshal([]byte(str), &p); err != nil {
-			continue
-		}
-
-		if filter.MaxLatency > 0 && p.Latency > filter.MaxLatency {
+		p, err := r.codec.Decode([]byte(str))
+		if err != nil {
 			continue
 		}
 
-		proxies = append(proxies, &p)
-	}
-
-	var nextCursor float64
-	if limit > 0 && len(results) == limit {
-		nextCursor = lastScore
+		proxies = append(proxies, p)
 	}
 
-	return proxies, nextCursor, int(total), nil
-}
-
-func (r *Repository) selectIndex(filter proxy.FilterOptions) string {
-	hasProtocol := filter.Protocol != ""
-	hasAnonymity := filter.Anonymity != ""
-
-	switch {
-	case hasProtocol && hasAnonymity:
-		return r.compositeSetKey(filter.Protocol, filter.Anonymity)
-	case hasProtocol:
-		return r.protocolSetKey(filter.Protocol)
-	case hasAnonymity:
-		return r.anonymitySetKey(filter.Anonymity)
-	default:
-		return r.aliveSetKey()
-	}
+	return proxies, nil
 }
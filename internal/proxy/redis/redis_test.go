@@ -2,6 +2,7 @@ package redis_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,10 +11,40 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/redis"
 
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
 	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/proxytest"
 	proxyredis "github.com/JulianoL13/app-proxy-engine/internal/proxy/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
 )
 
+func TestRepository_Conformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	cipher, err := crypt.NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+
+	var repos int
+	proxytest.Run(t, func(t *testing.T) proxytest.Repository {
+		repos++
+		return proxyredis.NewRepository(client, fmt.Sprintf("suite%d", repos)).WithCipher(cipher)
+	})
+}
+
 func TestRepository_Save(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -179,10 +210,15 @@ func TestRepository_GetAlive(t *testing.T) {
 
 	p1 := proxy.NewProxy("1.1.1.1", 80, proxy.HTTP, "s1")
 	p1.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p1.SupportsConnect = true
+	p1.SupportsPlainHTTP = true
+	p1.Country, p1.ASN = "BR", 28573
 	require.NoError(t, repo.Save(ctx, p1))
 
 	p2 := proxy.NewProxy("2.2.2.2", 1080, proxy.SOCKS5, "s1")
 	p2.MarkSuccess(50*time.Millisecond, proxy.Anonymous)
+	p2.SupportsConnect = true
+	p2.Country, p2.ASN = "BR", 18881
 	require.NoError(t, repo.Save(ctx, p2))
 
 	p3 := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
@@ -231,9 +267,468 @@ func TestRepository_GetAlive(t *testing.T) {
 		assert.Len(t, proxies, 2)
 	})
 
+	t.Run("filters by capability using capability index", func(t *testing.T) {
+		proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Capability: "connect"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, proxies, 2)
+	})
+
+	t.Run("filters by capability within protocol index", func(t *testing.T) {
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Protocol: "http", Capability: "plain_http"})
+		assert.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "1.1.1.1:80", proxies[0].Address())
+	})
+
+	t.Run("filters by country using country index", func(t *testing.T) {
+		proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Country: "BR"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, proxies, 2)
+
+		_, err = client.ZScore(ctx, "test:idx:country:BR", "1.1.1.1:80").Result()
+		assert.NoError(t, err)
+	})
+
+	t.Run("filters by asn using asn index", func(t *testing.T) {
+		proxies, _, total, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{ASN: 18881})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "2.2.2.2:1080", proxies[0].Address())
+	})
+
+	t.Run("filters by protocol within country index", func(t *testing.T) {
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Country: "BR", Protocol: "http"})
+		assert.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "1.1.1.1:80", proxies[0].Address())
+	})
+
+	t.Run("ignores stale country entries after the exit moves", func(t *testing.T) {
+		p := proxy.NewProxy("5.5.5.5", 3128, proxy.HTTP, "s1")
+		p.MarkSuccess(10*time.Millisecond, proxy.Elite)
+		p.Country = "AR"
+		require.NoError(t, repo.Save(ctx, p))
+
+		p.Country = "CL"
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Country: "AR"})
+		assert.NoError(t, err)
+		assert.Empty(t, proxies)
+
+		require.NoError(t, repo.RecordFailure(ctx, p))
+	})
+
+	t.Run("drops capability when a later check loses it", func(t *testing.T) {
+		p := proxy.NewProxy("4.4.4.4", 3128, proxy.HTTP, "s1")
+		p.MarkSuccess(10*time.Millisecond, proxy.Elite)
+		p.SupportsConnect = true
+		require.NoError(t, repo.Save(ctx, p))
+
+		p.SupportsConnect = false
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{Capability: "connect"})
+		assert.NoError(t, err)
+		for _, got := range proxies {
+			assert.NotEqual(t, "4.4.4.4:3128", got.Address())
+		}
+	})
+
 	t.Run("returns next cursor for pagination", func(t *testing.T) {
 		_, nextCursor, _, err := repo.GetAlive(ctx, 0, 2, proxy.FilterOptions{})
 		assert.NoError(t, err)
 		assert.Greater(t, nextCursor, float64(0))
 	})
 }
+
+func TestRepository_Session(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	repo := proxyredis.NewRepository(client, "test")
+
+	t.Run("returns empty for unknown session", func(t *testing.T) {
+		address, err := repo.GetSession(ctx, "missing")
+		assert.NoError(t, err)
+		assert.Empty(t, address)
+	})
+
+	t.Run("stores pin with ttl", func(t *testing.T) {
+		err := repo.SetSession(ctx, "login", "1.1.1.1:80", time.Minute)
+		require.NoError(t, err)
+
+		address, err := repo.GetSession(ctx, "login")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.1.1.1:80", address)
+
+		ttl, err := client.TTL(ctx, "test:session:login").Result()
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, time.Duration(0))
+	})
+}
+
+func TestRepository_RecordFailure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	repo := proxyredis.NewRepository(client, "test")
+
+	t.Run("ignores proxy that was never stored", func(t *testing.T) {
+		p := proxy.NewProxy("9.9.9.9", 8080, proxy.HTTP, "s1")
+
+		err := repo.RecordFailure(ctx, p)
+		assert.NoError(t, err)
+
+		exists, err := client.Exists(ctx, "test:data:9.9.9.9:8080").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), exists)
+	})
+
+	t.Run("persists cooldown and drops from alive indexes", func(t *testing.T) {
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		failed := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, 1, failed.FailCount)
+		assert.False(t, failed.IsReady())
+
+		for _, key := range []string{
+			"test:idx:alive",
+			"test:idx:proto:http",
+			"test:idx:anon:elite",
+			"test:idx:proto:http:anon:elite",
+			"test:idx:latency",
+		} {
+			_, err := client.ZScore(ctx, key, "1.1.1.1:8080").Result()
+			assert.ErrorIs(t, err, goredis.Nil, key)
+		}
+
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, 2, failed.FailCount)
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, proxies)
+	})
+
+	t.Run("skips proxies in cooldown when reading", func(t *testing.T) {
+		p := proxy.NewProxy("2.2.2.2", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		p.CooldownUntil = time.Now().Add(time.Hour)
+		require.NoError(t, repo.Save(ctx, p))
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, proxies)
+	})
+}
+
+func TestRepository_RecordMITM(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	repo := proxyredis.NewRepository(client, "test")
+
+	t.Run("flags proxy and drops it from every index", func(t *testing.T) {
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		p.SupportsConnect = true
+		require.NoError(t, repo.Save(ctx, p))
+
+		intercepted := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordMITM(ctx, intercepted))
+		assert.True(t, intercepted.MITM)
+
+		for _, key := range []string{
+			"test:idx:alive",
+			"test:idx:proto:http",
+			"test:idx:anon:elite",
+			"test:idx:proto:http:anon:elite",
+			"test:idx:cap:connect",
+			"test:idx:latency",
+		} {
+			_, err := client.ZScore(ctx, key, "1.1.1.1:8080").Result()
+			assert.ErrorIs(t, err, goredis.Nil, key)
+		}
+
+		blocked, err := client.SIsMember(ctx, "test:mitm", "1.1.1.1:8080").Result()
+		assert.NoError(t, err)
+		assert.True(t, blocked)
+	})
+
+	t.Run("refuses to serve the proxy again after a clean check", func(t *testing.T) {
+		p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+
+		err := repo.Save(ctx, p)
+		assert.ErrorIs(t, err, proxy.ErrIntercepted)
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, proxies)
+	})
+
+	t.Run("flags proxy that was never stored", func(t *testing.T) {
+		p := proxy.NewProxy("2.2.2.2", 8080, proxy.SOCKS5, "s1")
+		require.NoError(t, repo.RecordMITM(ctx, p))
+
+		err := repo.Save(ctx, p)
+		assert.ErrorIs(t, err, proxy.ErrIntercepted)
+	})
+}
+
+func TestRepository_GetStale(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	repo := proxyredis.NewRepository(client, "test").WithTTL(time.Hour)
+
+	seed := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, client.FlushDB(ctx).Err())
+
+		for ip, checkedAgo := range map[string]time.Duration{
+			"1.1.1.1": 20 * time.Minute,
+			"2.2.2.2": 40 * time.Minute,
+			"3.3.3.3": time.Minute,
+		} {
+			p := proxy.NewProxy(ip, 8080, proxy.HTTP, "s1")
+			p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+			p.LastCheckAt = time.Now().Add(-checkedAgo)
+			require.NoError(t, repo.Save(ctx, p))
+		}
+	}
+
+	t.Run("returns stale proxies oldest first", func(t *testing.T) {
+		seed(t)
+
+		proxies, err := repo.GetStale(ctx, time.Now().Add(-15*time.Minute), 10)
+		assert.NoError(t, err)
+		require.Len(t, proxies, 2)
+		assert.Equal(t, "2.2.2.2", proxies[0].IP)
+		assert.Equal(t, "1.1.1.1", proxies[1].IP)
+	})
+
+	t.Run("respects limit", func(t *testing.T) {
+		seed(t)
+
+		proxies, err := repo.GetStale(ctx, time.Now().Add(-15*time.Minute), 1)
+		assert.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "2.2.2.2", proxies[0].IP)
+	})
+
+	t.Run("returns nothing when all proxies are fresh", func(t *testing.T) {
+		seed(t)
+
+		proxies, err := repo.GetStale(ctx, time.Now().Add(-time.Hour), 10)
+		assert.NoError(t, err)
+		assert.Empty(t, proxies)
+	})
+
+	t.Run("bumps claimed proxies and drops expired ones", func(t *testing.T) {
+		seed(t)
+		require.NoError(t, client.Del(ctx, "test:data:1.1.1.1:8080").Err())
+
+		proxies, err := repo.GetStale(ctx, time.Now().Add(-15*time.Minute), 10)
+		assert.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "2.2.2.2", proxies[0].IP)
+
+		score, err := client.ZScore(ctx, "test:checked", "2.2.2.2:8080").Result()
+		require.NoError(t, err)
+		assert.InDelta(t, float64(time.Now().Unix()), score, 1)
+
+		_, err = client.ZScore(ctx, "test:checked", "1.1.1.1:8080").Result()
+		assert.ErrorIs(t, err, goredis.Nil)
+	})
+}
+
+func TestRepository_SourceStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	repo := proxyredis.NewRepository(client, "test")
+
+	t.Run("returns empty without stats", func(t *testing.T) {
+		stats, err := repo.ListSourceStats(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, stats)
+	})
+
+	t.Run("accumulates counters and average latency", func(t *testing.T) {
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 10, 8))
+		require.NoError(t, repo.RecordScrape(ctx, "s1", 5, 5))
+		require.NoError(t, repo.RecordVerified(ctx, "s1", 100*time.Millisecond))
+		require.NoError(t, repo.RecordVerified(ctx, "s1", 300*time.Millisecond))
+		require.NoError(t, repo.RecordScrape(ctx, "s2", 3, 3))
+
+		stats, err := repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+
+		byName := map[string]proxy.SourceStats{}
+		for _, s := range stats {
+			byName[s.Name] = s
+		}
+
+		assert.Equal(t, proxy.SourceStats{Name: "s1", Scraped: 15, Published: 13, Verified: 2, AvgLatency: 200 * time.Millisecond}, byName["s1"])
+		assert.Equal(t, proxy.SourceStats{Name: "s2", Scraped: 3, Published: 3}, byName["s2"])
+	})
+
+	t.Run("forces quarantined source back on", func(t *testing.T) {
+		until := time.Now().Add(time.Hour).Unix()
+		require.NoError(t, client.HSet(ctx, "test:source:s2", "strikes", 1, "quarantines", 2, "quarantined_until", until).Err())
+
+		require.NoError(t, repo.SetSourceForced(ctx, "s2", true))
+
+		stats, err := repo.ListSourceStats(ctx)
+		require.NoError(t, err)
+		for _, s := range stats {
+			if s.Name == "s2" {
+				assert.True(t, s.Forced)
+				assert.Zero(t, s.Quarantines)
+				assert.True(t, s.QuarantinedUntil.IsZero())
+				assert.False(t, s.IsQuarantined(time.Now()))
+			}
+		}
+
+		require.NoError(t, repo.SetSourceForced(ctx, "s2", false))
+	})
+
+	t.Run("rejects override for unknown source", func(t *testing.T) {
+		err := repo.SetSourceForced(ctx, "missing", true)
+		assert.ErrorIs(t, err, proxy.ErrSourceNotFound)
+	})
+}
+
+func TestRepository_Credentials(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	redisContainer, err := redis.Run(ctx, "redis:7-alpine")
+	require.NoError(t, err)
+	defer func() { _ = redisContainer.Terminate(ctx) }()
+
+	endpoint, err := redisContainer.Endpoint(ctx, "")
+	require.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: endpoint})
+	defer client.Close()
+
+	cipher, err := crypt.NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+
+	repo := proxyredis.NewRepository(client, "test").WithCipher(cipher)
+
+	t.Run("encrypts credentials at rest and restores them on read", func(t *testing.T) {
+		p := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+		p.Username = "alice"
+		p.Password = "s3cret"
+		p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+		require.NoError(t, repo.Save(ctx, p))
+
+		raw, err := client.Get(ctx, "test:data:3.3.3.3:8080").Result()
+		require.NoError(t, err)
+		assert.NotContains(t, raw, "alice")
+		assert.NotContains(t, raw, "s3cret")
+
+		proxies, _, _, err := repo.GetAlive(ctx, 0, 10, proxy.FilterOptions{})
+		require.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "alice", proxies[0].Username)
+		assert.Equal(t, "s3cret", proxies[0].Password)
+	})
+
+	t.Run("keeps credentials across failures", func(t *testing.T) {
+		failed := proxy.NewProxy("3.3.3.3", 8080, proxy.HTTP, "s1")
+		require.NoError(t, repo.RecordFailure(ctx, failed))
+		assert.Equal(t, "alice", failed.Username)
+		assert.Equal(t, "s3cret", failed.Password)
+	})
+
+	t.Run("refuses to store credentials without a cipher", func(t *testing.T) {
+		plain := proxyredis.NewRepository(client, "plain")
+
+		p := proxy.NewProxy("4.4.4.4", 8080, proxy.HTTP, "s1")
+		p.Username = "bob"
+		p.Password = "pw"
+
+		err := plain.Save(ctx, p)
+		assert.ErrorIs(t, err, storage.ErrNoCipher)
+	})
+}
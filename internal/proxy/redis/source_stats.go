@@ -0,0 +1,193 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+const (
+	fieldScraped        = "scraped"
+	fieldPublished      = "published"
+	fieldVerified       = "verified"
+	fieldLatencyTotalMs = "latency_total_ms"
+
+	fieldStrikes          = "strikes"
+	fieldQuarantines      = "quarantines"
+	fieldQuarantinedUntil = "quarantined_until"
+	fieldForced           = "forced"
+	fieldLastVerified     = "last_verified"
+	fieldLastCheckedAt    = "last_checked_at"
+)
+
+func (r *Repository) sourcesSetKey() string {
+	return fmt.Sprintf("%s:sources", r.keyPrefix)
+}
+
+func (r *Repository) sourceStatsKey(source string) string {
+	return fmt.Sprintf("%s:source:%s", r.keyPrefix, source)
+}
+
+func (r *Repository) RecordScrape(ctx context.Context, source string, scraped, published int) error {
+	key := r.sourceStatsKey(source)
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, r.sourcesSetKey(), source)
+	pipe.HIncrBy(ctx, key, fieldScraped, int64(scraped))
+	pipe.HIncrBy(ctx, key, fieldPublished, int64(published))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record scrape stats: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) RecordVerified(ctx context.Context, source string, latency time.Duration) error {
+	key := r.sourceStatsKey(source)
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, r.sourcesSetKey(), source)
+	pipe.HIncrBy(ctx, key, fieldVerified, 1)
+	pipe.HIncrBy(ctx, key, fieldLatencyTotalMs, latency.Milliseconds())
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("record verified stats: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListSourceStats(ctx context.Context) ([]proxy.SourceStats, error) {
+	sources, err := r.client.SMembers(ctx, r.sourcesSetKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("smembers sources: %w", err)
+	}
+
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(sources))
+	for i, source := range sources {
+		cmds[i] = pipe.HGetAll(ctx, r.sourceStatsKey(source))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("hgetall source stats: %w", err)
+	}
+
+	stats := make([]proxy.SourceStats, len(sources))
+	for i, source := range sources {
+		stats[i] = parseSourceStats(source, cmds[i].Val())
+	}
+
+	return stats, nil
+}
+
+// GetSourceStats reads the named sources, returning zero stats for the ones
+// never recorded.
+func (r *Repository) GetSourceStats(ctx context.Context, sources []string) (map[string]proxy.SourceStats, error) {
+	stats := make(map[string]proxy.SourceStats, len(sources))
+	if len(sources) == 0 {
+		return stats, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(sources))
+	for i, source := range sources {
+		cmds[i] = pipe.HGetAll(ctx, r.sourceStatsKey(source))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("hgetall source stats: %w", err)
+	}
+
+	for i, source := range sources {
+		stats[source] = parseSourceStats(source, cmds[i].Val())
+	}
+
+	return stats, nil
+}
+
+// SaveSourceHealth writes the quarantine fields of s and leaves the counters
+// and the operator override alone.
+func (r *Repository) SaveSourceHealth(ctx context.Context, s proxy.SourceStats) error {
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, r.sourcesSetKey(), s.Name)
+	pipe.HSet(ctx, r.sourceStatsKey(s.Name),
+		fieldLastVerified, s.LastVerified,
+		fieldStrikes, s.Strikes,
+		fieldQuarantines, s.Quarantines,
+		fieldQuarantinedUntil, formatUnix(s.QuarantinedUntil),
+		fieldLastCheckedAt, formatUnix(s.LastCheckedAt),
+	)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("save source health: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) SetSourceForced(ctx context.Context, source string, forced bool) error {
+	known, err := r.client.SIsMember(ctx, r.sourcesSetKey(), source).Result()
+	if err != nil {
+		return fmt.Errorf("sismember sources: %w", err)
+	}
+	if !known {
+		return proxy.ErrSourceNotFound
+	}
+
+	values := []any{fieldForced, 0}
+	if forced {
+		values = []any{fieldForced, 1, fieldStrikes, 0, fieldQuarantines, 0, fieldQuarantinedUntil, 0}
+	}
+
+	if err := r.client.HSet(ctx, r.sourceStatsKey(source), values...).Err(); err != nil {
+		return fmt.Errorf("set source override: %w", err)
+	}
+	return nil
+}
+
+func parseSourceStats(source string, fields map[string]string) proxy.SourceStats {
+	s := proxy.SourceStats{
+		Name:             source,
+		Scraped:          parseCounter(fields[fieldScraped]),
+		Published:        parseCounter(fields[fieldPublished]),
+		Verified:         parseCounter(fields[fieldVerified]),
+		Strikes:          int(parseCounter(fields[fieldStrikes])),
+		Quarantines:      int(parseCounter(fields[fieldQuarantines])),
+		QuarantinedUntil: parseUnix(fields[fieldQuarantinedUntil]),
+		Forced:           fields[fieldForced] == "1",
+		LastVerified:     parseCounter(fields[fieldLastVerified]),
+		LastCheckedAt:    parseUnix(fields[fieldLastCheckedAt]),
+	}
+	if s.Verified > 0 {
+		s.AvgLatency = time.Duration(parseCounter(fields[fieldLatencyTotalMs])/s.Verified) * time.Millisecond
+	}
+	return s
+}
+
+func parseCounter(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
+
+func parseUnix(value string) time.Time {
+	n := parseCounter(value)
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(n, 0)
+}
+
+func formatUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,89 @@
+// Package storage holds what the proxy repositories share whatever the
+// backend: the record encoding and the layout of the secondary indexes.
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+var ErrNoCipher = errors.New("proxy has credentials but no cipher is configured")
+
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+type storedProxy struct {
+	*proxy.Proxy
+	Credentials []byte `json:"Credentials,omitempty"`
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Codec turns proxies into stored records, keeping credentials encrypted
+// with Cipher. A nil Cipher refuses records that carry credentials.
+type Codec struct {
+	Cipher Cipher
+}
+
+func (c Codec) Encode(p *proxy.Proxy) ([]byte, error) {
+	stored := storedProxy{Proxy: p}
+
+	if p.HasAuth() {
+		if c.Cipher == nil {
+			return nil, ErrNoCipher
+		}
+
+		plain, err := json.Marshal(credentials{Username: p.Username, Password: p.Password})
+		if err != nil {
+			return nil, fmt.Errorf("marshal credentials: %w", err)
+		}
+
+		stored.Credentials, err = c.Cipher.Encrypt(plain)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt credentials: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("marshal proxy: %w", err)
+	}
+	return data, nil
+}
+
+func (c Codec) Decode(data []byte) (*proxy.Proxy, error) {
+	stored := storedProxy{Proxy: &proxy.Proxy{}}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("unmarshal proxy: %w", err)
+	}
+
+	if len(stored.Credentials) == 0 {
+		return stored.Proxy, nil
+	}
+
+	if c.Cipher == nil {
+		return nil, ErrNoCipher
+	}
+
+	plain, err := c.Cipher.Decrypt(stored.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt credentials: %w", err)
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(plain, &creds); err != nil {
+		return nil, fmt.Errorf("unmarshal credentials: %w", err)
+	}
+
+	stored.Username = creds.Username
+	stored.Password = creds.Password
+	return stored.Proxy, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+)
+
+// Capabilities lists every capability with its own index.
+var Capabilities = []proxy.Capability{proxy.CapabilityConnect, proxy.CapabilityPlainHTTP}
+
+// Index names are relative; each backend places them under its own
+// namespace. Every index but LatencyIndex is scored by expiration.
+const (
+	AliveIndex   = "alive"
+	LatencyIndex = "latency"
+)
+
+func ProtocolIndex(protocol string) string {
+	return fmt.Sprintf("proto:%s", protocol)
+}
+
+func AnonymityIndex(anonymity string) string {
+	return fmt.Sprintf("anon:%s", anonymity)
+}
+
+func CompositeIndex(protocol, anonymity string) string {
+	return fmt.Sprintf("proto:%s:anon:%s", protocol, anonymity)
+}
+
+func CapabilityIndex(capability proxy.Capability) string {
+	return fmt.Sprintf("cap:%s", capability)
+}
+
+func CountryIndex(country string) string {
+	return fmt.Sprintf("country:%s", country)
+}
+
+func ASNIndex(asn uint) string {
+	return fmt.Sprintf("asn:%d", asn)
+}
+
+// ExpiringIndexes lists the indexes scored by expiration that p belongs to,
+// leaving out capabilities, which are added or removed on every save.
+func ExpiringIndexes(p *proxy.Proxy) []string {
+	indexes := []string{
+		AliveIndex,
+		ProtocolIndex(string(p.Protocol)),
+		AnonymityIndex(string(p.Anonymity)),
+		CompositeIndex(string(p.Protocol), string(p.Anonymity)),
+	}
+	if p.Country != "" {
+		indexes = append(indexes, CountryIndex(p.Country))
+	}
+	if p.ASN != 0 {
+		indexes = append(indexes, ASNIndex(p.ASN))
+	}
+	return indexes
+}
+
+// SelectIndex picks the narrowest index to read for filter.
+func SelectIndex(filter proxy.FilterOptions) string {
+	hasProtocol := filter.Protocol != ""
+	hasAnonymity := filter.Anonymity != ""
+
+	switch {
+	case filter.Country != "":
+		return CountryIndex(filter.Country)
+	case filter.ASN != 0:
+		return ASNIndex(filter.ASN)
+	case filter.Capability != "" && !hasProtocol && !hasAnonymity:
+		return CapabilityIndex(proxy.Capability(filter.Capability))
+	case hasProtocol && hasAnonymity:
+		return CompositeIndex(filter.Protocol, filter.Anonymity)
+	case hasProtocol:
+		return ProtocolIndex(filter.Protocol)
+	case hasAnonymity:
+		return AnonymityIndex(filter.Anonymity)
+	default:
+		return AliveIndex
+	}
+}
+
+// Matches checks every filter on a loaded proxy. Only one index is read per
+// request, and location indexes may still hold an address whose exit moved
+// since the last save until its score expires.
+func Matches(p *proxy.Proxy, filter proxy.FilterOptions) bool {
+	switch {
+	case filter.Protocol != "" && string(p.Protocol) != filter.Protocol:
+		return false
+	case filter.Anonymity != "" && string(p.Anonymity) != filter.Anonymity:
+		return false
+	case filter.Capability != "" && !p.Supports(proxy.Capability(filter.Capability)):
+		return false
+	case filter.Country != "" && p.Country != filter.Country:
+		return false
+	case filter.ASN != 0 && p.ASN != filter.ASN:
+		return false
+	case filter.MaxLatency > 0 && p.Latency > filter.MaxLatency:
+		return false
+	default:
+		return true
+	}
+}
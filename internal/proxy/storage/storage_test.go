@@ -0,0 +1,105 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/crypt"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+)
+
+func TestCodec(t *testing.T) {
+	cipher, err := crypt.NewAESGCM(make([]byte, 32))
+	require.NoError(t, err)
+
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	p.Username = "alice"
+	p.Password = "s3cret"
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+
+	t.Run("encrypts credentials and restores them", func(t *testing.T) {
+		codec := storage.Codec{Cipher: cipher}
+
+		data, err := codec.Encode(p)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "alice")
+		assert.NotContains(t, string(data), "s3cret")
+
+		decoded, err := codec.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", decoded.Username)
+		assert.Equal(t, "s3cret", decoded.Password)
+		assert.Equal(t, p.Address(), decoded.Address())
+		assert.Equal(t, proxy.Elite, decoded.Anonymity)
+	})
+
+	t.Run("refuses credentials without a cipher", func(t *testing.T) {
+		_, err := storage.Codec{}.Encode(p)
+		assert.ErrorIs(t, err, storage.ErrNoCipher)
+
+		data, err := storage.Codec{Cipher: cipher}.Encode(p)
+		require.NoError(t, err)
+		_, err = storage.Codec{}.Decode(data)
+		assert.ErrorIs(t, err, storage.ErrNoCipher)
+	})
+
+	t.Run("stores proxies without credentials as they are", func(t *testing.T) {
+		plain := proxy.NewProxy("2.2.2.2", 1080, proxy.SOCKS5, "s1")
+
+		data, err := storage.Codec{}.Encode(plain)
+		require.NoError(t, err)
+
+		decoded, err := storage.Codec{}.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, plain.Address(), decoded.Address())
+	})
+}
+
+func TestSelectIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter proxy.FilterOptions
+		want   string
+	}{
+		{"no filter", proxy.FilterOptions{}, storage.AliveIndex},
+		{"protocol", proxy.FilterOptions{Protocol: "http"}, "proto:http"},
+		{"anonymity", proxy.FilterOptions{Anonymity: "elite"}, "anon:elite"},
+		{"protocol and anonymity", proxy.FilterOptions{Protocol: "http", Anonymity: "elite"}, "proto:http:anon:elite"},
+		{"capability", proxy.FilterOptions{Capability: "connect"}, "cap:connect"},
+		{"capability within protocol", proxy.FilterOptions{Protocol: "http", Capability: "connect"}, "proto:http"},
+		{"country first", proxy.FilterOptions{Country: "BR", Protocol: "http"}, "country:BR"},
+		{"asn", proxy.FilterOptions{ASN: 28573, Protocol: "http"}, "asn:28573"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, storage.SelectIndex(tt.filter))
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	p.SupportsConnect = true
+	p.Country, p.ASN = "BR", 28573
+
+	assert.True(t, storage.Matches(p, proxy.FilterOptions{Protocol: "http", Anonymity: "elite", Capability: "connect", Country: "BR", ASN: 28573, MaxLatency: time.Second}))
+	assert.False(t, storage.Matches(p, proxy.FilterOptions{Protocol: "socks5"}))
+	assert.False(t, storage.Matches(p, proxy.FilterOptions{Capability: "plain_http"}))
+	assert.False(t, storage.Matches(p, proxy.FilterOptions{Country: "AR"}))
+	assert.False(t, storage.Matches(p, proxy.FilterOptions{MaxLatency: 50 * time.Millisecond}))
+}
+
+func TestExpiringIndexes(t *testing.T) {
+	p := proxy.NewProxy("1.1.1.1", 8080, proxy.HTTP, "s1")
+	p.MarkSuccess(100*time.Millisecond, proxy.Elite)
+	assert.Equal(t, []string{"alive", "proto:http", "anon:elite", "proto:http:anon:elite"}, storage.ExpiringIndexes(p))
+
+	p.Country, p.ASN = "BR", 28573
+	assert.Equal(t, []string{"alive", "proto:http", "anon:elite", "proto:http:anon:elite", "country:BR", "asn:28573"}, storage.ExpiringIndexes(p))
+}
@@ -5,4 +5,14 @@ import "errors"
 var (
 	ErrSourceUnavailable = errors.New("source unavailable")
 	ErrInvalidProxy      = errors.New("invalid proxy format")
+	ErrUnsupportedFormat = errors.New("unsupported source format")
+	ErrInvalidSource     = errors.New("invalid source config")
 )
+
+type SourceError struct {
+	Source string
+	Err    error
+}
+
+func (e *SourceError) Error() string { return e.Err.Error() }
+func (e *SourceError) Unwrap() error { return e.Err }
@@ -0,0 +1,140 @@
+package sourcefile
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type sourcesFile struct {
+	Sources []sourceEntry `yaml:"sources"`
+}
+
+type sourceEntry struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Type    string            `yaml:"type"`
+	Format  string            `yaml:"format"`
+	Timeout string            `yaml:"timeout"`
+	Headers map[string]string `yaml:"headers"`
+	Enabled *bool             `yaml:"enabled"`
+	Fields  fieldsEntry       `yaml:"fields"`
+}
+
+type fieldsEntry struct {
+	Path     string `yaml:"path"`
+	IP       string `yaml:"ip"`
+	Port     string `yaml:"port"`
+	Protocol string `yaml:"protocol"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+var validTypes = map[string]bool{
+	"http": true, "https": true, "socks4": true, "socks5": true,
+}
+
+var validFormats = map[string]bool{
+	"":                 true,
+	scraper.FormatText: true,
+	scraper.FormatJSON: true,
+	scraper.FormatCSV:  true,
+	scraper.FormatHTML: true,
+}
+
+func Load(path string) ([]scraper.Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) ([]scraper.Source, error) {
+	var file sourcesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode sources: %w: %w", scraper.ErrInvalidSource, err)
+	}
+
+	if len(file.Sources) == 0 {
+		return nil, fmt.Errorf("no sources defined: %w", scraper.ErrInvalidSource)
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(file.Sources))
+	sources := make([]scraper.Source, 0, len(file.Sources))
+
+	for i, entry := range file.Sources {
+		source, err := entry.toSource()
+		if err == nil && seen[entry.Name] {
+			err = fmt.Errorf("duplicate name")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sources[%d] %q: %w", i, entry.Name, err))
+			continue
+		}
+		seen[entry.Name] = true
+
+		if entry.Enabled != nil && !*entry.Enabled {
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", scraper.ErrInvalidSource, errors.Join(errs...))
+	}
+
+	return sources, nil
+}
+
+func (e sourceEntry) toSource() (scraper.Source, error) {
+	if strings.TrimSpace(e.Name) == "" {
+		return scraper.Source{}, fmt.Errorf("name is required")
+	}
+
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return scraper.Source{}, fmt.Errorf("url %q must be an absolute http(s) url", e.URL)
+	}
+
+	if !validTypes[e.Type] {
+		return scraper.Source{}, fmt.Errorf("type %q must be one of http, https, socks4, socks5", e.Type)
+	}
+
+	if !validFormats[e.Format] {
+		return scraper.Source{}, fmt.Errorf("format %q must be one of text, json, csv, html", e.Format)
+	}
+
+	var timeout time.Duration
+	if e.Timeout != "" {
+		timeout, err = time.ParseDuration(e.Timeout)
+		if err != nil || timeout <= 0 {
+			return scraper.Source{}, fmt.Errorf("timeout %q must be a positive duration like 30s", e.Timeout)
+		}
+	}
+
+	return scraper.Source{
+		Name:    e.Name,
+		URL:     e.URL,
+		Type:    e.Type,
+		Format:  e.Format,
+		Timeout: timeout,
+		Headers: e.Headers,
+		Fields: scraper.FieldMapping{
+			Path:     e.Fields.Path,
+			IP:       e.Fields.IP,
+			Port:     e.Fields.Port,
+			Protocol: e.Fields.Protocol,
+			Username: e.Fields.Username,
+			Password: e.Fields.Password,
+		},
+	}, nil
+}
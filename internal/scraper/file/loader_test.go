@@ -0,0 +1,164 @@
+package sourcefile_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	sourcefile "github.com/JulianoL13/app-proxy-engine/internal/scraper/file"
+)
+
+type testLogger struct{}
+
+func (l testLogger) Info(msg string, args ...any) {}
+func (l testLogger) Warn(msg string, args ...any) {}
+
+func TestParse(t *testing.T) {
+	t.Run("parses yaml sources", func(t *testing.T) {
+		data := []byte(`
+sources:
+  - name: list
+    url: https://example.com/list.txt
+    type: socks5
+    timeout: 20s
+    headers:
+      X-Token: abc
+  - name: api
+    url: https://example.com/api
+    type: http
+    format: json
+    fields:
+      path: data.items
+      ip: host
+  - name: off
+    url: https://example.com/off
+    type: http
+    enabled: false
+`)
+
+		sources, err := sourcefile.Parse(data)
+		require.NoError(t, err)
+		require.Len(t, sources, 2)
+
+		assert.Equal(t, "list", sources[0].Name)
+		assert.Equal(t, "socks5", sources[0].Type)
+		assert.Equal(t, 20*time.Second, sources[0].Timeout)
+		assert.Equal(t, map[string]string{"X-Token": "abc"}, sources[0].Headers)
+
+		assert.Equal(t, scraper.FormatJSON, sources[1].Format)
+		assert.Equal(t, "data.items", sources[1].Fields.Path)
+		assert.Equal(t, "host", sources[1].Fields.IP)
+	})
+
+	t.Run("parses json sources", func(t *testing.T) {
+		data := []byte(`{"sources":[{"name":"a","url":"http://example.com/a","type":"http","format":"csv"}]}`)
+
+		sources, err := sourcefile.Parse(data)
+		require.NoError(t, err)
+		require.Len(t, sources, 1)
+		assert.Equal(t, scraper.FormatCSV, sources[0].Format)
+	})
+
+	t.Run("reports every invalid entry", func(t *testing.T) {
+		data := []byte(`
+sources:
+  - name: ""
+    url: https://example.com
+    type: http
+  - name: bad-url
+    url: ftp://example.com
+    type: http
+  - name: bad-type
+    url: https://example.com
+    type: smtp
+  - name: bad-format
+    url: https://example.com
+    type: http
+    format: xml
+  - name: bad-timeout
+    url: https://example.com
+    type: http
+    timeout: soon
+  - name: dup
+    url: https://example.com
+    type: http
+  - name: dup
+    url: https://example.com
+    type: http
+`)
+
+		_, err := sourcefile.Parse(data)
+		require.ErrorIs(t, err, scraper.ErrInvalidSource)
+		for _, want := range []string{
+			"sources[0]",
+			`sources[1] "bad-url"`,
+			`sources[2] "bad-type"`,
+			`sources[3] "bad-format"`,
+			`sources[4] "bad-timeout"`,
+			`sources[6] "dup": duplicate name`,
+		} {
+			assert.Contains(t, err.Error(), want)
+		}
+	})
+
+	t.Run("rejects empty file", func(t *testing.T) {
+		_, err := sourcefile.Parse([]byte("sources: []"))
+		assert.ErrorIs(t, err, scraper.ErrInvalidSource)
+	})
+
+	t.Run("loads the example file", func(t *testing.T) {
+		sources, err := sourcefile.Load("../../../config/sources.example.yaml")
+		require.NoError(t, err)
+		assert.NotEmpty(t, sources)
+	})
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sources.yaml")
+	write := func(content string, mtime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	write("sources:\n  - {name: a, url: https://example.com/a, type: http}\n", time.Now().Add(-time.Hour))
+
+	t.Run("fails on invalid file at startup", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+		require.NoError(t, os.WriteFile(invalid, []byte("sources:\n  - {name: a}\n"), 0o600))
+
+		_, err := sourcefile.NewWatcher(invalid, time.Second, testLogger{})
+		assert.ErrorIs(t, err, scraper.ErrInvalidSource)
+	})
+
+	t.Run("fails on missing file", func(t *testing.T) {
+		_, err := sourcefile.NewWatcher(filepath.Join(t.TempDir(), "missing.yaml"), time.Second, testLogger{})
+		assert.Error(t, err)
+	})
+
+	w, err := sourcefile.NewWatcher(path, 10*time.Millisecond, testLogger{})
+	require.NoError(t, err)
+	require.Len(t, w.Sources(), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Watch(ctx)
+
+	t.Run("reloads on change", func(t *testing.T) {
+		write("sources:\n  - {name: a, url: https://example.com/a, type: http}\n  - {name: b, url: https://example.com/b, type: socks5}\n", time.Now())
+
+		assert.Eventually(t, func() bool { return len(w.Sources()) == 2 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("keeps previous sources when reload is invalid", func(t *testing.T) {
+		write("sources:\n  - {name: c, url: nope, type: http}\n", time.Now().Add(time.Minute))
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Len(t, w.Sources(), 2)
+	})
+}
@@ -0,0 +1,101 @@
+package sourcefile
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+}
+
+type Watcher struct {
+	path     string
+	interval time.Duration
+	logger   Logger
+
+	mu      sync.RWMutex
+	sources []scraper.Source
+	modTime time.Time
+	size    int64
+}
+
+func NewWatcher(path string, interval time.Duration, logger Logger) (*Watcher, error) {
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		logger:   logger,
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w.sources = sources
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+
+	return w, nil
+}
+
+func (w *Watcher) Sources() []scraper.Source {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.sources
+}
+
+func (w *Watcher) Watch(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.reload()
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		w.logger.Warn("failed to stat sources file", "path", w.path, "error", err)
+		return
+	}
+
+	w.mu.RLock()
+	unchanged := info.ModTime().Equal(w.modTime) && info.Size() == w.size
+	w.mu.RUnlock()
+	if unchanged {
+		return
+	}
+
+	sources, err := Load(w.path)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+
+	if err != nil {
+		w.logger.Warn("invalid sources file, keeping previous sources", "path", w.path, "error", err)
+		return
+	}
+
+	w.sources = sources
+	w.logger.Info("sources reloaded", "path", w.path, "sources", len(sources))
+}
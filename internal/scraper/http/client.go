@@ -1,16 +1,17 @@
 package httpclient
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
 )
 
@@ -19,6 +20,7 @@ const (
 	KB          = 1 << (10 * iota)
 	MB          = 1 << (10 * iota)
 	maxBodySize = 10 * MB
+	tracerName  = "github.com/JulianoL13/app-proxy-engine/internal/scraper/http"
 )
 
 type Logger interface {
@@ -26,8 +28,9 @@ type Logger interface {
 }
 
 type Fetcher struct {
-	client *http.Client
-	logger Logger
+	client  *http.Client
+	logger  Logger
+	parsers map[string]Parser
 }
 
 func New(logger Logger) *Fetcher {
@@ -41,20 +44,64 @@ func New(logger Logger) *Fetcher {
 			},
 		},
 		logger: logger,
+		parsers: map[string]Parser{
+			scraper.FormatText: &textParser{logger: logger},
+			scraper.FormatJSON: &jsonParser{logger: logger},
+			scraper.FormatCSV:  &csvParser{logger: logger},
+			scraper.FormatHTML: &htmlParser{logger: logger},
+		},
 	}
 }
 
+func (f *Fetcher) WithParser(format string, p Parser) *Fetcher {
+	f.parsers[format] = p
+	return f
+}
+
 func (f *Fetcher) FetchAndParse(ctx context.Context, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "scraper.fetch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("scraper.source", source.Name),
+			attribute.String("scraper.format", source.Format),
+		),
+	)
+	defer span.End()
+
+	proxies, err := f.fetchAndParse(ctx, source)
+	span.SetAttributes(attribute.Int("scraper.proxies", len(proxies)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return proxies, err
+}
+
+func (f *Fetcher) fetchAndParse(ctx context.Context, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	format := source.Format
+	if format == "" {
+		format = scraper.FormatText
+	}
+
+	parser, ok := f.parsers[format]
+	if !ok {
+		return nil, fmt.Errorf("source %s: format %q: %w", source.Name, format, scraper.ErrUnsupportedFormat)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("source %s: create request: %w", source.Name, err)
 	}
 
 	req.Header.Set("User-Agent", "ProxyEngine/1.0")
+	for key, value := range source.Headers {
+		req.Header.Set(key, value)
+	}
 
 	resp, err := f.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("source %s: fetch: %w", source.Name, err)
+		return nil, fmt.Errorf("source %s: fetch: %w: %w", source.Name, scraper.ErrSourceUnavailable, err)
 	}
 	defer resp.Body.Close()
 
@@ -62,58 +109,10 @@ func (f *Fetcher) FetchAndParse(ctx context.Context, source scraper.Source) ([]*
 		return nil, fmt.Errorf("source %s: status %d: %w", source.Name, resp.StatusCode, scraper.ErrSourceUnavailable)
 	}
 
-	limitedReader := io.LimitReader(resp.Body, maxBodySize)
-
-	var proxies []*scraper.ScrapeOutput
-	scanner := bufio.NewScanner(limitedReader)
-	for scanner.Scan() {
-		if ctx.Err() != nil {
-			return proxies, ctx.Err()
-		}
-
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		p, err := parseLine(line, source)
-		if err != nil {
-			f.logger.Debug("parse error", "line", line, "error", err)
-			continue
-		}
-		proxies = append(proxies, p)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("source %s: scan: %w", source.Name, err)
+	proxies, err := parser.Parse(ctx, io.LimitReader(resp.Body, maxBodySize), source)
+	if err != nil {
+		return proxies, fmt.Errorf("source %s: parse: %w", source.Name, err)
 	}
 
 	return proxies, nil
 }
-
-func parseLine(line string, source scraper.Source) (*scraper.ScrapeOutput, error) {
-	parts := strings.Split(line, ":")
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("invalid format")
-	}
-
-	ip := strings.TrimSpace(parts[0])
-	portStr := strings.TrimSpace(parts[1])
-
-	if net.ParseIP(ip) == nil {
-		return nil, fmt.Errorf("invalid ip: %s", ip)
-	}
-
-	port, err := strconv.Atoi(portStr)
-	if err != nil || port <= 0 || port > 65535 {
-		return nil, fmt.Errorf("invalid port")
-	}
-
-	if len(parts) == 4 {
-		username := strings.TrimSpace(parts[2])
-		password := strings.TrimSpace(parts[3])
-		return scraper.NewScrapeOutputWithAuth(ip, port, source.Type, source.Name, username, password), nil
-	}
-
-	return scraper.NewScrapeOutput(ip, port, source.Type, source.Name), nil
-}
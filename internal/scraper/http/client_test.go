@@ -0,0 +1,183 @@
+package httpclient_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	httpclient "github.com/JulianoL13/app-proxy-engine/internal/scraper/http"
+)
+
+type testLogger struct{}
+
+func (l testLogger) Debug(msg string, args ...any) {}
+
+func serve(t *testing.T, body string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func addresses(proxies []*scraper.ScrapeOutput) []string {
+	out := make([]string, len(proxies))
+	for i, p := range proxies {
+		out[i] = p.Protocol() + "://" + net.JoinHostPort(p.IP(), strconv.Itoa(p.Port()))
+	}
+	return out
+}
+
+func TestFetcher_FetchAndParse(t *testing.T) {
+	ctx := context.Background()
+	fetcher := httpclient.New(testLogger{})
+
+	t.Run("parses text lists by default", func(t *testing.T) {
+		url := serve(t, "# comment\n1.1.1.1:8080\ninvalid\n2.2.2.2:3128:user:pass\n")
+
+		proxies, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "txt", URL: url, Type: "http"})
+		require.NoError(t, err)
+		require.Len(t, proxies, 2)
+		assert.Equal(t, []string{"http://1.1.1.1:8080", "http://2.2.2.2:3128"}, addresses(proxies))
+		assert.True(t, proxies[1].HasAuth())
+		assert.Equal(t, "txt", proxies[0].Source())
+	})
+
+	t.Run("parses json with path and field names", func(t *testing.T) {
+		url := serve(t, `{"data":{"proxies":[
+			{"addr":"1.1.1.1","p":8080,"type":"SOCKS5"},
+			{"addr":"2.2.2.2","p":"3128"},
+			{"addr":"bad","p":80}
+		]}}`)
+
+		source := scraper.Source{
+			Name:   "json",
+			URL:    url,
+			Type:   "http",
+			Format: scraper.FormatJSON,
+			Fields: scraper.FieldMapping{Path: "data.proxies", IP: "addr", Port: "p", Protocol: "type"},
+		}
+
+		proxies, err := fetcher.FetchAndParse(ctx, source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"socks5://1.1.1.1:8080", "http://2.2.2.2:3128"}, addresses(proxies))
+	})
+
+	t.Run("parses json root array with default fields", func(t *testing.T) {
+		url := serve(t, `[{"ip":"1.1.1.1","port":8080,"username":"u","password":"p"}]`)
+
+		proxies, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "json", URL: url, Type: "http", Format: scraper.FormatJSON})
+		require.NoError(t, err)
+		require.Len(t, proxies, 1)
+		assert.Equal(t, "u", proxies[0].Username())
+		assert.Equal(t, "p", proxies[0].Password())
+	})
+
+	t.Run("fails when json path is not an array", func(t *testing.T) {
+		url := serve(t, `{"data":{"count":1}}`)
+
+		source := scraper.Source{Name: "json", URL: url, Type: "http", Format: scraper.FormatJSON, Fields: scraper.FieldMapping{Path: "data.count"}}
+
+		_, err := fetcher.FetchAndParse(ctx, source)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, scraper.ErrSourceUnavailable)
+	})
+
+	t.Run("parses csv with header row", func(t *testing.T) {
+		url := serve(t, "Port,IP Address,Protocol\n8080,1.1.1.1,https\n3128,2.2.2.2,\nx,3.3.3.3,http\n")
+
+		source := scraper.Source{
+			Name:   "csv",
+			URL:    url,
+			Type:   "http",
+			Format: scraper.FormatCSV,
+			Fields: scraper.FieldMapping{IP: "ip address"},
+		}
+
+		proxies, err := fetcher.FetchAndParse(ctx, source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://1.1.1.1:8080", "http://2.2.2.2:3128"}, addresses(proxies))
+	})
+
+	t.Run("parses csv with combined address column", func(t *testing.T) {
+		url := serve(t, "proxy,country\n1.1.1.1:8080,BR\n")
+
+		source := scraper.Source{Name: "csv", URL: url, Type: "socks4", Format: scraper.FormatCSV, Fields: scraper.FieldMapping{IP: "proxy"}}
+
+		proxies, err := fetcher.FetchAndParse(ctx, source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"socks4://1.1.1.1:8080"}, addresses(proxies))
+	})
+
+	t.Run("fails when csv header lacks ip column", func(t *testing.T) {
+		url := serve(t, "host,port\n1.1.1.1,8080\n")
+
+		_, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "csv", URL: url, Type: "http", Format: scraper.FormatCSV})
+		assert.Error(t, err)
+	})
+
+	t.Run("extracts html table", func(t *testing.T) {
+		url := serve(t, `<html><body>
+			<table><tr><td>nav</td></tr></table>
+			<table>
+				<thead><tr><th>IP</th><th>Port</th><th>Type</th></tr></thead>
+				<tbody>
+					<tr><td>1.1.1.1</td><td>8080</td><td>HTTPS</td></tr>
+					<tr><td><b>2.2.2.2</b></td><td>3128</td><td>http</td></tr>
+					<tr><td>not-an-ip</td><td>80</td><td>http</td></tr>
+				</tbody>
+			</table>
+		</body></html>`)
+
+		source := scraper.Source{
+			Name:   "html",
+			URL:    url,
+			Type:   "http",
+			Format: scraper.FormatHTML,
+			Fields: scraper.FieldMapping{Protocol: "type"},
+		}
+
+		proxies, err := fetcher.FetchAndParse(ctx, source)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://1.1.1.1:8080", "http://2.2.2.2:3128"}, addresses(proxies))
+	})
+
+	t.Run("fails when no html table matches", func(t *testing.T) {
+		url := serve(t, `<table><tr><th>Host</th></tr><tr><td>x</td></tr></table>`)
+
+		_, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "html", URL: url, Type: "http", Format: scraper.FormatHTML})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		_, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "x", URL: "http://127.0.0.1:1", Type: "http", Format: "xml"})
+		assert.ErrorIs(t, err, scraper.ErrUnsupportedFormat)
+	})
+
+	t.Run("returns source unavailable on bad status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		_, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "x", URL: srv.URL, Type: "http"})
+		assert.ErrorIs(t, err, scraper.ErrSourceUnavailable)
+	})
+
+	t.Run("returns source unavailable when the fetch fails", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		_, err := fetcher.FetchAndParse(ctx, scraper.Source{Name: "x", URL: srv.URL, Type: "http"})
+		assert.ErrorIs(t, err, scraper.ErrSourceUnavailable)
+	})
+}
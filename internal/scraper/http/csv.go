@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type csvParser struct {
+	logger Logger
+}
+
+func (p *csvParser) Parse(ctx context.Context, r io.Reader, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns, err := newColumns(header, fieldsWithDefaults(source.Fields))
+	if err != nil {
+		return nil, err
+	}
+
+	var proxies []*scraper.ScrapeOutput
+	for {
+		if ctx.Err() != nil {
+			return proxies, ctx.Err()
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			p.logger.Debug("parse error", "error", err)
+			continue
+		}
+
+		out, err := columns.output(source, record)
+		if err != nil {
+			p.logger.Debug("parse error", "record", record, "error", err)
+			continue
+		}
+		proxies = append(proxies, out)
+	}
+
+	return proxies, nil
+}
+
+type columns struct {
+	ip, port, protocol, username, password int
+}
+
+func newColumns(header []string, fields scraper.FieldMapping) (columns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	find := func(name string) int {
+		if i, ok := index[strings.ToLower(name)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	c := columns{
+		ip:       find(fields.IP),
+		port:     find(fields.Port),
+		protocol: find(fields.Protocol),
+		username: find(fields.Username),
+		password: find(fields.Password),
+	}
+	if c.ip < 0 {
+		return c, fmt.Errorf("header missing %q column", fields.IP)
+	}
+
+	return c, nil
+}
+
+func (c columns) output(source scraper.Source, row []string) (*scraper.ScrapeOutput, error) {
+	cell := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return row[i]
+	}
+
+	return newOutput(source, cell(c.ip), cell(c.port), cell(c.protocol), cell(c.username), cell(c.password))
+}
@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type htmlParser struct {
+	logger Logger
+}
+
+func (p *htmlParser) Parse(ctx context.Context, r io.Reader, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+
+	fields := fieldsWithDefaults(source.Fields)
+
+	var proxies []*scraper.ScrapeOutput
+	matched := false
+	for _, table := range findAll(doc, atom.Table) {
+		rows := tableRows(table)
+		if len(rows) == 0 {
+			continue
+		}
+
+		columns, err := newColumns(rows[0], fields)
+		if err != nil {
+			continue
+		}
+		matched = true
+
+		for _, row := range rows[1:] {
+			if ctx.Err() != nil {
+				return proxies, ctx.Err()
+			}
+
+			out, err := columns.output(source, row)
+			if err != nil {
+				p.logger.Debug("parse error", "row", row, "error", err)
+				continue
+			}
+			proxies = append(proxies, out)
+		}
+	}
+
+	if !matched {
+		return nil, fmt.Errorf("no table with %q column", fields.IP)
+	}
+
+	return proxies, nil
+}
+
+func tableRows(table *html.Node) [][]string {
+	var rows [][]string
+	for _, tr := range findAll(table, atom.Tr) {
+		var cells []string
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Td || c.DataAtom == atom.Th {
+				cells = append(cells, strings.TrimSpace(textContent(c)))
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	}
+	return rows
+}
+
+func findAll(n *html.Node, a atom.Atom) []*html.Node {
+	var found []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == a {
+			found = append(found, c)
+			continue
+		}
+		found = append(found, findAll(c, a)...)
+	}
+	return found
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type jsonParser struct {
+	logger Logger
+}
+
+func (p *jsonParser) Parse(ctx context.Context, r io.Reader, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	node, ok := lookup(doc, source.Fields.Path)
+	if !ok {
+		return nil, fmt.Errorf("path %q not found", source.Fields.Path)
+	}
+
+	items, ok := node.([]any)
+	if !ok {
+		return nil, fmt.Errorf("path %q is not an array", source.Fields.Path)
+	}
+
+	fields := fieldsWithDefaults(source.Fields)
+
+	var proxies []*scraper.ScrapeOutput
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return proxies, ctx.Err()
+		}
+
+		out, err := newOutput(source,
+			jsonString(item, fields.IP),
+			jsonString(item, fields.Port),
+			jsonString(item, fields.Protocol),
+			jsonString(item, fields.Username),
+			jsonString(item, fields.Password),
+		)
+		if err != nil {
+			p.logger.Debug("parse error", "item", item, "error", err)
+			continue
+		}
+		proxies = append(proxies, out)
+	}
+
+	return proxies, nil
+}
+
+func lookup(node any, path string) (any, bool) {
+	if path == "" {
+		return node, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		node, ok = obj[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+func jsonString(item any, path string) string {
+	value, ok := lookup(item, path)
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+type Parser interface {
+	Parse(ctx context.Context, r io.Reader, source scraper.Source) ([]*scraper.ScrapeOutput, error)
+}
+
+var validProtocols = map[string]bool{
+	"http": true, "https": true, "socks4": true, "socks5": true,
+}
+
+type textParser struct {
+	logger Logger
+}
+
+func (p *textParser) Parse(ctx context.Context, r io.Reader, source scraper.Source) ([]*scraper.ScrapeOutput, error) {
+	var proxies []*scraper.ScrapeOutput
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return proxies, ctx.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		out, err := parseLine(line, source)
+		if err != nil {
+			p.logger.Debug("parse error", "line", line, "error", err)
+			continue
+		}
+		proxies = append(proxies, out)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return proxies, nil
+}
+
+func parseLine(line string, source scraper.Source) (*scraper.ScrapeOutput, error) {
+	parts := strings.Split(line, ":")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid format")
+	}
+
+	if len(parts) == 4 {
+		return newOutput(source, parts[0], parts[1], "", parts[2], parts[3])
+	}
+
+	return newOutput(source, parts[0], parts[1], "", "", "")
+}
+
+func newOutput(source scraper.Source, ip, port, protocol, username, password string) (*scraper.ScrapeOutput, error) {
+	ip = strings.TrimSpace(ip)
+	port = strings.TrimSpace(port)
+
+	if port == "" {
+		if host, p, err := net.SplitHostPort(ip); err == nil {
+			ip, port = host, p
+		}
+	}
+
+	if net.ParseIP(ip) == nil {
+		return nil, fmt.Errorf("invalid ip: %s", ip)
+	}
+
+	portNum, err := strconv.Atoi(port)
+	if err != nil || portNum <= 0 || portNum > 65535 {
+		return nil, fmt.Errorf("invalid port")
+	}
+
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol == "" {
+		protocol = source.Type
+	}
+	if !validProtocols[protocol] {
+		return nil, fmt.Errorf("invalid protocol: %s", protocol)
+	}
+
+	username = strings.TrimSpace(username)
+	password = strings.TrimSpace(password)
+	if username != "" || password != "" {
+		return scraper.NewScrapeOutputWithAuth(ip, portNum, protocol, source.Name, username, password), nil
+	}
+
+	return scraper.NewScrapeOutput(ip, portNum, protocol, source.Name), nil
+}
+
+func fieldsWithDefaults(m scraper.FieldMapping) scraper.FieldMapping {
+	if m.IP == "" {
+		m.IP = "ip"
+	}
+	if m.Port == "" {
+		m.Port = "port"
+	}
+	if m.Protocol == "" {
+		m.Protocol = "protocol"
+	}
+	if m.Username == "" {
+		m.Username = "username"
+	}
+	if m.Password == "" {
+		m.Password = "password"
+	}
+	return m
+}
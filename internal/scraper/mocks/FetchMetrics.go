@@ -0,0 +1,72 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// FetchMetrics is an autogenerated mock type for the FetchMetrics type
+type FetchMetrics struct {
+	mock.Mock
+}
+
+type FetchMetrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *FetchMetrics) EXPECT() *FetchMetrics_Expecter {
+	return &FetchMetrics_Expecter{mock: &_m.Mock}
+}
+
+// ObserveFetch provides a mock function with given fields: source, result, proxies, duration
+func (_m *FetchMetrics) ObserveFetch(source string, result string, proxies int, duration time.Duration) {
+	_m.Called(source, result, proxies, duration)
+}
+
+// FetchMetrics_ObserveFetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveFetch'
+type FetchMetrics_ObserveFetch_Call struct {
+	*mock.Call
+}
+
+// ObserveFetch is a helper method to define mock.On call
+//   - source string
+//   - result string
+//   - proxies int
+//   - duration time.Duration
+func (_e *FetchMetrics_Expecter) ObserveFetch(source interface{}, result interface{}, proxies interface{}, duration interface{}) *FetchMetrics_ObserveFetch_Call {
+	return &FetchMetrics_ObserveFetch_Call{Call: _e.mock.On("ObserveFetch", source, result, proxies, duration)}
+}
+
+func (_c *FetchMetrics_ObserveFetch_Call) Run(run func(source string, result string, proxies int, duration time.Duration)) *FetchMetrics_ObserveFetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *FetchMetrics_ObserveFetch_Call) Return() *FetchMetrics_ObserveFetch_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *FetchMetrics_ObserveFetch_Call) RunAndReturn(run func(string, string, int, time.Duration)) *FetchMetrics_ObserveFetch_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewFetchMetrics creates a new instance of FetchMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFetchMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FetchMetrics {
+	mock := &FetchMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
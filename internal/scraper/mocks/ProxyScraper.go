@@ -23,9 +23,9 @@ func (_m *ProxyScraper) EXPECT() *ProxyScraper_Expecter {
 	return &ProxyScraper_Expecter{mock: &_m.Mock}
 }
 
-// Execute provides a mock function with given fields: ctx
-func (_m *ProxyScraper) Execute(ctx context.Context) ([]scraper.ScrapedProxy, []error) {
-	ret := _m.Called(ctx)
+// Execute provides a mock function with given fields: ctx, skip
+func (_m *ProxyScraper) Execute(ctx context.Context, skip map[string]bool) ([]scraper.ScrapedProxy, []error) {
+	ret := _m.Called(ctx, skip)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Execute")
@@ -33,19 +33,19 @@ func (_m *ProxyScraper) Execute(ctx context.Context) ([]scraper.ScrapedProxy, []
 
 	var r0 []scraper.ScrapedProxy
 	var r1 []error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]scraper.ScrapedProxy, []error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]bool) ([]scraper.ScrapedProxy, []error)); ok {
+		return rf(ctx, skip)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []scraper.ScrapedProxy); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]bool) []scraper.ScrapedProxy); ok {
+		r0 = rf(ctx, skip)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]scraper.ScrapedProxy)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) []error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, map[string]bool) []error); ok {
+		r1 = rf(ctx, skip)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).([]error)
@@ -62,13 +62,14 @@ type ProxyScraper_Execute_Call struct {
 
 // Execute is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *ProxyScraper_Expecter) Execute(ctx interface{}) *ProxyScraper_Execute_Call {
-	return &ProxyScraper_Execute_Call{Call: _e.mock.On("Execute", ctx)}
+//   - skip map[string]bool
+func (_e *ProxyScraper_Expecter) Execute(ctx interface{}, skip interface{}) *ProxyScraper_Execute_Call {
+	return &ProxyScraper_Execute_Call{Call: _e.mock.On("Execute", ctx, skip)}
 }
 
-func (_c *ProxyScraper_Execute_Call) Run(run func(ctx context.Context)) *ProxyScraper_Execute_Call {
+func (_c *ProxyScraper_Execute_Call) Run(run func(ctx context.Context, skip map[string]bool)) *ProxyScraper_Execute_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(map[string]bool))
 	})
 	return _c
 }
@@ -78,7 +79,54 @@ func (_c *ProxyScraper_Execute_Call) Return(_a0 []scraper.ScrapedProxy, _a1 []er
 	return _c
 }
 
-func (_c *ProxyScraper_Execute_Call) RunAndReturn(run func(context.Context) ([]scraper.ScrapedProxy, []error)) *ProxyScraper_Execute_Call {
+func (_c *ProxyScraper_Execute_Call) RunAndReturn(run func(context.Context, map[string]bool) ([]scraper.ScrapedProxy, []error)) *ProxyScraper_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SourceNames provides a mock function with no fields
+func (_m *ProxyScraper) SourceNames() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SourceNames")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// ProxyScraper_SourceNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SourceNames'
+type ProxyScraper_SourceNames_Call struct {
+	*mock.Call
+}
+
+// SourceNames is a helper method to define mock.On call
+func (_e *ProxyScraper_Expecter) SourceNames() *ProxyScraper_SourceNames_Call {
+	return &ProxyScraper_SourceNames_Call{Call: _e.mock.On("SourceNames")}
+}
+
+func (_c *ProxyScraper_SourceNames_Call) Run(run func()) *ProxyScraper_SourceNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ProxyScraper_SourceNames_Call) Return(_a0 []string) *ProxyScraper_SourceNames_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ProxyScraper_SourceNames_Call) RunAndReturn(run func() []string) *ProxyScraper_SourceNames_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,67 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// PublishMetrics is an autogenerated mock type for the PublishMetrics type
+type PublishMetrics struct {
+	mock.Mock
+}
+
+type PublishMetrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PublishMetrics) EXPECT() *PublishMetrics_Expecter {
+	return &PublishMetrics_Expecter{mock: &_m.Mock}
+}
+
+// ObservePublish provides a mock function with given fields: source, published, failed
+func (_m *PublishMetrics) ObservePublish(source string, published int, failed int) {
+	_m.Called(source, published, failed)
+}
+
+// PublishMetrics_ObservePublish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObservePublish'
+type PublishMetrics_ObservePublish_Call struct {
+	*mock.Call
+}
+
+// ObservePublish is a helper method to define mock.On call
+//   - source string
+//   - published int
+//   - failed int
+func (_e *PublishMetrics_Expecter) ObservePublish(source interface{}, published interface{}, failed interface{}) *PublishMetrics_ObservePublish_Call {
+	return &PublishMetrics_ObservePublish_Call{Call: _e.mock.On("ObservePublish", source, published, failed)}
+}
+
+func (_c *PublishMetrics_ObservePublish_Call) Run(run func(source string, published int, failed int)) *PublishMetrics_ObservePublish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *PublishMetrics_ObservePublish_Call) Return() *PublishMetrics_ObservePublish_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *PublishMetrics_ObservePublish_Call) RunAndReturn(run func(string, int, int)) *PublishMetrics_ObservePublish_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewPublishMetrics creates a new instance of PublishMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPublishMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PublishMetrics {
+	mock := &PublishMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	scraper "github.com/JulianoL13/app-proxy-engine/internal/scraper"
+)
+
+// SourceHealthStore is an autogenerated mock type for the SourceHealthStore type
+type SourceHealthStore struct {
+	mock.Mock
+}
+
+type SourceHealthStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SourceHealthStore) EXPECT() *SourceHealthStore_Expecter {
+	return &SourceHealthStore_Expecter{mock: &_m.Mock}
+}
+
+// GetSourceHealth provides a mock function with given fields: ctx, sources
+func (_m *SourceHealthStore) GetSourceHealth(ctx context.Context, sources []string) (map[string]scraper.SourceHealth, error) {
+	ret := _m.Called(ctx, sources)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSourceHealth")
+	}
+
+	var r0 map[string]scraper.SourceHealth
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]scraper.SourceHealth, error)); ok {
+		return rf(ctx, sources)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]scraper.SourceHealth); ok {
+		r0 = rf(ctx, sources)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]scraper.SourceHealth)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, sources)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SourceHealthStore_GetSourceHealth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSourceHealth'
+type SourceHealthStore_GetSourceHealth_Call struct {
+	*mock.Call
+}
+
+// GetSourceHealth is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sources []string
+func (_e *SourceHealthStore_Expecter) GetSourceHealth(ctx interface{}, sources interface{}) *SourceHealthStore_GetSourceHealth_Call {
+	return &SourceHealthStore_GetSourceHealth_Call{Call: _e.mock.On("GetSourceHealth", ctx, sources)}
+}
+
+func (_c *SourceHealthStore_GetSourceHealth_Call) Run(run func(ctx context.Context, sources []string)) *SourceHealthStore_GetSourceHealth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *SourceHealthStore_GetSourceHealth_Call) Return(_a0 map[string]scraper.SourceHealth, _a1 error) *SourceHealthStore_GetSourceHealth_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SourceHealthStore_GetSourceHealth_Call) RunAndReturn(run func(context.Context, []string) (map[string]scraper.SourceHealth, error)) *SourceHealthStore_GetSourceHealth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveSourceHealth provides a mock function with given fields: ctx, health
+func (_m *SourceHealthStore) SaveSourceHealth(ctx context.Context, health scraper.SourceHealth) error {
+	ret := _m.Called(ctx, health)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSourceHealth")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, scraper.SourceHealth) error); ok {
+		r0 = rf(ctx, health)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SourceHealthStore_SaveSourceHealth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSourceHealth'
+type SourceHealthStore_SaveSourceHealth_Call struct {
+	*mock.Call
+}
+
+// SaveSourceHealth is a helper method to define mock.On call
+//   - ctx context.Context
+//   - health scraper.SourceHealth
+func (_e *SourceHealthStore_Expecter) SaveSourceHealth(ctx interface{}, health interface{}) *SourceHealthStore_SaveSourceHealth_Call {
+	return &SourceHealthStore_SaveSourceHealth_Call{Call: _e.mock.On("SaveSourceHealth", ctx, health)}
+}
+
+func (_c *SourceHealthStore_SaveSourceHealth_Call) Run(run func(ctx context.Context, health scraper.SourceHealth)) *SourceHealthStore_SaveSourceHealth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(scraper.SourceHealth))
+	})
+	return _c
+}
+
+func (_c *SourceHealthStore_SaveSourceHealth_Call) Return(_a0 error) *SourceHealthStore_SaveSourceHealth_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SourceHealthStore_SaveSourceHealth_Call) RunAndReturn(run func(context.Context, scraper.SourceHealth) error) *SourceHealthStore_SaveSourceHealth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSourceHealthStore creates a new instance of SourceHealthStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSourceHealthStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SourceHealthStore {
+	mock := &SourceHealthStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SourceStatsRecorder is an autogenerated mock type for the SourceStatsRecorder type
+type SourceStatsRecorder struct {
+	mock.Mock
+}
+
+type SourceStatsRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SourceStatsRecorder) EXPECT() *SourceStatsRecorder_Expecter {
+	return &SourceStatsRecorder_Expecter{mock: &_m.Mock}
+}
+
+// RecordScrape provides a mock function with given fields: ctx, source, scraped, published
+func (_m *SourceStatsRecorder) RecordScrape(ctx context.Context, source string, scraped int, published int) error {
+	ret := _m.Called(ctx, source, scraped, published)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordScrape")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) error); ok {
+		r0 = rf(ctx, source, scraped, published)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SourceStatsRecorder_RecordScrape_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordScrape'
+type SourceStatsRecorder_RecordScrape_Call struct {
+	*mock.Call
+}
+
+// RecordScrape is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - scraped int
+//   - published int
+func (_e *SourceStatsRecorder_Expecter) RecordScrape(ctx interface{}, source interface{}, scraped interface{}, published interface{}) *SourceStatsRecorder_RecordScrape_Call {
+	return &SourceStatsRecorder_RecordScrape_Call{Call: _e.mock.On("RecordScrape", ctx, source, scraped, published)}
+}
+
+func (_c *SourceStatsRecorder_RecordScrape_Call) Run(run func(ctx context.Context, source string, scraped int, published int)) *SourceStatsRecorder_RecordScrape_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *SourceStatsRecorder_RecordScrape_Call) Return(_a0 error) *SourceStatsRecorder_RecordScrape_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SourceStatsRecorder_RecordScrape_Call) RunAndReturn(run func(context.Context, string, int, int) error) *SourceStatsRecorder_RecordScrape_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSourceStatsRecorder creates a new instance of SourceStatsRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSourceStatsRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SourceStatsRecorder {
+	mock := &SourceStatsRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
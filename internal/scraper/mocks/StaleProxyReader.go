@@ -0,0 +1,100 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	scraper "github.com/JulianoL13/app-proxy-engine/internal/scraper"
+
+	time "time"
+)
+
+// StaleProxyReader is an autogenerated mock type for the StaleProxyReader type
+type StaleProxyReader struct {
+	mock.Mock
+}
+
+type StaleProxyReader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StaleProxyReader) EXPECT() *StaleProxyReader_Expecter {
+	return &StaleProxyReader_Expecter{mock: &_m.Mock}
+}
+
+// GetStale provides a mock function with given fields: ctx, checkedBefore, limit
+func (_m *StaleProxyReader) GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]scraper.ScrapedProxy, error) {
+	ret := _m.Called(ctx, checkedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStale")
+	}
+
+	var r0 []scraper.ScrapedProxy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]scraper.ScrapedProxy, error)); ok {
+		return rf(ctx, checkedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []scraper.ScrapedProxy); ok {
+		r0 = rf(ctx, checkedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]scraper.ScrapedProxy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, checkedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StaleProxyReader_GetStale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStale'
+type StaleProxyReader_GetStale_Call struct {
+	*mock.Call
+}
+
+// GetStale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - checkedBefore time.Time
+//   - limit int
+func (_e *StaleProxyReader_Expecter) GetStale(ctx interface{}, checkedBefore interface{}, limit interface{}) *StaleProxyReader_GetStale_Call {
+	return &StaleProxyReader_GetStale_Call{Call: _e.mock.On("GetStale", ctx, checkedBefore, limit)}
+}
+
+func (_c *StaleProxyReader_GetStale_Call) Run(run func(ctx context.Context, checkedBefore time.Time, limit int)) *StaleProxyReader_GetStale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *StaleProxyReader_GetStale_Call) Return(_a0 []scraper.ScrapedProxy, _a1 error) *StaleProxyReader_GetStale_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StaleProxyReader_GetStale_Call) RunAndReturn(run func(context.Context, time.Time, int) ([]scraper.ScrapedProxy, error)) *StaleProxyReader_GetStale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStaleProxyReader creates a new instance of StaleProxyReader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStaleProxyReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StaleProxyReader {
+	mock := &StaleProxyReader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,99 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type SourceHealth struct {
+	Name             string
+	Verified         int64
+	LastVerified     int64
+	Strikes          int
+	Quarantines      int
+	QuarantinedUntil time.Time
+	LastCheckedAt    time.Time
+	Forced           bool
+}
+
+func (h SourceHealth) IsQuarantined(now time.Time) bool {
+	return !h.Forced && now.Before(h.QuarantinedUntil)
+}
+
+type SourceHealthStore interface {
+	GetSourceHealth(ctx context.Context, sources []string) (map[string]SourceHealth, error)
+	SaveSourceHealth(ctx context.Context, health SourceHealth) error
+}
+
+type QuarantinePolicy struct {
+	Strikes     int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p QuarantinePolicy) backoff(quarantines int) time.Duration {
+	d := p.BaseBackoff
+	for i := 1; i < quarantines && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+type healthChange int
+
+const (
+	healthUnchanged healthChange = iota
+	healthQuarantined
+	healthRecovered
+)
+
+func (p QuarantinePolicy) evaluate(h SourceHealth, fetchFailed bool, now time.Time) (SourceHealth, healthChange) {
+	// yield is judged one cycle late: proxies published now are verified before the next cycle
+	judgeYield := !h.LastCheckedAt.IsZero() && h.QuarantinedUntil.IsZero()
+	bad := fetchFailed || (judgeYield && h.Verified <= h.LastVerified)
+
+	h.LastVerified = h.Verified
+	h.LastCheckedAt = now
+	h.QuarantinedUntil = time.Time{}
+
+	if !bad {
+		h.Strikes = 0
+		if judgeYield && h.Quarantines > 0 {
+			h.Quarantines = 0
+			return h, healthRecovered
+		}
+		return h, healthUnchanged
+	}
+
+	h.Strikes++
+	if h.Forced {
+		return h, healthUnchanged
+	}
+
+	// a source on probation after a quarantine goes straight back in
+	if h.Strikes >= p.Strikes || h.Quarantines > 0 {
+		h.Quarantines++
+		h.Strikes = 0
+		h.QuarantinedUntil = now.Add(p.backoff(h.Quarantines))
+		return h, healthQuarantined
+	}
+
+	return h, healthUnchanged
+}
+
+// failedSources only counts sources that could not be reached; a list that
+// parses badly or is misconfigured is a problem on our side, not a strike.
+func failedSources(errs []error) map[string]bool {
+	failed := make(map[string]bool)
+	for _, err := range errs {
+		var se *SourceError
+		if errors.As(err, &se) && errors.Is(err, ErrSourceUnavailable) && !errors.Is(err, context.Canceled) {
+			failed[se.Source] = true
+		}
+	}
+	return failed
+}
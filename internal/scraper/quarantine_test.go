@@ -0,0 +1,196 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper/mocks"
+)
+
+var testPolicy = scraper.QuarantinePolicy{
+	Strikes:     3,
+	BaseBackoff: 30 * time.Minute,
+	MaxBackoff:  2 * time.Hour,
+}
+
+func runQuarantineCycle(t *testing.T, sources []string, health map[string]scraper.SourceHealth, errs []error, expectedSkip map[string]bool) map[string]scraper.SourceHealth {
+	t.Helper()
+
+	scraperMock := mocks.NewProxyScraper(t)
+	scraperMock.EXPECT().SourceNames().Return(sources)
+	scraperMock.EXPECT().
+		Execute(mock.Anything, expectedSkip).
+		Return(nil, errs)
+
+	saved := make(map[string]scraper.SourceHealth)
+	store := mocks.NewSourceHealthStore(t)
+	store.EXPECT().
+		GetSourceHealth(mock.Anything, sources).
+		Return(health, nil)
+	store.EXPECT().
+		SaveSourceHealth(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, h scraper.SourceHealth) error {
+			saved[h.Name] = h
+			return nil
+		}).Maybe()
+
+	uc := scraper.NewScheduleScrapingUseCase(scraperMock, mocks.NewProxySerializer(t), mocks.NewPublisher(t), nil, time.Hour, schedulerTestLogger{}, "test-topic").
+		WithQuarantine(store, testPolicy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_ = uc.Execute(ctx)
+
+	return saved
+}
+
+func fetchError(source string) error {
+	return &scraper.SourceError{Source: source, Err: scraper.ErrSourceUnavailable}
+}
+
+func TestScheduleScrapingUseCase_Quarantine(t *testing.T) {
+	lastCycle := time.Now().Add(-time.Hour)
+
+	t.Run("skips quarantined sources", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"bad":  {Name: "bad", Quarantines: 1, QuarantinedUntil: time.Now().Add(time.Hour)},
+			"good": {Name: "good"},
+		}
+
+		saved := runQuarantineCycle(t, []string{"bad", "good"}, health, nil, map[string]bool{"bad": true})
+
+		assert.Contains(t, saved, "good")
+		assert.NotContains(t, saved, "bad")
+	})
+
+	t.Run("does not skip forced sources", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"forced": {Name: "forced", Forced: true, Strikes: 2, LastCheckedAt: lastCycle, QuarantinedUntil: time.Now().Add(time.Hour)},
+		}
+
+		saved := runQuarantineCycle(t, []string{"forced"}, health, []error{fetchError("forced")}, map[string]bool{})
+
+		assert.Equal(t, 3, saved["forced"].Strikes)
+		assert.Zero(t, saved["forced"].Quarantines)
+	})
+
+	t.Run("counts strike on fetch failure", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{"a": {Name: "a"}}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, []error{fetchError("a")}, map[string]bool{})
+
+		assert.Equal(t, 1, saved["a"].Strikes)
+		assert.True(t, saved["a"].QuarantinedUntil.IsZero())
+	})
+
+	t.Run("does not count strike on parse failure", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{"a": {Name: "a"}}
+		parseErr := &scraper.SourceError{Source: "a", Err: scraper.ErrInvalidProxy}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, []error{parseErr}, map[string]bool{})
+
+		assert.Zero(t, saved["a"].Strikes)
+	})
+
+	t.Run("does not judge yield on first cycle", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{"a": {Name: "a"}}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, nil, map[string]bool{})
+
+		assert.Zero(t, saved["a"].Strikes)
+		assert.False(t, saved["a"].LastCheckedAt.IsZero())
+	})
+
+	t.Run("counts strike when nothing was verified since last cycle", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Verified: 10, LastVerified: 10, LastCheckedAt: lastCycle},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, nil, map[string]bool{})
+
+		assert.Equal(t, 1, saved["a"].Strikes)
+	})
+
+	t.Run("resets strikes when source yields", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Verified: 12, LastVerified: 10, Strikes: 2, LastCheckedAt: lastCycle},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, nil, map[string]bool{})
+
+		assert.Zero(t, saved["a"].Strikes)
+		assert.Equal(t, int64(12), saved["a"].LastVerified)
+	})
+
+	t.Run("quarantines after reaching strikes", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Strikes: 2, LastCheckedAt: lastCycle},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, []error{fetchError("a")}, map[string]bool{})
+
+		assert.Equal(t, 1, saved["a"].Quarantines)
+		assert.Zero(t, saved["a"].Strikes)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), saved["a"].QuarantinedUntil, 5*time.Second)
+	})
+
+	t.Run("grows interval when source fails on probation", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Quarantines: 2, LastCheckedAt: lastCycle, QuarantinedUntil: time.Now().Add(-time.Minute)},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, []error{fetchError("a")}, map[string]bool{})
+
+		assert.Equal(t, 3, saved["a"].Quarantines)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), saved["a"].QuarantinedUntil, 5*time.Second)
+	})
+
+	t.Run("judges yield of the retry on the following cycle", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Quarantines: 1, Verified: 5, LastVerified: 5, LastCheckedAt: lastCycle, QuarantinedUntil: time.Now().Add(-time.Minute)},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, nil, map[string]bool{})
+
+		assert.Equal(t, 1, saved["a"].Quarantines)
+		assert.True(t, saved["a"].QuarantinedUntil.IsZero())
+	})
+
+	t.Run("recovers when source yields after quarantine", func(t *testing.T) {
+		health := map[string]scraper.SourceHealth{
+			"a": {Name: "a", Quarantines: 1, Verified: 8, LastVerified: 5, LastCheckedAt: lastCycle},
+		}
+
+		saved := runQuarantineCycle(t, []string{"a"}, health, nil, map[string]bool{})
+
+		assert.Zero(t, saved["a"].Quarantines)
+	})
+
+	t.Run("scrapes all sources when health is unavailable", func(t *testing.T) {
+		scraperMock := mocks.NewProxyScraper(t)
+		scraperMock.EXPECT().SourceNames().Return([]string{"a"})
+		scraperMock.EXPECT().
+			Execute(mock.Anything, map[string]bool(nil)).
+			Return(nil, nil)
+
+		store := mocks.NewSourceHealthStore(t)
+		store.EXPECT().
+			GetSourceHealth(mock.Anything, []string{"a"}).
+			Return(nil, errors.New("redis down"))
+
+		uc := scraper.NewScheduleScrapingUseCase(scraperMock, mocks.NewProxySerializer(t), mocks.NewPublisher(t), nil, time.Hour, schedulerTestLogger{}, "test-topic").
+			WithQuarantine(store, testPolicy)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+	})
+}
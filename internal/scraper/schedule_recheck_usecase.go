@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type StaleProxyReader interface {
+	GetStale(ctx context.Context, checkedBefore time.Time, limit int) ([]ScrapedProxy, error)
+}
+
+type ScheduleRecheckUseCase struct {
+	reader     StaleProxyReader
+	serializer ProxySerializer
+	publisher  Publisher
+	interval   time.Duration
+	maxAge     time.Duration
+	batchSize  int
+	topic      string
+	metrics    PublishMetrics
+	logger     SchedulerLogger
+}
+
+func NewScheduleRecheckUseCase(
+	reader StaleProxyReader,
+	serializer ProxySerializer,
+	publisher Publisher,
+	interval time.Duration,
+	maxAge time.Duration,
+	batchSize int,
+	logger SchedulerLogger,
+	topic string,
+) *ScheduleRecheckUseCase {
+	return &ScheduleRecheckUseCase{
+		reader:     reader,
+		serializer: serializer,
+		publisher:  publisher,
+		interval:   interval,
+		maxAge:     maxAge,
+		batchSize:  batchSize,
+		topic:      topic,
+		logger:     logger,
+	}
+}
+
+func (uc *ScheduleRecheckUseCase) WithMetrics(metrics PublishMetrics) *ScheduleRecheckUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+func (uc *ScheduleRecheckUseCase) Execute(ctx context.Context) error {
+	uc.logger.Info("starting recheck scheduler", "interval", uc.interval, "max_age", uc.maxAge, "topic", uc.topic)
+
+	uc.runCycle(ctx)
+
+	ticker := time.NewTicker(uc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			uc.logger.Info("recheck scheduler stopped")
+			return ctx.Err()
+		case <-ticker.C:
+			uc.runCycle(ctx)
+		}
+	}
+}
+
+func (uc *ScheduleRecheckUseCase) runCycle(ctx context.Context) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "scraper.recheck")
+	defer span.End()
+
+	stale, err := uc.reader.GetStale(ctx, time.Now().Add(-uc.maxAge), uc.batchSize)
+	if err != nil {
+		uc.logger.Warn("failed to load stale proxies", "error", err)
+		return
+	}
+
+	if len(stale) == 0 {
+		return
+	}
+
+	requeued := 0
+	tallies := make(map[string]*sourceTally)
+	for _, p := range stale {
+		tally, ok := tallies[p.Source()]
+		if !ok {
+			tally = &sourceTally{}
+			tallies[p.Source()] = tally
+		}
+		tally.scraped++
+
+		data, err := uc.serializer.Serialize(p)
+		if err != nil {
+			uc.logger.Warn("failed to serialize proxy", "error", err)
+			continue
+		}
+
+		if err := uc.publisher.Publish(ctx, uc.topic, data); err != nil {
+			uc.logger.Warn("failed to publish proxy", "error", err)
+			continue
+		}
+		requeued++
+		tally.published++
+	}
+
+	if uc.metrics != nil {
+		for source, tally := range tallies {
+			uc.metrics.ObservePublish(source, tally.published, tally.scraped-tally.published)
+		}
+	}
+
+	uc.logger.Info("recheck cycle complete", "stale", len(stale), "requeued", requeued)
+	span.SetAttributes(
+		attribute.Int("scraper.stale", len(stale)),
+		attribute.Int("scraper.requeued", requeued),
+	)
+}
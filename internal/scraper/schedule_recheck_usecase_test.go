@@ -0,0 +1,114 @@
+package scraper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper/mocks"
+)
+
+func TestScheduleRecheckUseCase_runCycle(t *testing.T) {
+	logger := schedulerTestLogger{}
+
+	t.Run("republishes stale proxies in order", func(t *testing.T) {
+		oldest := scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "test")
+		newer := scraper.NewScrapeOutput("2.2.2.2", 3128, "socks5", "test")
+
+		reader := mocks.NewStaleProxyReader(t)
+		reader.EXPECT().
+			GetStale(mock.Anything, mock.AnythingOfType("time.Time"), 100).
+			Return([]scraper.ScrapedProxy{oldest, newer}, nil)
+
+		var published []string
+		serializer := mocks.NewProxySerializer(t)
+		serializer.EXPECT().
+			Serialize(mock.Anything).
+			RunAndReturn(func(p scraper.ScrapedProxy) ([]byte, error) {
+				return []byte(p.IP()), nil
+			}).Times(2)
+
+		publisher := mocks.NewPublisher(t)
+		publisher.EXPECT().
+			Publish(mock.Anything, "test-topic", mock.Anything).
+			RunAndReturn(func(_ context.Context, _ string, data []byte) error {
+				published = append(published, string(data))
+				return nil
+			}).Times(2)
+
+		uc := scraper.NewScheduleRecheckUseCase(reader, serializer, publisher, time.Hour, 15*time.Minute, 100, logger, "test-topic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+
+		assert.Equal(t, []string{"1.1.1.1", "2.2.2.2"}, published)
+	})
+
+	t.Run("uses max age as threshold", func(t *testing.T) {
+		reader := mocks.NewStaleProxyReader(t)
+		reader.EXPECT().
+			GetStale(mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				age := time.Since(before)
+				return age >= 15*time.Minute && age < 16*time.Minute
+			}), 100).
+			Return(nil, nil)
+
+		uc := scraper.NewScheduleRecheckUseCase(reader, mocks.NewProxySerializer(t), mocks.NewPublisher(t), time.Hour, 15*time.Minute, 100, logger, "test-topic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+	})
+
+	t.Run("handles reader errors gracefully", func(t *testing.T) {
+		reader := mocks.NewStaleProxyReader(t)
+		reader.EXPECT().
+			GetStale(mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("redis down"))
+
+		uc := scraper.NewScheduleRecheckUseCase(reader, mocks.NewProxySerializer(t), mocks.NewPublisher(t), time.Hour, 15*time.Minute, 100, logger, "test-topic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+	})
+
+	t.Run("continues when publish fails", func(t *testing.T) {
+		reader := mocks.NewStaleProxyReader(t)
+		reader.EXPECT().
+			GetStale(mock.Anything, mock.Anything, mock.Anything).
+			Return([]scraper.ScrapedProxy{
+				scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "test"),
+				scraper.NewScrapeOutput("2.2.2.2", 8080, "http", "test"),
+			}, nil)
+
+		serializer := mocks.NewProxySerializer(t)
+		serializer.EXPECT().
+			Serialize(mock.Anything).
+			Return([]byte("serialized"), nil).Times(2)
+
+		publisher := mocks.NewPublisher(t)
+		publisher.EXPECT().
+			Publish(mock.Anything, "test-topic", mock.Anything).
+			Return(errors.New("publish failed")).Once()
+		publisher.EXPECT().
+			Publish(mock.Anything, "test-topic", mock.Anything).
+			Return(nil).Once()
+
+		uc := scraper.NewScheduleRecheckUseCase(reader, serializer, publisher, time.Hour, 15*time.Minute, 100, logger, "test-topic")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+	})
+}
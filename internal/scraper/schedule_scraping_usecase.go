@@ -3,8 +3,13 @@ package scraper
 import (
 	"context"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+const tracerName = "github.com/JulianoL13/app-proxy-engine/internal/scraper"
+
 type SchedulerLogger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
@@ -24,7 +29,8 @@ type ScrapedProxy interface {
 }
 
 type ProxyScraper interface {
-	Execute(ctx context.Context) ([]ScrapedProxy, []error)
+	SourceNames() []string
+	Execute(ctx context.Context, skip map[string]bool) ([]ScrapedProxy, []error)
 }
 
 type ProxySerializer interface {
@@ -35,11 +41,28 @@ type Cleaner interface {
 	Cleanup(ctx context.Context) error
 }
 
+type SourceStatsRecorder interface {
+	RecordScrape(ctx context.Context, source string, scraped, published int) error
+}
+
+type PublishMetrics interface {
+	ObservePublish(source string, published, failed int)
+}
+
+type sourceTally struct {
+	scraped   int
+	published int
+}
+
 type ScheduleScrapingUseCase struct {
 	scraper    ProxyScraper
 	serializer ProxySerializer
 	publisher  Publisher
 	cleaner    Cleaner
+	stats      SourceStatsRecorder
+	metrics    PublishMetrics
+	health     SourceHealthStore
+	policy     QuarantinePolicy
 	interval   time.Duration
 	topic      string
 	logger     SchedulerLogger
@@ -65,6 +88,22 @@ func NewScheduleScrapingUseCase(
 	}
 }
 
+func (uc *ScheduleScrapingUseCase) WithStats(stats SourceStatsRecorder) *ScheduleScrapingUseCase {
+	uc.stats = stats
+	return uc
+}
+
+func (uc *ScheduleScrapingUseCase) WithMetrics(metrics PublishMetrics) *ScheduleScrapingUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+func (uc *ScheduleScrapingUseCase) WithQuarantine(store SourceHealthStore, policy QuarantinePolicy) *ScheduleScrapingUseCase {
+	uc.health = store
+	uc.policy = policy
+	return uc
+}
+
 func (uc *ScheduleScrapingUseCase) Execute(ctx context.Context) error {
 	uc.logger.Info("starting scheduler", "interval", uc.interval, "topic", uc.topic)
 
@@ -85,15 +124,28 @@ func (uc *ScheduleScrapingUseCase) Execute(ctx context.Context) error {
 }
 
 func (uc *ScheduleScrapingUseCase) runCycle(ctx context.Context) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "scraper.cycle")
+	defer span.End()
+
 	uc.logger.Info("starting scrape cycle")
 
-	proxies, errs := uc.scraper.Execute(ctx)
+	names, health, skip := uc.loadHealth(ctx)
+
+	proxies, errs := uc.scraper.Execute(ctx, skip)
 	if len(errs) > 0 {
 		uc.logger.Warn("scrape errors", "count", len(errs))
 	}
 
 	published := 0
+	tallies := make(map[string]*sourceTally)
 	for _, scraped := range proxies {
+		tally, ok := tallies[scraped.Source()]
+		if !ok {
+			tally = &sourceTally{}
+			tallies[scraped.Source()] = tally
+		}
+		tally.scraped++
+
 		data, err := uc.serializer.Serialize(scraped)
 		if err != nil {
 			uc.logger.Warn("failed to serialize proxy", "error", err)
@@ -105,9 +157,19 @@ func (uc *ScheduleScrapingUseCase) runCycle(ctx context.Context) {
 			continue
 		}
 		published++
+		tally.published++
 	}
 
 	uc.logger.Info("scrape cycle complete", "scraped", len(proxies), "published", published)
+	span.SetAttributes(
+		attribute.Int("scraper.scraped", len(proxies)),
+		attribute.Int("scraper.published", published),
+		attribute.Int("scraper.errors", len(errs)),
+	)
+
+	uc.recordStats(ctx, tallies)
+	uc.recordMetrics(tallies)
+	uc.updateHealth(ctx, names, health, skip, failedSources(errs))
 
 	if uc.cleaner != nil {
 		if err := uc.cleaner.Cleanup(ctx); err != nil {
@@ -117,3 +179,77 @@ func (uc *ScheduleScrapingUseCase) runCycle(ctx context.Context) {
 		}
 	}
 }
+
+func (uc *ScheduleScrapingUseCase) recordStats(ctx context.Context, tallies map[string]*sourceTally) {
+	if uc.stats == nil {
+		return
+	}
+
+	for source, tally := range tallies {
+		if source == "" {
+			continue
+		}
+		if err := uc.stats.RecordScrape(ctx, source, tally.scraped, tally.published); err != nil {
+			uc.logger.Warn("failed to record source stats", "source", source, "error", err)
+		}
+	}
+}
+
+func (uc *ScheduleScrapingUseCase) recordMetrics(tallies map[string]*sourceTally) {
+	if uc.metrics == nil {
+		return
+	}
+
+	for source, tally := range tallies {
+		uc.metrics.ObservePublish(source, tally.published, tally.scraped-tally.published)
+	}
+}
+
+func (uc *ScheduleScrapingUseCase) loadHealth(ctx context.Context) ([]string, map[string]SourceHealth, map[string]bool) {
+	if uc.health == nil {
+		return nil, nil, nil
+	}
+
+	names := uc.scraper.SourceNames()
+	health, err := uc.health.GetSourceHealth(ctx, names)
+	if err != nil {
+		uc.logger.Warn("failed to load source health, scraping all sources", "error", err)
+		return nil, nil, nil
+	}
+
+	now := time.Now()
+	skip := make(map[string]bool)
+	for _, name := range names {
+		h := health[name]
+		if h.IsQuarantined(now) {
+			skip[name] = true
+			uc.logger.Info("skipping quarantined source", "source", name, "until", h.QuarantinedUntil, "quarantines", h.Quarantines)
+		}
+	}
+
+	return names, health, skip
+}
+
+func (uc *ScheduleScrapingUseCase) updateHealth(ctx context.Context, names []string, health map[string]SourceHealth, skip, failed map[string]bool) {
+	now := time.Now()
+	for _, name := range names {
+		if skip[name] {
+			continue
+		}
+
+		h := health[name]
+		h.Name = name
+
+		updated, change := uc.policy.evaluate(h, failed[name], now)
+		switch change {
+		case healthQuarantined:
+			uc.logger.Warn("source quarantined", "source", name, "until", updated.QuarantinedUntil, "quarantines", updated.Quarantines, "fetch_failed", failed[name])
+		case healthRecovered:
+			uc.logger.Info("source recovered from quarantine", "source", name)
+		}
+
+		if err := uc.health.SaveSourceHealth(ctx, updated); err != nil {
+			uc.logger.Warn("failed to save source health", "source", name, "error", err)
+		}
+	}
+}
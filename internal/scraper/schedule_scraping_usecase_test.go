@@ -40,7 +40,7 @@ func TestScheduleScrapingUseCase_runCycle(t *testing.T) {
 
 		scraperMock := mocks.NewProxyScraper(t)
 		scraperMock.EXPECT().
-			Execute(mock.Anything).
+			Execute(mock.Anything, mock.Anything).
 			Return([]scraper.ScrapedProxy{proxy1, proxy2}, []error{})
 
 		publisher := mocks.NewPublisher(t)
@@ -69,7 +69,7 @@ func TestScheduleScrapingUseCase_runCycle(t *testing.T) {
 	t.Run("handles scraper errors gracefully", func(t *testing.T) {
 		scraperMock := mocks.NewProxyScraper(t)
 		scraperMock.EXPECT().
-			Execute(mock.Anything).
+			Execute(mock.Anything, mock.Anything).
 			Return([]scraper.ScrapedProxy{}, []error{errors.New("fetch failed")})
 
 		publisher := mocks.NewPublisher(t)
@@ -101,7 +101,7 @@ func TestScheduleScrapingUseCase_runCycle(t *testing.T) {
 
 		scraperMock := mocks.NewProxyScraper(t)
 		scraperMock.EXPECT().
-			Execute(mock.Anything).
+			Execute(mock.Anything, mock.Anything).
 			Return([]scraper.ScrapedProxy{proxy1}, []error{})
 
 		publisher := mocks.NewPublisher(t)
@@ -138,7 +138,7 @@ func TestScheduleScrapingUseCase_runCycle(t *testing.T) {
 
 		scraperMock := mocks.NewProxyScraper(t)
 		scraperMock.EXPECT().
-			Execute(mock.Anything).
+			Execute(mock.Anything, mock.Anything).
 			Return([]scraper.ScrapedProxy{proxy1}, []error{})
 
 		publisher := mocks.NewPublisher(t)
@@ -159,4 +159,47 @@ func TestScheduleScrapingUseCase_runCycle(t *testing.T) {
 
 		_ = uc.Execute(ctx)
 	})
+
+	t.Run("records stats and metrics per source", func(t *testing.T) {
+		scraperMock := mocks.NewProxyScraper(t)
+		scraperMock.EXPECT().
+			Execute(mock.Anything, mock.Anything).
+			Return([]scraper.ScrapedProxy{
+				scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "a"),
+				scraper.NewScrapeOutput("2.2.2.2", 8080, "http", "a"),
+				scraper.NewScrapeOutput("3.3.3.3", 8080, "http", "b"),
+			}, nil)
+
+		serializer := mocks.NewProxySerializer(t)
+		serializer.EXPECT().
+			Serialize(mock.Anything).
+			RunAndReturn(func(p scraper.ScrapedProxy) ([]byte, error) {
+				return []byte(p.IP()), nil
+			})
+
+		publisher := mocks.NewPublisher(t)
+		publisher.EXPECT().
+			Publish(mock.Anything, "test-topic", []byte("2.2.2.2")).
+			Return(errors.New("publish failed"))
+		publisher.EXPECT().
+			Publish(mock.Anything, "test-topic", mock.Anything).
+			Return(nil)
+
+		stats := mocks.NewSourceStatsRecorder(t)
+		stats.EXPECT().RecordScrape(mock.Anything, "a", 2, 1).Return(nil)
+		stats.EXPECT().RecordScrape(mock.Anything, "b", 1, 1).Return(errors.New("redis down"))
+
+		metrics := mocks.NewPublishMetrics(t)
+		metrics.EXPECT().ObservePublish("a", 1, 1).Return()
+		metrics.EXPECT().ObservePublish("b", 1, 0).Return()
+
+		uc := scraper.NewScheduleScrapingUseCase(scraperMock, serializer, publisher, nil, time.Hour, logger, "test-topic").
+			WithStats(stats).
+			WithMetrics(metrics)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = uc.Execute(ctx)
+	})
 }
@@ -17,9 +17,26 @@ type Fetcher interface {
 	FetchAndParse(ctx context.Context, source Source) ([]*ScrapeOutput, error)
 }
 
+type SourceProvider interface {
+	Sources() []Source
+}
+
+const (
+	FetchSuccess = "success"
+	FetchError   = "error"
+	FetchTimeout = "timeout"
+	FetchSkipped = "skipped"
+)
+
+type FetchMetrics interface {
+	ObserveFetch(source, result string, proxies int, duration time.Duration)
+}
+
 type ScrapeProxiesUseCase struct {
 	fetcher       Fetcher
 	sources       []Source
+	provider      SourceProvider
+	metrics       FetchMetrics
 	logger        Logger
 	sourceTimeout time.Duration
 }
@@ -33,27 +50,74 @@ func NewScrapeProxiesUseCase(f Fetcher, sources []Source, logger Logger, sourceT
 	}
 }
 
-func (uc *ScrapeProxiesUseCase) Execute(ctx context.Context) ([]*ScrapeOutput, []error) {
-	uc.logger.Info("starting proxy scrape", "sources", len(uc.sources))
+func (uc *ScrapeProxiesUseCase) WithSourceProvider(provider SourceProvider) *ScrapeProxiesUseCase {
+	uc.provider = provider
+	return uc
+}
+
+func (uc *ScrapeProxiesUseCase) WithMetrics(metrics FetchMetrics) *ScrapeProxiesUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+func (uc *ScrapeProxiesUseCase) currentSources() []Source {
+	if uc.provider != nil {
+		return uc.provider.Sources()
+	}
+	return uc.sources
+}
+
+func (uc *ScrapeProxiesUseCase) SourceNames() []string {
+	sources := uc.currentSources()
+	names := make([]string, len(sources))
+	for i, s := range sources {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func (uc *ScrapeProxiesUseCase) Execute(ctx context.Context, skip map[string]bool) ([]*ScrapeOutput, []error) {
+	var sources []Source
+	for _, s := range uc.currentSources() {
+		if skip[s.Name] {
+			uc.observeFetch(s.Name, FetchSkipped, 0, 0)
+			continue
+		}
+		sources = append(sources, s)
+	}
+	uc.logger.Info("starting proxy scrape", "sources", len(sources), "skipped", len(skip))
 
 	var wg sync.WaitGroup
-	results := make(chan []*ScrapeOutput, len(uc.sources))
-	errors := make(chan error, len(uc.sources))
+	results := make(chan []*ScrapeOutput, len(sources))
+	errors := make(chan error, len(sources))
 
-	for _, src := range uc.sources {
+	for _, src := range sources {
 		wg.Add(1)
 		go func(source Source) {
 			defer wg.Done()
 
-			timeoutCtx, cancel := context.WithTimeout(ctx, uc.sourceTimeout)
+			timeout := uc.sourceTimeout
+			if source.Timeout > 0 {
+				timeout = source.Timeout
+			}
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 			defer cancel()
 
+			start := time.Now()
 			proxies, err := uc.fetcher.FetchAndParse(timeoutCtx, source)
+			elapsed := time.Since(start)
 			if err != nil {
+				result := FetchError
+				if timeoutCtx.Err() == context.DeadlineExceeded {
+					result = FetchTimeout
+				}
+				uc.observeFetch(source.Name, result, 0, elapsed)
 				uc.logger.Warn("source fetch failed", "source", source.Name, "error", err)
-				errors <- err
+				errors <- &SourceError{Source: source.Name, Err: err}
 				return
 			}
+			uc.observeFetch(source.Name, FetchSuccess, len(proxies), elapsed)
 			uc.logger.Debug("source fetched", "source", source.Name, "count", len(proxies))
 			results <- proxies
 		}(src)
@@ -96,3 +160,9 @@ func (uc *ScrapeProxiesUseCase) Execute(ctx context.Context) ([]*ScrapeOutput, [
 	uc.logger.Info("scrape completed", "unique", len(uniqueProxies), "errors", len(errs))
 	return finalList, errs
 }
+
+func (uc *ScrapeProxiesUseCase) observeFetch(source, result string, proxies int, duration time.Duration) {
+	if uc.metrics != nil {
+		uc.metrics.ObserveFetch(source, result, proxies, duration)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
 	"github.com/JulianoL13/app-proxy-engine/internal/scraper/mocks"
@@ -43,7 +44,7 @@ func TestScrapeProxiesUseCase_Execute(t *testing.T) {
 		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		defer cancel()
 
-		result, errs := uc.Execute(timeoutCtx)
+		result, errs := uc.Execute(timeoutCtx, nil)
 
 		assert.Empty(t, errs)
 		assert.Len(t, result, 2, "Should have 2 unique proxies")
@@ -60,10 +61,103 @@ func TestScrapeProxiesUseCase_Execute(t *testing.T) {
 		mockFetcher.On("FetchAndParse", mock.Anything, sources[0]).Return(nil, errors.New("network error"))
 
 		uc := scraper.NewScrapeProxiesUseCase(mockFetcher, sources, logger, 45*time.Second)
-		result, errs := uc.Execute(ctx)
+		result, errs := uc.Execute(ctx, nil)
 
 		assert.Len(t, errs, 1)
 		assert.Empty(t, result)
 		mockFetcher.AssertExpectations(t)
 	})
+
+	t.Run("skips sources and tags fetch errors", func(t *testing.T) {
+		mockFetcher := mocks.NewFetcher(t)
+
+		sources := []scraper.Source{
+			{Name: "Skipped", URL: "http://skipped.com", Type: "http"},
+			{Name: "Broken", URL: "http://broken.com", Type: "http"},
+		}
+
+		mockFetcher.EXPECT().FetchAndParse(mock.Anything, sources[1]).Return(nil, scraper.ErrSourceUnavailable)
+
+		uc := scraper.NewScrapeProxiesUseCase(mockFetcher, sources, logger, 45*time.Second)
+		assert.Equal(t, []string{"Skipped", "Broken"}, uc.SourceNames())
+
+		_, errs := uc.Execute(ctx, map[string]bool{"Skipped": true})
+
+		require.Len(t, errs, 1)
+		var sourceErr *scraper.SourceError
+		require.ErrorAs(t, errs[0], &sourceErr)
+		assert.Equal(t, "Broken", sourceErr.Source)
+		assert.ErrorIs(t, errs[0], scraper.ErrSourceUnavailable)
+	})
+
+	t.Run("reads sources from provider", func(t *testing.T) {
+		mockFetcher := mocks.NewFetcher(t)
+
+		provided := scraper.Source{Name: "Provided", URL: "http://provided.com", Type: "http", Timeout: time.Second}
+		mockFetcher.EXPECT().
+			FetchAndParse(mock.MatchedBy(func(ctx context.Context) bool {
+				deadline, ok := ctx.Deadline()
+				return ok && time.Until(deadline) <= time.Second
+			}), provided).
+			Return([]*scraper.ScrapeOutput{scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "Provided")}, nil)
+
+		static := []scraper.Source{{Name: "Static", URL: "http://static.com", Type: "http"}}
+		uc := scraper.NewScrapeProxiesUseCase(mockFetcher, static, logger, 45*time.Second).
+			WithSourceProvider(staticProvider{provided})
+
+		result, errs := uc.Execute(ctx, nil)
+
+		assert.Empty(t, errs)
+		assert.Len(t, result, 1)
+	})
+
+	t.Run("observes fetch results per source", func(t *testing.T) {
+		mockFetcher := mocks.NewFetcher(t)
+
+		ok := scraper.Source{Name: "Ok", URL: "http://ok.com", Type: "http"}
+		broken := scraper.Source{Name: "Broken", URL: "http://broken.com", Type: "http"}
+		quarantined := scraper.Source{Name: "Quarantined", URL: "http://q.com", Type: "http"}
+
+		mockFetcher.EXPECT().FetchAndParse(mock.Anything, ok).
+			Return([]*scraper.ScrapeOutput{scraper.NewScrapeOutput("1.1.1.1", 8080, "http", "Ok")}, nil)
+		mockFetcher.EXPECT().FetchAndParse(mock.Anything, broken).
+			Return(nil, errors.New("network error"))
+
+		metrics := mocks.NewFetchMetrics(t)
+		metrics.EXPECT().ObserveFetch("Ok", scraper.FetchSuccess, 1, mock.Anything).Return()
+		metrics.EXPECT().ObserveFetch("Broken", scraper.FetchError, 0, mock.Anything).Return()
+		metrics.EXPECT().ObserveFetch("Quarantined", scraper.FetchSkipped, 0, time.Duration(0)).Return()
+
+		uc := scraper.NewScrapeProxiesUseCase(mockFetcher, []scraper.Source{ok, broken, quarantined}, logger, 45*time.Second).
+			WithMetrics(metrics)
+
+		_, errs := uc.Execute(ctx, map[string]bool{"Quarantined": true})
+
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("reports timeouts separately", func(t *testing.T) {
+		mockFetcher := mocks.NewFetcher(t)
+
+		slow := scraper.Source{Name: "Slow", URL: "http://slow.com", Type: "http", Timeout: 10 * time.Millisecond}
+		mockFetcher.EXPECT().FetchAndParse(mock.Anything, slow).
+			RunAndReturn(func(ctx context.Context, _ scraper.Source) ([]*scraper.ScrapeOutput, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+
+		metrics := mocks.NewFetchMetrics(t)
+		metrics.EXPECT().ObserveFetch("Slow", scraper.FetchTimeout, 0, mock.Anything).Return()
+
+		uc := scraper.NewScrapeProxiesUseCase(mockFetcher, []scraper.Source{slow}, logger, 45*time.Second).
+			WithMetrics(metrics)
+
+		_, errs := uc.Execute(ctx, nil)
+
+		assert.Len(t, errs, 1)
+	})
 }
+
+type staticProvider []scraper.Source
+
+func (p staticProvider) Sources() []scraper.Source { return p }
@@ -1,9 +1,31 @@
 package scraper
 
+import "time"
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+)
+
 type Source struct {
-	Name string
-	URL  string
-	Type string
+	Name    string
+	URL     string
+	Type    string
+	Format  string
+	Fields  FieldMapping
+	Timeout time.Duration
+	Headers map[string]string
+}
+
+type FieldMapping struct {
+	Path     string
+	IP       string
+	Port     string
+	Protocol string
+	Username string
+	Password string
 }
 
 func PublicSources() []Source {
@@ -2,9 +2,41 @@ package verifier
 
 import "errors"
 
+const (
+	OutcomeSuccess         = "success"
+	OutcomeProxyDead       = "proxy_dead"
+	OutcomeProxyTimeout    = "proxy_timeout"
+	OutcomePayloadModified = "payload_modified"
+	OutcomeInjection       = "injection_detected"
+	OutcomeNoJudge         = "judge_unavailable"
+	OutcomeError           = "error"
+)
+
 var (
 	ErrProxyDead         = errors.New("proxy dead")
 	ErrProxyTimeout      = errors.New("proxy timeout")
 	ErrPayloadModified   = errors.New("proxy modified payload")
 	ErrInjectionDetected = errors.New("injection detected in payload")
+	ErrNoJudge           = errors.New("no healthy judge available")
+	ErrQuorumNotMet      = errors.New("judge quorum not met")
+	ErrProxyBlocked      = errors.New("proxy is blocked")
 )
+
+func Outcome(out VerifyOutput) string {
+	switch {
+	case out.Success:
+		return OutcomeSuccess
+	case errors.Is(out.Error, ErrNoJudge):
+		return OutcomeNoJudge
+	case errors.Is(out.Error, ErrProxyTimeout):
+		return OutcomeProxyTimeout
+	case errors.Is(out.Error, ErrInjectionDetected):
+		return OutcomeInjection
+	case errors.Is(out.Error, ErrPayloadModified):
+		return OutcomePayloadModified
+	case errors.Is(out.Error, ErrProxyDead):
+		return OutcomeProxyDead
+	default:
+		return OutcomeError
+	}
+}
@@ -0,0 +1,187 @@
+package httpverifier
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+const (
+	DefaultPlainHTTPURL = "http://httpbin.org/get"
+	// DefaultTLSTarget is only probed when no judge is configured, alongside
+	// the httpbin DefaultVerifyURL.
+	DefaultTLSTarget = "httpbin.org:443"
+
+	// DefaultJudgeTLSPort matches the default JUDGE_TLS_PORT of cmd/judge.
+	DefaultJudgeTLSPort = "8443"
+)
+
+var errPinMismatch = errors.New("certificate does not match pin")
+
+// JudgeTLSTarget is the TLS listener of the judge serving judgeURL, the
+// preferred CONNECT probe target: it is under our control and, unlike a
+// public endpoint, never rate limits the probes. It returns "" when no judge
+// is configured.
+func JudgeTLSTarget(judgeURL, tlsPort string) string {
+	u, err := url.Parse(strings.TrimSpace(judgeURL))
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	if tlsPort == "" {
+		tlsPort = DefaultJudgeTLSPort
+	}
+	return net.JoinHostPort(u.Hostname(), tlsPort)
+}
+
+type CapabilityProbe struct {
+	plainURL  string
+	tlsTarget string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	logger    Logger
+}
+
+func NewCapabilityProbe(plainURL, tlsTarget string, timeout time.Duration, logger Logger) *CapabilityProbe {
+	if plainURL == "" {
+		plainURL = DefaultPlainHTTPURL
+	}
+	if tlsTarget == "" {
+		tlsTarget = DefaultTLSTarget
+	}
+	return &CapabilityProbe{
+		plainURL:  plainURL,
+		tlsTarget: tlsTarget,
+		timeout:   timeout,
+		logger:    logger,
+	}
+}
+
+func (c *CapabilityProbe) WithTLSConfig(cfg *tls.Config) *CapabilityProbe {
+	c.tlsConfig = cfg
+	return c
+}
+
+// WithPins trusts the TLS target by its pinned certificate or public key
+// instead of the system roots, for judges running on self-signed certificates.
+func (c *CapabilityProbe) WithPins(pins []judge.Pin) *CapabilityProbe {
+	c.tlsConfig = &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if !judge.MatchesPins(cs.PeerCertificates, pins) {
+				return errPinMismatch
+			}
+			return nil
+		},
+	}
+	return c
+}
+
+// CheckTarget completes a TLS handshake with the target directly, without a
+// proxy. Every CONNECT probe fails while it does not, so callers check it
+// once before relying on the probe.
+func (c *CapabilityProbe) CheckTarget(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: c.timeout}, Config: c.clientConfig()}
+	conn, err := dialer.DialContext(ctx, "tcp", c.tlsTarget)
+	if err != nil {
+		return fmt.Errorf("tls target %s: %w", c.tlsTarget, err)
+	}
+	return conn.Close()
+}
+
+func (c *CapabilityProbe) Probe(ctx context.Context, p verifier.Verifiable) verifier.Capabilities {
+	var (
+		caps verifier.Capabilities
+		wg   sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := c.probeConnect(ctx, p); err != nil {
+			c.logger.Debug("connect probe failed", "address", p.Address(), "error", err)
+			return
+		}
+		caps.Connect = true
+	}()
+	go func() {
+		defer wg.Done()
+		if err := c.probePlainHTTP(ctx, p); err != nil {
+			c.logger.Debug("plain http probe failed", "address", p.Address(), "error", err)
+			return
+		}
+		caps.PlainHTTP = true
+	}()
+	wg.Wait()
+
+	return caps
+}
+
+func (c *CapabilityProbe) probeConnect(ctx context.Context, p verifier.Verifiable) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := tunnel.Dial(ctx, p.URL(), c.tlsTarget, c.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tlsConn := tls.Client(conn, c.clientConfig())
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("tls handshake: %w", err)
+	}
+	return nil
+}
+
+func (c *CapabilityProbe) probePlainHTTP(ctx context.Context, p verifier.Verifiable) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	client := &http.Client{Transport: transportFor(p.URL(), c.timeout)}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.plainURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "ProxyEngine/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *CapabilityProbe) clientConfig() *tls.Config {
+	cfg := &tls.Config{}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(c.tlsTarget)
+		if err != nil {
+			host = c.tlsTarget
+		}
+		cfg.ServerName = host
+	}
+	return cfg
+}
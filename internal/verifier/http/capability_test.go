@@ -0,0 +1,180 @@
+package httpverifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+func newConnectProxy(t *testing.T, allowConnect bool) *httptest.Server {
+	t.Helper()
+
+	forward := newForwardProxy(t, "", nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			forward.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+
+		if !allowConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		hijackTunnel(w, r.Host)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func hijackTunnel(w http.ResponseWriter, addr string) {
+	upstream, err := net.Dial("tcp", addr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
+
+	go func() {
+		defer upstream.Close()
+		_, _ = io.Copy(upstream, buf)
+	}()
+	go func() {
+		defer conn.Close()
+		_, _ = io.Copy(conn, upstream)
+	}()
+}
+
+func TestCapabilityProbe_CheckTarget(t *testing.T) {
+	tlsTarget := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(tlsTarget.Close)
+
+	newProbe := func(target string) *CapabilityProbe {
+		return NewCapabilityProbe("", target, time.Second, &mockLogger{})
+	}
+
+	t.Run("accepts a pinned target", func(t *testing.T) {
+		probe := newProbe(tlsTarget.Listener.Addr().String()).
+			WithPins([]judge.Pin{judge.PublicKeyPin(tlsTarget.Certificate())})
+
+		assert.NoError(t, probe.CheckTarget(context.Background()))
+	})
+
+	t.Run("rejects an untrusted target", func(t *testing.T) {
+		assert.Error(t, newProbe(tlsTarget.Listener.Addr().String()).CheckTarget(context.Background()))
+	})
+
+	t.Run("rejects a target without a tls listener", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		assert.Error(t, newProbe(addr).CheckTarget(context.Background()))
+	})
+}
+
+func TestCapabilityProbe_Probe(t *testing.T) {
+	plain := newHTTPBin(t)
+
+	tlsTarget := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(tlsTarget.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tlsTarget.Certificate())
+	trusted := &tls.Config{RootCAs: roots}
+
+	newProbe := func(cfg *tls.Config) *CapabilityProbe {
+		return NewCapabilityProbe(plain.URL+"/get", tlsTarget.Listener.Addr().String(), 2*time.Second, &mockLogger{}).
+			WithTLSConfig(cfg)
+	}
+
+	t.Run("detects connect and plain http on a full proxy", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+
+		caps := newProbe(trusted).Probe(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.Equal(t, verifier.Capabilities{Connect: true, PlainHTTP: true}, caps)
+	})
+
+	t.Run("detects plain http only when connect is refused", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, false)
+
+		caps := newProbe(trusted).Probe(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.Equal(t, verifier.Capabilities{PlainHTTP: true}, caps)
+	})
+
+	t.Run("requires a completed tls handshake", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+
+		caps := newProbe(nil).Probe(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, caps.Connect)
+		assert.True(t, caps.PlainHTTP)
+	})
+
+	t.Run("trusts a pinned self-signed target", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+		probe := NewCapabilityProbe(plain.URL+"/get", tlsTarget.Listener.Addr().String(), 2*time.Second, &mockLogger{}).
+			WithPins([]judge.Pin{judge.PublicKeyPin(tlsTarget.Certificate())})
+
+		caps := probe.Probe(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.True(t, caps.Connect)
+	})
+
+	t.Run("rejects a target that does not match the pins", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+		probe := NewCapabilityProbe(plain.URL+"/get", tlsTarget.Listener.Addr().String(), 2*time.Second, &mockLogger{}).
+			WithPins([]judge.Pin{{}})
+
+		caps := probe.Probe(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, caps.Connect)
+	})
+
+	t.Run("tunnels through socks4", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, socks4Granted)
+
+		caps := newProbe(trusted).Probe(context.Background(), socks4Target{address: proxySrv.addr})
+
+		assert.Equal(t, verifier.Capabilities{Connect: true, PlainHTTP: true}, caps)
+	})
+
+	t.Run("reports nothing for a dead proxy", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		caps := newProbe(trusted).Probe(context.Background(), httpTarget{address: addr})
+
+		assert.Equal(t, verifier.Capabilities{}, caps)
+	})
+}
+
+func TestJudgeTLSTarget(t *testing.T) {
+	assert.Equal(t, "judge.example.com:8443", JudgeTLSTarget("http://judge.example.com:8090/echo", ""))
+	assert.Equal(t, "judge.example.com:9443", JudgeTLSTarget(" http://judge.example.com:8090/echo", "9443"))
+	assert.Equal(t, "", JudgeTLSTarget("", DefaultJudgeTLSPort))
+}
@@ -7,14 +7,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
 	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
 )
 
+const tracerName = "github.com/JulianoL13/app-proxy-engine/internal/verifier/http"
+
 type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
@@ -27,6 +38,10 @@ var expectedFields = map[string]bool{
 	"args": true, "headers": true, "origin": true, "url": true,
 }
 
+var judgeFields = map[string]bool{
+	"origin": true, "headers": true, "nonce": true, "issued_at": true, "signature": true,
+}
+
 const maxPayloadSize = 2048
 
 type Checker struct {
@@ -37,6 +52,7 @@ type Checker struct {
 	initOnce     sync.Once
 	baseline     []byte
 	baselineHash string
+	signer       *judge.Signer
 }
 
 func NewChecker(target string, timeout time.Duration, logger Logger) *Checker {
@@ -50,6 +66,11 @@ func NewChecker(target string, timeout time.Duration, logger Logger) *Checker {
 	}
 }
 
+func (c *Checker) WithJudge(signer *judge.Signer) *Checker {
+	c.signer = signer
+	return c
+}
+
 func (c *Checker) ensureRealIP() {
 	c.initOnce.Do(func() {
 		c.realIP = c.fetchRealIP()
@@ -134,8 +155,13 @@ func (c *Checker) checkIntegrity(body []byte) bool {
 		return false
 	}
 
+	fields := expectedFields
+	if c.signer != nil {
+		fields = judgeFields
+	}
+
 	for key := range data {
-		if !expectedFields[key] {
+		if !fields[key] {
 			c.logger.Warn("unexpected field in response", "field", key)
 			return false
 		}
@@ -164,18 +190,34 @@ func (c *Checker) checkIntegrity(body []byte) bool {
 }
 
 func (c *Checker) Verify(ctx context.Context, p verifier.Verifiable) verifier.VerifyOutput {
-	c.ensureRealIP()
-	c.ensureBaseline()
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "verifier.check",
+		trace.WithAttributes(
+			attribute.String("proxy.address", p.Address()),
+			attribute.String("proxy.protocol", p.URL().Scheme),
+			attribute.String("verifier.judge", c.TargetURL),
+		),
+	)
+	defer span.End()
+
+	result := c.verify(ctx, p)
+
+	span.SetAttributes(
+		attribute.String("verifier.outcome", verifier.Outcome(result)),
+		attribute.Int64("verifier.latency_ms", result.Latency.Milliseconds()),
+	)
+	if result.Error != nil {
+		span.SetStatus(codes.Error, result.Error.Error())
+	}
 
-	proxyURL := p.URL()
+	return result
+}
 
-	transport := &http.Transport{
-		Proxy:             http.ProxyURL(proxyURL),
-		DisableKeepAlives: true,
-	}
+func (c *Checker) verify(ctx context.Context, p verifier.Verifiable) verifier.VerifyOutput {
+	c.ensureRealIP()
+	c.ensureBaseline()
 
 	client := &http.Client{
-		Transport: transport,
+		Transport: transportFor(p.URL(), c.Timeout),
 		Timeout:   c.Timeout,
 	}
 
@@ -184,7 +226,12 @@ func (c *Checker) Verify(ctx context.Context, p verifier.Verifiable) verifier.Ve
 	reqCtx, cancel := context.WithTimeout(ctx, c.Timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(reqCtx, "GET", c.TargetURL, nil)
+	target, nonce, err := c.challengeURL()
+	if err != nil {
+		return verifier.VerifyOutput{Error: err}
+	}
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", target, nil)
 	if err != nil {
 		return verifier.VerifyOutput{Error: err}
 	}
@@ -197,7 +244,7 @@ func (c *Checker) Verify(ctx context.Context, p verifier.Verifiable) verifier.Ve
 	if err != nil {
 		c.logger.Debug("proxy verification failed", "address", p.Address(), "error", err)
 
-		wrappedErr := fmt.Errorf("proxy %s: %w", p.Address(), err)
+		wrappedErr := fmt.Errorf("proxy %s: %w: %w", p.Address(), verifier.ErrProxyDead, err)
 		if ctx.Err() == context.DeadlineExceeded || reqCtx.Err() == context.DeadlineExceeded {
 			wrappedErr = fmt.Errorf("proxy %s: %w", p.Address(), verifier.ErrProxyTimeout)
 		}
@@ -245,12 +292,105 @@ func (c *Checker) Verify(ctx context.Context, p verifier.Verifiable) verifier.Ve
 		}
 	}
 
+	if c.signer != nil {
+		if err := c.verifySignature(body, nonce); err != nil {
+			c.logger.Warn("proxy tampered with judge response", "address", p.Address(), "error", err)
+			return verifier.VerifyOutput{
+				Success: false,
+				Latency: latency,
+				Error:   fmt.Errorf("proxy %s: %w: %w", p.Address(), verifier.ErrPayloadModified, err),
+			}
+		}
+	}
+
 	anonymity := c.detectAnonymity(body)
 
 	return verifier.VerifyOutput{
-		Success:   true,
-		Latency:   latency,
-		Anonymity: anonymity,
+		Success:      true,
+		Latency:      latency,
+		Anonymity:    anonymity,
+		PassedJudges: []string{c.TargetURL},
+		ExitIP:       exitIP(body),
+	}
+}
+
+func (c *Checker) Healthy(ctx context.Context) error {
+	target, nonce, err := c.challengeURL()
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "GET", target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "ProxyEngine/1.0")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("judge %s: %w", c.TargetURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("judge %s: status %d", c.TargetURL, resp.StatusCode)
+	}
+
+	if c.signer == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPayloadSize))
+	if err != nil {
+		return fmt.Errorf("judge %s: %w", c.TargetURL, err)
+	}
+	if err := c.verifySignature(body, nonce); err != nil {
+		return fmt.Errorf("judge %s: %w", c.TargetURL, err)
+	}
+
+	return nil
+}
+
+func (c *Checker) challengeURL() (string, string, error) {
+	if c.signer == nil {
+		return c.TargetURL, "", nil
+	}
+
+	u, err := url.Parse(c.TargetURL)
+	if err != nil {
+		return "", "", fmt.Errorf("parse judge url: %w", err)
+	}
+
+	nonce := judge.NewNonce()
+	q := u.Query()
+	q.Set(judge.NonceParam, nonce)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nonce, nil
+}
+
+func (c *Checker) verifySignature(body []byte, nonce string) error {
+	var echo judge.Echo
+	if err := json.Unmarshal(body, &echo); err != nil {
+		return err
+	}
+	return c.signer.Verify(echo, nonce)
+}
+
+func transportFor(proxyURL *url.URL, timeout time.Duration) *http.Transport {
+	if proxyURL.Scheme == "socks4" {
+		return &http.Transport{
+			DialContext:       tunnel.NewSOCKS4Dialer(proxyURL.Host, proxyURL.User.Username(), timeout).DialContext,
+			DisableKeepAlives: true,
+		}
+	}
+
+	return &http.Transport{
+		Proxy:             http.ProxyURL(proxyURL),
+		DisableKeepAlives: true,
 	}
 }
 
@@ -259,6 +399,22 @@ type httpbinResponse struct {
 	Origin  string            `json:"origin"`
 }
 
+func exitIP(body []byte) string {
+	var resp httpbinResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return ""
+	}
+
+	// httpbin reports "client, proxy" when a forwarding header is present;
+	// the address the judge saw the request come from is always the last one.
+	origins := strings.Split(resp.Origin, ",")
+	ip := strings.TrimSpace(origins[len(origins)-1])
+	if net.ParseIP(ip) == nil {
+		return ""
+	}
+	return ip
+}
+
 func (c *Checker) detectAnonymity(body []byte) string {
 	var resp httpbinResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
@@ -102,3 +102,24 @@ func TestExpectedFields(t *testing.T) {
 func TestMaxPayloadSize(t *testing.T) {
 	assert.Equal(t, 2048, maxPayloadSize)
 }
+
+func TestExitIP(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "single origin", body: `{"origin":"5.6.7.8"}`, want: "5.6.7.8"},
+		{name: "forwarded origin keeps the last hop", body: `{"origin":"1.2.3.4, 5.6.7.8"}`, want: "5.6.7.8"},
+		{name: "ipv6 origin", body: `{"origin":"2001:db8::1"}`, want: "2001:db8::1"},
+		{name: "missing origin", body: `{"headers":{}}`, want: ""},
+		{name: "garbage origin", body: `{"origin":"unknown"}`, want: ""},
+		{name: "invalid json", body: `{`, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitIP([]byte(tt.body)))
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package httpverifier
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	judgehttp "github.com/JulianoL13/app-proxy-engine/internal/judge/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+type httpTarget struct {
+	address string
+}
+
+func (p httpTarget) Address() string { return p.address }
+func (p httpTarget) URL() *url.URL {
+	return &url.URL{Scheme: "http", Host: p.address}
+}
+
+func newForwardProxy(t *testing.T, via string, rewrite func([]byte) []byte) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := r.Clone(r.Context())
+		out.RequestURI = ""
+		if via != "" {
+			out.Header.Set("Via", via)
+		}
+
+		resp, err := http.DefaultTransport.RoundTrip(out)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		if rewrite != nil {
+			body = rewrite(body)
+		}
+
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChecker_Verify_Judge(t *testing.T) {
+	signer, err := judge.NewSigner([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	judgeSrv := httptest.NewServer(judgehttp.NewRouter(judgehttp.NewHandler(signer, &mockLogger{})))
+	t.Cleanup(judgeSrv.Close)
+
+	newChecker := func() *Checker {
+		return NewChecker(judgeSrv.URL+"/echo", 2*time.Second, &mockLogger{}).WithJudge(signer)
+	}
+
+	t.Run("accepts untouched judge response", func(t *testing.T) {
+		proxySrv := newForwardProxy(t, "", nil)
+
+		out := newChecker().Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		require.NoError(t, out.Error)
+		assert.True(t, out.Success)
+		assert.Equal(t, "elite", out.Anonymity)
+		assert.Equal(t, "127.0.0.1", out.ExitIP)
+	})
+
+	t.Run("detects rewritten origin", func(t *testing.T) {
+		proxySrv := newForwardProxy(t, "", func(body []byte) []byte {
+			return bytes.Replace(body, []byte(`"origin":"127.0.0.1"`), []byte(`"origin":"127.0.0.2"`), 1)
+		})
+
+		out := newChecker().Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrPayloadModified)
+		assert.ErrorIs(t, out.Error, judge.ErrInvalidSignature)
+	})
+
+	t.Run("detects replayed response", func(t *testing.T) {
+		var cached []byte
+		proxySrv := newForwardProxy(t, "", func(body []byte) []byte {
+			if cached == nil {
+				cached = body
+			}
+			return cached
+		})
+
+		c := newChecker()
+		target := httpTarget{address: proxySrv.Listener.Addr().String()}
+
+		first := c.Verify(context.Background(), target)
+		require.NoError(t, first.Error)
+
+		second := c.Verify(context.Background(), target)
+		assert.False(t, second.Success)
+		assert.ErrorIs(t, second.Error, judge.ErrNonceMismatch)
+	})
+
+	t.Run("rejects responses signed with another key", func(t *testing.T) {
+		other, err := judge.NewSigner([]byte("fedcba9876543210"))
+		require.NoError(t, err)
+
+		proxySrv := newForwardProxy(t, "", nil)
+		c := NewChecker(judgeSrv.URL+"/echo", 2*time.Second, &mockLogger{}).WithJudge(other)
+
+		out := c.Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, judge.ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,238 @@
+package httpverifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+type Strategy string
+
+const (
+	StrategyRoundRobin   Strategy = "round-robin"
+	StrategyFirstSuccess Strategy = "first-success"
+	StrategyQuorum       Strategy = "quorum"
+)
+
+const defaultHealthInterval = 30 * time.Second
+
+var (
+	ErrNoJudges        = errors.New("at least one judge is required")
+	ErrInvalidStrategy = errors.New("invalid judge strategy")
+	ErrInvalidQuorum   = errors.New("invalid judge quorum")
+)
+
+var anonymityRank = map[string]int{
+	"transparent": 1,
+	"anonymous":   2,
+	"elite":       3,
+}
+
+func ParseStrategy(s string) (Strategy, error) {
+	switch Strategy(s) {
+	case StrategyRoundRobin, StrategyFirstSuccess, StrategyQuorum:
+		return Strategy(s), nil
+	default:
+		return "", fmt.Errorf("%q: %w", s, ErrInvalidStrategy)
+	}
+}
+
+type MultiChecker struct {
+	judges         []*Checker
+	strategy       Strategy
+	quorum         int
+	logger         Logger
+	healthInterval time.Duration
+
+	next       atomic.Uint64
+	mu         sync.Mutex
+	healthy    []*Checker
+	checkedAt  time.Time
+	refreshing bool
+}
+
+func NewMultiChecker(judges []*Checker, strategy Strategy, quorum int, logger Logger) (*MultiChecker, error) {
+	if len(judges) == 0 {
+		return nil, ErrNoJudges
+	}
+
+	if _, err := ParseStrategy(string(strategy)); err != nil {
+		return nil, err
+	}
+
+	if strategy == StrategyQuorum {
+		if quorum == 0 {
+			quorum = len(judges)/2 + 1
+		}
+		if quorum < 1 || quorum > len(judges) {
+			return nil, fmt.Errorf("%d of %d: %w", quorum, len(judges), ErrInvalidQuorum)
+		}
+	}
+
+	return &MultiChecker{
+		judges:         judges,
+		strategy:       strategy,
+		quorum:         quorum,
+		logger:         logger,
+		healthInterval: defaultHealthInterval,
+		healthy:        judges,
+	}, nil
+}
+
+func (m *MultiChecker) WithHealthInterval(d time.Duration) *MultiChecker {
+	m.healthInterval = d
+	return m
+}
+
+func (m *MultiChecker) Verify(ctx context.Context, p verifier.Verifiable) verifier.VerifyOutput {
+	judges := m.healthyJudges(ctx)
+	if len(judges) == 0 {
+		return verifier.VerifyOutput{Error: fmt.Errorf("proxy %s: %w", p.Address(), verifier.ErrNoJudge)}
+	}
+
+	switch m.strategy {
+	case StrategyFirstSuccess:
+		return m.firstSuccess(ctx, p, judges)
+	case StrategyQuorum:
+		return m.quorumVerify(ctx, p, judges)
+	default:
+		return m.roundRobin(ctx, p, judges)
+	}
+}
+
+func (m *MultiChecker) roundRobin(ctx context.Context, p verifier.Verifiable, judges []*Checker) verifier.VerifyOutput {
+	i := m.next.Add(1) - 1
+	return judges[i%uint64(len(judges))].Verify(ctx, p)
+}
+
+func (m *MultiChecker) firstSuccess(ctx context.Context, p verifier.Verifiable, judges []*Checker) verifier.VerifyOutput {
+	var out verifier.VerifyOutput
+	for _, j := range judges {
+		out = j.Verify(ctx, p)
+		if out.Success || tampered(out.Error) || ctx.Err() != nil {
+			return out
+		}
+	}
+	return out
+}
+
+func (m *MultiChecker) quorumVerify(ctx context.Context, p verifier.Verifiable, judges []*Checker) verifier.VerifyOutput {
+	if len(judges) < m.quorum {
+		return verifier.VerifyOutput{
+			Error: fmt.Errorf("proxy %s: %d healthy judges, quorum %d: %w", p.Address(), len(judges), m.quorum, verifier.ErrNoJudge),
+		}
+	}
+
+	results := make([]verifier.VerifyOutput, len(judges))
+	var wg sync.WaitGroup
+	for i, j := range judges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = j.Verify(ctx, p)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		passed    []string
+		total     time.Duration
+		anonymity string
+		exit      string
+		firstErr  error
+	)
+	for i, out := range results {
+		if tampered(out.Error) {
+			return verifier.VerifyOutput{Latency: out.Latency, PassedJudges: passed, Error: out.Error}
+		}
+		if !out.Success {
+			if firstErr == nil {
+				firstErr = out.Error
+			}
+			continue
+		}
+
+		passed = append(passed, judges[i].TargetURL)
+		total += out.Latency
+		if exit == "" {
+			exit = out.ExitIP
+		}
+		if anonymity == "" || anonymityRank[out.Anonymity] < anonymityRank[anonymity] {
+			anonymity = out.Anonymity
+		}
+	}
+
+	if len(passed) < m.quorum {
+		return verifier.VerifyOutput{
+			PassedJudges: passed,
+			Error:        fmt.Errorf("proxy %s: %d of %d judges passed: %w: %w", p.Address(), len(passed), m.quorum, verifier.ErrQuorumNotMet, firstErr),
+		}
+	}
+
+	return verifier.VerifyOutput{
+		Success:      true,
+		Latency:      total / time.Duration(len(passed)),
+		Anonymity:    anonymity,
+		PassedJudges: passed,
+		ExitIP:       exit,
+	}
+}
+
+// healthyJudges returns the judges that passed the last health check. Once
+// the check is due, a single caller probes the judges without holding the
+// lock while the others keep using the previous list; a probe cut short by
+// ctx leaves that list in place.
+func (m *MultiChecker) healthyJudges(ctx context.Context) []*Checker {
+	m.mu.Lock()
+	healthy := m.healthy
+	due := !m.refreshing && (m.checkedAt.IsZero() || time.Since(m.checkedAt) >= m.healthInterval)
+	if due {
+		m.refreshing = true
+	}
+	m.mu.Unlock()
+
+	if !due {
+		return healthy
+	}
+
+	errs := make([]error, len(m.judges))
+	var wg sync.WaitGroup
+	for i, j := range m.judges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = j.Healthy(ctx)
+		}()
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshing = false
+
+	if ctx.Err() != nil {
+		return healthy
+	}
+
+	healthy = make([]*Checker, 0, len(m.judges))
+	for i, j := range m.judges {
+		if errs[i] != nil {
+			m.logger.Warn("skipping unhealthy judge", "judge", j.TargetURL, "error", errs[i])
+			continue
+		}
+		healthy = append(healthy, j)
+	}
+
+	m.healthy = healthy
+	m.checkedAt = time.Now()
+	return healthy
+}
+
+func tampered(err error) bool {
+	return errors.Is(err, verifier.ErrPayloadModified) || errors.Is(err, verifier.ErrInjectionDetected)
+}
@@ -0,0 +1,213 @@
+package httpverifier
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	judgehttp "github.com/JulianoL13/app-proxy-engine/internal/judge/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+const testVia = "1.1 test-proxy"
+
+func TestNewMultiChecker(t *testing.T) {
+	judges := []*Checker{
+		NewChecker("http://a/echo", time.Second, &mockLogger{}),
+		NewChecker("http://b/echo", time.Second, &mockLogger{}),
+		NewChecker("http://c/echo", time.Second, &mockLogger{}),
+	}
+
+	t.Run("requires judges", func(t *testing.T) {
+		_, err := NewMultiChecker(nil, StrategyRoundRobin, 0, &mockLogger{})
+		assert.ErrorIs(t, err, ErrNoJudges)
+	})
+
+	t.Run("rejects unknown strategy", func(t *testing.T) {
+		_, err := NewMultiChecker(judges, "random", 0, &mockLogger{})
+		assert.ErrorIs(t, err, ErrInvalidStrategy)
+	})
+
+	t.Run("rejects quorum larger than judge count", func(t *testing.T) {
+		_, err := NewMultiChecker(judges, StrategyQuorum, 4, &mockLogger{})
+		assert.ErrorIs(t, err, ErrInvalidQuorum)
+	})
+
+	t.Run("defaults quorum to majority", func(t *testing.T) {
+		m, err := NewMultiChecker(judges, StrategyQuorum, 0, &mockLogger{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, m.quorum)
+	})
+}
+
+func TestMultiChecker_Verify(t *testing.T) {
+	signer, err := judge.NewSigner([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+
+	echo := judgehttp.NewRouter(judgehttp.NewHandler(signer, &mockLogger{}))
+
+	newJudge := func(t *testing.T) string {
+		srv := httptest.NewServer(echo)
+		t.Cleanup(srv.Close)
+		return srv.URL + "/echo"
+	}
+
+	newProxyHostileJudge := func(t *testing.T) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Via") != "" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			echo.ServeHTTP(w, r)
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL + "/echo"
+	}
+
+	newDownJudge := func(t *testing.T) string {
+		srv := httptest.NewServer(echo)
+		srv.Close()
+		return srv.URL + "/echo"
+	}
+
+	checkers := func(urls ...string) []*Checker {
+		out := make([]*Checker, len(urls))
+		for i, u := range urls {
+			out[i] = NewChecker(u, 2*time.Second, &mockLogger{}).WithJudge(signer)
+		}
+		return out
+	}
+
+	proxySrv := newForwardProxy(t, testVia, nil)
+	target := httpTarget{address: proxySrv.Listener.Addr().String()}
+
+	t.Run("round robin alternates between judges", func(t *testing.T) {
+		a, b := newJudge(t), newJudge(t)
+		m, err := NewMultiChecker(checkers(a, b), StrategyRoundRobin, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		first := m.Verify(context.Background(), target)
+		second := m.Verify(context.Background(), target)
+
+		require.True(t, first.Success)
+		require.True(t, second.Success)
+		assert.Equal(t, []string{a}, first.PassedJudges)
+		assert.Equal(t, []string{b}, second.PassedJudges)
+	})
+
+	t.Run("skips unhealthy judges", func(t *testing.T) {
+		down, up := newDownJudge(t), newJudge(t)
+		m, err := NewMultiChecker(checkers(down, up), StrategyRoundRobin, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		for range 3 {
+			out := m.Verify(context.Background(), target)
+			require.True(t, out.Success)
+			assert.Equal(t, []string{up}, out.PassedJudges)
+		}
+	})
+
+	t.Run("reports no judge when all are down", func(t *testing.T) {
+		m, err := NewMultiChecker(checkers(newDownJudge(t)), StrategyFirstSuccess, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), target)
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrNoJudge)
+		assert.Equal(t, verifier.OutcomeNoJudge, verifier.Outcome(out))
+	})
+
+	t.Run("keeps the judges when the health check is cancelled", func(t *testing.T) {
+		down, up := newDownJudge(t), newJudge(t)
+		m, err := NewMultiChecker(checkers(down, up), StrategyFirstSuccess, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		out := m.Verify(ctx, target)
+		assert.NotErrorIs(t, out.Error, verifier.ErrNoJudge)
+
+		out = m.Verify(context.Background(), target)
+		require.True(t, out.Success)
+		assert.Equal(t, []string{up}, out.PassedJudges)
+	})
+
+	t.Run("first success falls through to the next judge", func(t *testing.T) {
+		hostile, good := newProxyHostileJudge(t), newJudge(t)
+		m, err := NewMultiChecker(checkers(hostile, good), StrategyFirstSuccess, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), target)
+
+		require.NoError(t, out.Error)
+		assert.Equal(t, []string{good}, out.PassedJudges)
+		assert.Equal(t, "anonymous", out.Anonymity)
+	})
+
+	t.Run("first success stops on tampering", func(t *testing.T) {
+		tamperer := newForwardProxy(t, "", func(body []byte) []byte {
+			return bytes.Replace(body, []byte(`"origin":"127.0.0.1"`), []byte(`"origin":"127.0.0.2"`), 1)
+		})
+		m, err := NewMultiChecker(checkers(newJudge(t), newJudge(t)), StrategyFirstSuccess, 0, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), httpTarget{address: tamperer.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrPayloadModified)
+		assert.Empty(t, out.PassedJudges)
+	})
+
+	t.Run("quorum succeeds when enough judges pass", func(t *testing.T) {
+		a, b, hostile := newJudge(t), newJudge(t), newProxyHostileJudge(t)
+		m, err := NewMultiChecker(checkers(a, b, hostile), StrategyQuorum, 2, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), target)
+
+		require.NoError(t, out.Error)
+		assert.True(t, out.Success)
+		assert.Equal(t, []string{a, b}, out.PassedJudges)
+	})
+
+	t.Run("quorum fails when too few judges pass", func(t *testing.T) {
+		a, hostile := newJudge(t), newProxyHostileJudge(t)
+		m, err := NewMultiChecker(checkers(a, hostile), StrategyQuorum, 2, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), target)
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrQuorumNotMet)
+		assert.ErrorIs(t, out.Error, verifier.ErrProxyDead)
+		assert.Equal(t, []string{a}, out.PassedJudges)
+	})
+
+	t.Run("quorum needs enough healthy judges", func(t *testing.T) {
+		m, err := NewMultiChecker(checkers(newJudge(t), newDownJudge(t)), StrategyQuorum, 2, &mockLogger{})
+		require.NoError(t, err)
+
+		out := m.Verify(context.Background(), target)
+
+		assert.ErrorIs(t, out.Error, verifier.ErrNoJudge)
+	})
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, s := range []Strategy{StrategyRoundRobin, StrategyFirstSuccess, StrategyQuorum} {
+		got, err := ParseStrategy(string(s))
+		require.NoError(t, err)
+		assert.Equal(t, s, got)
+	}
+
+	_, err := ParseStrategy("fastest")
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+}
@@ -0,0 +1,98 @@
+package httpverifier
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/common/tunnel"
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+// errNoTunnel marks proxies that cannot CONNECT at all; the capability probe
+// already records them as SupportsConnect=false, so there is nothing to pin.
+var errNoTunnel = errors.New("tunnel refused")
+
+type PinChecker struct {
+	inner   verifier.ProxyChecker
+	target  string
+	pins    []judge.Pin
+	timeout time.Duration
+	logger  Logger
+}
+
+func NewPinChecker(inner verifier.ProxyChecker, target string, pins []judge.Pin, timeout time.Duration, logger Logger) *PinChecker {
+	return &PinChecker{
+		inner:   inner,
+		target:  target,
+		pins:    pins,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+func (c *PinChecker) Verify(ctx context.Context, p verifier.Verifiable) verifier.VerifyOutput {
+	out := c.inner.Verify(ctx, p)
+	if !out.Success {
+		return out
+	}
+
+	intercepted, err := c.intercepted(ctx, p)
+	if errors.Is(err, errNoTunnel) {
+		c.logger.Debug("pin check skipped", "address", p.Address(), "error", err)
+		return out
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return verifier.VerifyOutput{
+			Latency: out.Latency,
+			Error:   fmt.Errorf("proxy %s: pin check: %w", p.Address(), verifier.ErrProxyTimeout),
+		}
+	}
+	if err != nil {
+		// the tunnel was up, so a failed handshake means something other than
+		// the target answered on it
+		return verifier.VerifyOutput{
+			Latency: out.Latency,
+			Error:   fmt.Errorf("proxy %s: %w: %w", p.Address(), verifier.ErrInjectionDetected, err),
+		}
+	}
+	if intercepted {
+		return verifier.VerifyOutput{
+			Latency: out.Latency,
+			Error:   fmt.Errorf("proxy %s: certificate does not match pin: %w", p.Address(), verifier.ErrInjectionDetected),
+		}
+	}
+	return out
+}
+
+func (c *PinChecker) intercepted(ctx context.Context, p verifier.Verifiable) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := tunnel.Dial(ctx, p.URL(), c.target, c.timeout)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", errNoTunnel, err)
+	}
+	defer conn.Close()
+
+	host, _, err := net.SplitHostPort(c.target)
+	if err != nil {
+		host = c.target
+	}
+
+	// The chain is checked against the pins below, not against system roots:
+	// an interceptor with a trusted CA must still be caught.
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("tls handshake: %w", ctx.Err())
+		}
+		return false, fmt.Errorf("tls handshake: %w", err)
+	}
+
+	return !judge.MatchesPins(tlsConn.ConnectionState().PeerCertificates, c.pins), nil
+}
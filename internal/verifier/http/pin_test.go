@@ -0,0 +1,165 @@
+package httpverifier
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+type stubChecker struct {
+	out verifier.VerifyOutput
+}
+
+func (s stubChecker) Verify(ctx context.Context, p verifier.Verifiable) verifier.VerifyOutput {
+	return s.out
+}
+
+func newImpostor(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newInterceptingProxy(t *testing.T, impostor string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		hijackTunnel(w, impostor)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPinChecker_Verify(t *testing.T) {
+	judgeSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(judgeSrv.Close)
+	target := judgeSrv.Listener.Addr().String()
+
+	passed := verifier.VerifyOutput{Success: true, Latency: 50 * time.Millisecond, Anonymity: "elite"}
+
+	newChecker := func(inner verifier.VerifyOutput, pin judge.Pin) *PinChecker {
+		return NewPinChecker(stubChecker{out: inner}, target, []judge.Pin{pin}, 2*time.Second, &mockLogger{})
+	}
+
+	t.Run("passes when the tunnelled chain matches the public key pin", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+
+		out := newChecker(passed, judge.PublicKeyPin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.Equal(t, passed, out)
+	})
+
+	t.Run("passes when the tunnelled chain matches the certificate pin", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, true)
+
+		out := newChecker(passed, judge.CertificatePin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.True(t, out.Success)
+	})
+
+	t.Run("detects a proxy presenting another certificate", func(t *testing.T) {
+		proxySrv := newInterceptingProxy(t, newImpostor(t).Listener.Addr().String())
+
+		out := newChecker(passed, judge.PublicKeyPin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrInjectionDetected)
+		assert.Equal(t, verifier.OutcomeInjection, verifier.Outcome(out))
+		assert.Equal(t, passed.Latency, out.Latency)
+	})
+
+	t.Run("keeps the result when the proxy refuses connect", func(t *testing.T) {
+		proxySrv := newConnectProxy(t, false)
+
+		out := newChecker(passed, judge.PublicKeyPin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.Equal(t, passed, out)
+	})
+
+	t.Run("fails when the tunnel does not speak tls", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		t.Cleanup(plain.Close)
+		proxySrv := newInterceptingProxy(t, plain.Listener.Addr().String())
+
+		out := newChecker(passed, judge.PublicKeyPin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrInjectionDetected)
+	})
+
+	t.Run("times out when the tunnel stays silent", func(t *testing.T) {
+		silent, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { silent.Close() })
+		go func() {
+			for {
+				conn, err := silent.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					_, _ = io.Copy(io.Discard, conn)
+				}()
+			}
+		}()
+		proxySrv := newInterceptingProxy(t, silent.Addr().String())
+
+		checker := NewPinChecker(stubChecker{out: passed}, target, []judge.Pin{judge.PublicKeyPin(judgeSrv.Certificate())}, 200*time.Millisecond, &mockLogger{})
+		out := checker.Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrProxyTimeout)
+	})
+
+	t.Run("does not tunnel when the inner check fails", func(t *testing.T) {
+		failed := verifier.VerifyOutput{Error: verifier.ErrProxyDead}
+		proxySrv := newInterceptingProxy(t, newImpostor(t).Listener.Addr().String())
+
+		out := newChecker(failed, judge.PublicKeyPin(judgeSrv.Certificate())).
+			Verify(context.Background(), httpTarget{address: proxySrv.Listener.Addr().String()})
+
+		assert.Equal(t, failed, out)
+	})
+}
@@ -0,0 +1,172 @@
+package httpverifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+const socks4Granted = 0x5A
+
+type socks4Request struct {
+	target string
+	userID string
+}
+
+type socks4Server struct {
+	addr     string
+	reply    byte
+	mu       sync.Mutex
+	requests []socks4Request
+}
+
+func newSOCKS4Server(t *testing.T, reply byte) *socks4Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &socks4Server{addr: ln.Addr().String(), reply: reply}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *socks4Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	userID, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+
+	host := net.IP(header[4:8]).String()
+	if header[4] == 0 && header[5] == 0 && header[6] == 0 && header[7] != 0 {
+		domain, err := r.ReadString(0)
+		if err != nil {
+			return
+		}
+		host = domain[:len(domain)-1]
+	}
+	target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(header[2:4]))))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, socks4Request{target: target, userID: userID[:len(userID)-1]})
+	s.mu.Unlock()
+
+	if s.reply != socks4Granted {
+		_, _ = conn.Write([]byte{0, s.reply, 0, 0, 0, 0, 0, 0})
+		return
+	}
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_, _ = conn.Write([]byte{0, 0x5B, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	_, _ = conn.Write([]byte{0, socks4Granted, 0, 0, 0, 0, 0, 0})
+
+	go func() { _, _ = io.Copy(upstream, r) }()
+	_, _ = io.Copy(conn, upstream)
+}
+
+func (s *socks4Server) received() []socks4Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]socks4Request(nil), s.requests...)
+}
+
+type socks4Target struct {
+	address string
+}
+
+func (p socks4Target) Address() string { return p.address }
+func (p socks4Target) URL() *url.URL {
+	return &url.URL{Scheme: "socks4", Host: p.address}
+}
+
+func newHTTPBin(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"args":{},"headers":{"Host":"` + r.Host + `"},"origin":"10.0.0.1","url":"http://` + r.Host + `/get"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChecker_Verify_SOCKS4(t *testing.T) {
+	target := newHTTPBin(t)
+
+	t.Run("verifies through socks4 proxy", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, socks4Granted)
+		c := NewChecker(target.URL+"/get", 2*time.Second, &mockLogger{})
+
+		out := c.Verify(context.Background(), socks4Target{address: proxySrv.addr})
+
+		require.NoError(t, out.Error)
+		assert.True(t, out.Success)
+		assert.Equal(t, "elite", out.Anonymity)
+
+		requests := proxySrv.received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, target.Listener.Addr().String(), requests[0].target)
+	})
+
+	t.Run("fails when proxy rejects the request", func(t *testing.T) {
+		proxySrv := newSOCKS4Server(t, 0x5B)
+		c := NewChecker(target.URL+"/get", 2*time.Second, &mockLogger{})
+
+		out := c.Verify(context.Background(), socks4Target{address: proxySrv.addr})
+
+		assert.False(t, out.Success)
+		assert.ErrorIs(t, out.Error, verifier.ErrProxyDead)
+	})
+
+	t.Run("fails when proxy is unreachable", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		c := NewChecker(target.URL+"/get", 2*time.Second, &mockLogger{})
+
+		out := c.Verify(context.Background(), socks4Target{address: addr})
+
+		assert.False(t, out.Success)
+		assert.Error(t, out.Error)
+	})
+}
@@ -0,0 +1,98 @@
+package mmdb
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/oschwald/maxminddb-golang/v2"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+var (
+	ErrNoDatabase = errors.New("no geoip database given")
+	ErrInvalidIP  = errors.New("invalid ip address")
+	ErrNotFound   = errors.New("ip not found in geoip database")
+)
+
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	ASN uint `maxminddb:"autonomous_system_number"`
+}
+
+type Locator struct {
+	dbs []*maxminddb.Reader
+}
+
+func Open(paths ...string) (*Locator, error) {
+	if len(paths) == 0 {
+		return nil, ErrNoDatabase
+	}
+
+	l := &Locator{}
+	for _, path := range paths {
+		db, err := maxminddb.Open(path)
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		l.dbs = append(l.dbs, db)
+	}
+	return l, nil
+}
+
+func (l *Locator) Close() error {
+	var errs []error
+	for _, db := range l.dbs {
+		errs = append(errs, db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Locator) Locate(ip string) (verifier.Location, error) {
+	var loc verifier.Location
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return loc, fmt.Errorf("%q: %w", ip, ErrInvalidIP)
+	}
+	addr = addr.Unmap()
+
+	found := false
+	for _, db := range l.dbs {
+		result := db.Lookup(addr)
+		if !result.Found() {
+			if err := result.Err(); err != nil {
+				return loc, fmt.Errorf("lookup %s: %w", ip, err)
+			}
+			continue
+		}
+
+		var rec record
+		if err := result.Decode(&rec); err != nil {
+			return loc, fmt.Errorf("decode %s: %w", ip, err)
+		}
+		found = true
+
+		if loc.Country == "" {
+			loc.Country = rec.Country.ISOCode
+		}
+		if loc.City == "" {
+			loc.City = rec.City.Names["en"]
+		}
+		if loc.ASN == 0 {
+			loc.ASN = rec.ASN
+		}
+	}
+
+	if !found {
+		return loc, fmt.Errorf("%s: %w", ip, ErrNotFound)
+	}
+	return loc, nil
+}
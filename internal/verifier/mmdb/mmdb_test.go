@@ -0,0 +1,90 @@
+package mmdb_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier/mmdb"
+)
+
+func writeDB(t *testing.T, dbType string, records map[string]mmdbtype.Map) string {
+	t.Helper()
+
+	tree, err := mmdbwriter.New(mmdbwriter.Options{DatabaseType: dbType, RecordSize: 24})
+	require.NoError(t, err)
+
+	for cidr, data := range records {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		require.NoError(t, tree.Insert(network, data))
+	}
+
+	path := filepath.Join(t.TempDir(), dbType+".mmdb")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = tree.WriteTo(f)
+	require.NoError(t, err)
+	return path
+}
+
+func TestLocator_Locate(t *testing.T) {
+	city := writeDB(t, "GeoLite2-City", map[string]mmdbtype.Map{
+		"177.54.0.0/16": {
+			"country": mmdbtype.Map{"iso_code": mmdbtype.String("BR")},
+			"city":    mmdbtype.Map{"names": mmdbtype.Map{"en": mmdbtype.String("Sao Paulo")}},
+		},
+		"81.2.69.0/24": {
+			"country": mmdbtype.Map{"iso_code": mmdbtype.String("GB")},
+		},
+	})
+	asn := writeDB(t, "GeoLite2-ASN", map[string]mmdbtype.Map{
+		"177.54.0.0/16": {
+			"autonomous_system_number":       mmdbtype.Uint32(28573),
+			"autonomous_system_organization": mmdbtype.String("Claro NXT"),
+		},
+	})
+
+	locator, err := mmdb.Open(city, asn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = locator.Close() })
+
+	t.Run("merges city and asn databases", func(t *testing.T) {
+		loc, err := locator.Locate("177.54.10.20")
+		require.NoError(t, err)
+		assert.Equal(t, verifier.Location{Country: "BR", City: "Sao Paulo", ASN: 28573}, loc)
+	})
+
+	t.Run("returns partial data when only one database knows the ip", func(t *testing.T) {
+		loc, err := locator.Locate("81.2.69.142")
+		require.NoError(t, err)
+		assert.Equal(t, verifier.Location{Country: "GB"}, loc)
+	})
+
+	t.Run("reports unknown ips", func(t *testing.T) {
+		_, err := locator.Locate("8.8.8.8")
+		assert.ErrorIs(t, err, mmdb.ErrNotFound)
+	})
+
+	t.Run("rejects invalid ips", func(t *testing.T) {
+		_, err := locator.Locate("not-an-ip")
+		assert.ErrorIs(t, err, mmdb.ErrInvalidIP)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	_, err := mmdb.Open()
+	assert.ErrorIs(t, err, mmdb.ErrNoDatabase)
+
+	_, err = mmdb.Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	verifier "github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+// CapabilityProber is an autogenerated mock type for the CapabilityProber type
+type CapabilityProber struct {
+	mock.Mock
+}
+
+type CapabilityProber_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *CapabilityProber) EXPECT() *CapabilityProber_Expecter {
+	return &CapabilityProber_Expecter{mock: &_m.Mock}
+}
+
+// Probe provides a mock function with given fields: ctx, p
+func (_m *CapabilityProber) Probe(ctx context.Context, p verifier.Verifiable) verifier.Capabilities {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Probe")
+	}
+
+	var r0 verifier.Capabilities
+	if rf, ok := ret.Get(0).(func(context.Context, verifier.Verifiable) verifier.Capabilities); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Get(0).(verifier.Capabilities)
+	}
+
+	return r0
+}
+
+// CapabilityProber_Probe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Probe'
+type CapabilityProber_Probe_Call struct {
+	*mock.Call
+}
+
+// Probe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - p verifier.Verifiable
+func (_e *CapabilityProber_Expecter) Probe(ctx interface{}, p interface{}) *CapabilityProber_Probe_Call {
+	return &CapabilityProber_Probe_Call{Call: _e.mock.On("Probe", ctx, p)}
+}
+
+func (_c *CapabilityProber_Probe_Call) Run(run func(ctx context.Context, p verifier.Verifiable)) *CapabilityProber_Probe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(verifier.Verifiable))
+	})
+	return _c
+}
+
+func (_c *CapabilityProber_Probe_Call) Return(_a0 verifier.Capabilities) *CapabilityProber_Probe_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *CapabilityProber_Probe_Call) RunAndReturn(run func(context.Context, verifier.Verifiable) verifier.Capabilities) *CapabilityProber_Probe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCapabilityProber creates a new instance of CapabilityProber. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCapabilityProber(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CapabilityProber {
+	mock := &CapabilityProber{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -72,6 +72,55 @@ func (_c *Consumer_Ack_Call) RunAndReturn(run func(context.Context, string, stri
 	return _c
 }
 
+// Nack provides a mock function with given fields: ctx, topic, group, msgID
+func (_m *Consumer) Nack(ctx context.Context, topic string, group string, msgID string) error {
+	ret := _m.Called(ctx, topic, group, msgID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Nack")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, topic, group, msgID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Consumer_Nack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nack'
+type Consumer_Nack_Call struct {
+	*mock.Call
+}
+
+// Nack is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - group string
+//   - msgID string
+func (_e *Consumer_Expecter) Nack(ctx interface{}, topic interface{}, group interface{}, msgID interface{}) *Consumer_Nack_Call {
+	return &Consumer_Nack_Call{Call: _e.mock.On("Nack", ctx, topic, group, msgID)}
+}
+
+func (_c *Consumer_Nack_Call) Run(run func(ctx context.Context, topic string, group string, msgID string)) *Consumer_Nack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Consumer_Nack_Call) Return(_a0 error) *Consumer_Nack_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Consumer_Nack_Call) RunAndReturn(run func(context.Context, string, string, string) error) *Consumer_Nack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Subscribe provides a mock function with given fields: ctx, topic, group, consumer
 func (_m *Consumer) Subscribe(ctx context.Context, topic string, group string, consumer string) (<-chan verifier.Message, error) {
 	ret := _m.Called(ctx, topic, group, consumer)
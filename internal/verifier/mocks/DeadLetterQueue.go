@@ -0,0 +1,88 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	verifier "github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+// DeadLetterQueue is an autogenerated mock type for the DeadLetterQueue type
+type DeadLetterQueue struct {
+	mock.Mock
+}
+
+type DeadLetterQueue_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DeadLetterQueue) EXPECT() *DeadLetterQueue_Expecter {
+	return &DeadLetterQueue_Expecter{mock: &_m.Mock}
+}
+
+// DeadLetter provides a mock function with given fields: ctx, topic, group, msg, reason
+func (_m *DeadLetterQueue) DeadLetter(ctx context.Context, topic string, group string, msg verifier.Message, reason string) error {
+	ret := _m.Called(ctx, topic, group, msg, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, verifier.Message, string) error); ok {
+		r0 = rf(ctx, topic, group, msg, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeadLetterQueue_DeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeadLetter'
+type DeadLetterQueue_DeadLetter_Call struct {
+	*mock.Call
+}
+
+// DeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - topic string
+//   - group string
+//   - msg verifier.Message
+//   - reason string
+func (_e *DeadLetterQueue_Expecter) DeadLetter(ctx interface{}, topic interface{}, group interface{}, msg interface{}, reason interface{}) *DeadLetterQueue_DeadLetter_Call {
+	return &DeadLetterQueue_DeadLetter_Call{Call: _e.mock.On("DeadLetter", ctx, topic, group, msg, reason)}
+}
+
+func (_c *DeadLetterQueue_DeadLetter_Call) Run(run func(ctx context.Context, topic string, group string, msg verifier.Message, reason string)) *DeadLetterQueue_DeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(verifier.Message), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *DeadLetterQueue_DeadLetter_Call) Return(_a0 error) *DeadLetterQueue_DeadLetter_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DeadLetterQueue_DeadLetter_Call) RunAndReturn(run func(context.Context, string, string, verifier.Message, string) error) *DeadLetterQueue_DeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDeadLetterQueue creates a new instance of DeadLetterQueue. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterQueue(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterQueue {
+	mock := &DeadLetterQueue{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	verifier "github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+// GeoLocator is an autogenerated mock type for the GeoLocator type
+type GeoLocator struct {
+	mock.Mock
+}
+
+type GeoLocator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeoLocator) EXPECT() *GeoLocator_Expecter {
+	return &GeoLocator_Expecter{mock: &_m.Mock}
+}
+
+// Locate provides a mock function with given fields: ip
+func (_m *GeoLocator) Locate(ip string) (verifier.Location, error) {
+	ret := _m.Called(ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Locate")
+	}
+
+	var r0 verifier.Location
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (verifier.Location, error)); ok {
+		return rf(ip)
+	}
+	if rf, ok := ret.Get(0).(func(string) verifier.Location); ok {
+		r0 = rf(ip)
+	} else {
+		r0 = ret.Get(0).(verifier.Location)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeoLocator_Locate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Locate'
+type GeoLocator_Locate_Call struct {
+	*mock.Call
+}
+
+// Locate is a helper method to define mock.On call
+//   - ip string
+func (_e *GeoLocator_Expecter) Locate(ip interface{}) *GeoLocator_Locate_Call {
+	return &GeoLocator_Locate_Call{Call: _e.mock.On("Locate", ip)}
+}
+
+func (_c *GeoLocator_Locate_Call) Run(run func(ip string)) *GeoLocator_Locate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *GeoLocator_Locate_Call) Return(_a0 verifier.Location, _a1 error) *GeoLocator_Locate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GeoLocator_Locate_Call) RunAndReturn(run func(string) (verifier.Location, error)) *GeoLocator_Locate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeoLocator creates a new instance of GeoLocator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeoLocator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeoLocator {
+	mock := &GeoLocator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
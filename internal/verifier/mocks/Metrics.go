@@ -0,0 +1,70 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Metrics is an autogenerated mock type for the Metrics type
+type Metrics struct {
+	mock.Mock
+}
+
+type Metrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Metrics) EXPECT() *Metrics_Expecter {
+	return &Metrics_Expecter{mock: &_m.Mock}
+}
+
+// ObserveVerification provides a mock function with given fields: outcome, latency
+func (_m *Metrics) ObserveVerification(outcome string, latency time.Duration) {
+	_m.Called(outcome, latency)
+}
+
+// Metrics_ObserveVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveVerification'
+type Metrics_ObserveVerification_Call struct {
+	*mock.Call
+}
+
+// ObserveVerification is a helper method to define mock.On call
+//   - outcome string
+//   - latency time.Duration
+func (_e *Metrics_Expecter) ObserveVerification(outcome interface{}, latency interface{}) *Metrics_ObserveVerification_Call {
+	return &Metrics_ObserveVerification_Call{Call: _e.mock.On("ObserveVerification", outcome, latency)}
+}
+
+func (_c *Metrics_ObserveVerification_Call) Run(run func(outcome string, latency time.Duration)) *Metrics_ObserveVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *Metrics_ObserveVerification_Call) Return() *Metrics_ObserveVerification_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_ObserveVerification_Call) RunAndReturn(run func(string, time.Duration)) *Metrics_ObserveVerification_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Metrics {
+	mock := &Metrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,85 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StatsRecorder is an autogenerated mock type for the StatsRecorder type
+type StatsRecorder struct {
+	mock.Mock
+}
+
+type StatsRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StatsRecorder) EXPECT() *StatsRecorder_Expecter {
+	return &StatsRecorder_Expecter{mock: &_m.Mock}
+}
+
+// RecordVerified provides a mock function with given fields: ctx, source, latency
+func (_m *StatsRecorder) RecordVerified(ctx context.Context, source string, latency time.Duration) error {
+	ret := _m.Called(ctx, source, latency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, source, latency)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StatsRecorder_RecordVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordVerified'
+type StatsRecorder_RecordVerified_Call struct {
+	*mock.Call
+}
+
+// RecordVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+//   - latency time.Duration
+func (_e *StatsRecorder_Expecter) RecordVerified(ctx interface{}, source interface{}, latency interface{}) *StatsRecorder_RecordVerified_Call {
+	return &StatsRecorder_RecordVerified_Call{Call: _e.mock.On("RecordVerified", ctx, source, latency)}
+}
+
+func (_c *StatsRecorder_RecordVerified_Call) Run(run func(ctx context.Context, source string, latency time.Duration)) *StatsRecorder_RecordVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *StatsRecorder_RecordVerified_Call) Return(_a0 error) *StatsRecorder_RecordVerified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StatsRecorder_RecordVerified_Call) RunAndReturn(run func(context.Context, string, time.Duration) error) *StatsRecorder_RecordVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStatsRecorder creates a new instance of StatsRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatsRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatsRecorder {
+	mock := &StatsRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	url "net/url"
+
+	verifier "github.com/JulianoL13/app-proxy-engine/internal/verifier"
 )
 
 // VerifiedProxy is an autogenerated mock type for the VerifiedProxy type
@@ -102,6 +104,195 @@ func (_c *VerifiedProxy_MarkSuccess_Call) RunAndReturn(run func(time.Duration, s
 	return _c
 }
 
+// Recheck provides a mock function with no fields
+func (_m *VerifiedProxy) Recheck() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recheck")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// VerifiedProxy_Recheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Recheck'
+type VerifiedProxy_Recheck_Call struct {
+	*mock.Call
+}
+
+// Recheck is a helper method to define mock.On call
+func (_e *VerifiedProxy_Expecter) Recheck() *VerifiedProxy_Recheck_Call {
+	return &VerifiedProxy_Recheck_Call{Call: _e.mock.On("Recheck")}
+}
+
+func (_c *VerifiedProxy_Recheck_Call) Run(run func()) *VerifiedProxy_Recheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *VerifiedProxy_Recheck_Call) Return(_a0 bool) *VerifiedProxy_Recheck_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *VerifiedProxy_Recheck_Call) RunAndReturn(run func() bool) *VerifiedProxy_Recheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCapabilities provides a mock function with given fields: caps
+func (_m *VerifiedProxy) SetCapabilities(caps verifier.Capabilities) {
+	_m.Called(caps)
+}
+
+// VerifiedProxy_SetCapabilities_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCapabilities'
+type VerifiedProxy_SetCapabilities_Call struct {
+	*mock.Call
+}
+
+// SetCapabilities is a helper method to define mock.On call
+//   - caps verifier.Capabilities
+func (_e *VerifiedProxy_Expecter) SetCapabilities(caps interface{}) *VerifiedProxy_SetCapabilities_Call {
+	return &VerifiedProxy_SetCapabilities_Call{Call: _e.mock.On("SetCapabilities", caps)}
+}
+
+func (_c *VerifiedProxy_SetCapabilities_Call) Run(run func(caps verifier.Capabilities)) *VerifiedProxy_SetCapabilities_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(verifier.Capabilities))
+	})
+	return _c
+}
+
+func (_c *VerifiedProxy_SetCapabilities_Call) Return() *VerifiedProxy_SetCapabilities_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *VerifiedProxy_SetCapabilities_Call) RunAndReturn(run func(verifier.Capabilities)) *VerifiedProxy_SetCapabilities_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetExitIP provides a mock function with given fields: ip
+func (_m *VerifiedProxy) SetExitIP(ip string) {
+	_m.Called(ip)
+}
+
+// VerifiedProxy_SetExitIP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetExitIP'
+type VerifiedProxy_SetExitIP_Call struct {
+	*mock.Call
+}
+
+// SetExitIP is a helper method to define mock.On call
+//   - ip string
+func (_e *VerifiedProxy_Expecter) SetExitIP(ip interface{}) *VerifiedProxy_SetExitIP_Call {
+	return &VerifiedProxy_SetExitIP_Call{Call: _e.mock.On("SetExitIP", ip)}
+}
+
+func (_c *VerifiedProxy_SetExitIP_Call) Run(run func(ip string)) *VerifiedProxy_SetExitIP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *VerifiedProxy_SetExitIP_Call) Return() *VerifiedProxy_SetExitIP_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *VerifiedProxy_SetExitIP_Call) RunAndReturn(run func(string)) *VerifiedProxy_SetExitIP_Call {
+	_c.Run(run)
+	return _c
+}
+
+// SetLocation provides a mock function with given fields: loc
+func (_m *VerifiedProxy) SetLocation(loc verifier.Location) {
+	_m.Called(loc)
+}
+
+// VerifiedProxy_SetLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLocation'
+type VerifiedProxy_SetLocation_Call struct {
+	*mock.Call
+}
+
+// SetLocation is a helper method to define mock.On call
+//   - loc verifier.Location
+func (_e *VerifiedProxy_Expecter) SetLocation(loc interface{}) *VerifiedProxy_SetLocation_Call {
+	return &VerifiedProxy_SetLocation_Call{Call: _e.mock.On("SetLocation", loc)}
+}
+
+func (_c *VerifiedProxy_SetLocation_Call) Run(run func(loc verifier.Location)) *VerifiedProxy_SetLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(verifier.Location))
+	})
+	return _c
+}
+
+func (_c *VerifiedProxy_SetLocation_Call) Return() *VerifiedProxy_SetLocation_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *VerifiedProxy_SetLocation_Call) RunAndReturn(run func(verifier.Location)) *VerifiedProxy_SetLocation_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Source provides a mock function with no fields
+func (_m *VerifiedProxy) Source() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Source")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// VerifiedProxy_Source_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Source'
+type VerifiedProxy_Source_Call struct {
+	*mock.Call
+}
+
+// Source is a helper method to define mock.On call
+func (_e *VerifiedProxy_Expecter) Source() *VerifiedProxy_Source_Call {
+	return &VerifiedProxy_Source_Call{Call: _e.mock.On("Source")}
+}
+
+func (_c *VerifiedProxy_Source_Call) Run(run func()) *VerifiedProxy_Source_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *VerifiedProxy_Source_Call) Return(_a0 string) *VerifiedProxy_Source_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *VerifiedProxy_Source_Call) RunAndReturn(run func() string) *VerifiedProxy_Source_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // URL provides a mock function with no fields
 func (_m *VerifiedProxy) URL() *url.URL {
 	ret := _m.Called()
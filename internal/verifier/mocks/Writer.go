@@ -23,6 +23,100 @@ func (_m *Writer) EXPECT() *Writer_Expecter {
 	return &Writer_Expecter{mock: &_m.Mock}
 }
 
+// RecordFailure provides a mock function with given fields: ctx, p
+func (_m *Writer) RecordFailure(ctx context.Context, p verifier.VerifiedProxy) error {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, verifier.VerifiedProxy) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Writer_RecordFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordFailure'
+type Writer_RecordFailure_Call struct {
+	*mock.Call
+}
+
+// RecordFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - p verifier.VerifiedProxy
+func (_e *Writer_Expecter) RecordFailure(ctx interface{}, p interface{}) *Writer_RecordFailure_Call {
+	return &Writer_RecordFailure_Call{Call: _e.mock.On("RecordFailure", ctx, p)}
+}
+
+func (_c *Writer_RecordFailure_Call) Run(run func(ctx context.Context, p verifier.VerifiedProxy)) *Writer_RecordFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(verifier.VerifiedProxy))
+	})
+	return _c
+}
+
+func (_c *Writer_RecordFailure_Call) Return(_a0 error) *Writer_RecordFailure_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Writer_RecordFailure_Call) RunAndReturn(run func(context.Context, verifier.VerifiedProxy) error) *Writer_RecordFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordMITM provides a mock function with given fields: ctx, p
+func (_m *Writer) RecordMITM(ctx context.Context, p verifier.VerifiedProxy) error {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordMITM")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, verifier.VerifiedProxy) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Writer_RecordMITM_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordMITM'
+type Writer_RecordMITM_Call struct {
+	*mock.Call
+}
+
+// RecordMITM is a helper method to define mock.On call
+//   - ctx context.Context
+//   - p verifier.VerifiedProxy
+func (_e *Writer_Expecter) RecordMITM(ctx interface{}, p interface{}) *Writer_RecordMITM_Call {
+	return &Writer_RecordMITM_Call{Call: _e.mock.On("RecordMITM", ctx, p)}
+}
+
+func (_c *Writer_RecordMITM_Call) Run(run func(ctx context.Context, p verifier.VerifiedProxy)) *Writer_RecordMITM_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(verifier.VerifiedProxy))
+	})
+	return _c
+}
+
+func (_c *Writer_RecordMITM_Call) Return(_a0 error) *Writer_RecordMITM_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Writer_RecordMITM_Call) RunAndReturn(run func(context.Context, verifier.VerifiedProxy) error) *Writer_RecordMITM_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function with given fields: ctx, p
 func (_m *Writer) Save(ctx context.Context, p verifier.VerifiedProxy) error {
 	ret := _m.Called(ctx, p)
@@ -2,11 +2,20 @@ package verifier
 
 import (
 	"context"
+	"errors"
+	"net"
 	"net/url"
 	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const tracerName = "github.com/JulianoL13/app-proxy-engine/internal/verifier"
+
 type Logger interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
@@ -14,13 +23,20 @@ type Logger interface {
 }
 
 type Message struct {
-	ID      string
-	Payload []byte
+	ID         string
+	Payload    []byte
+	Headers    map[string]string
+	Deliveries int64
 }
 
 type Consumer interface {
 	Subscribe(ctx context.Context, topic, group, consumer string) (<-chan Message, error)
 	Ack(ctx context.Context, topic, group, msgID string) error
+	Nack(ctx context.Context, topic, group, msgID string) error
+}
+
+type DeadLetterQueue interface {
+	DeadLetter(ctx context.Context, topic, group string, msg Message, reason string) error
 }
 
 type Verifiable interface {
@@ -33,15 +49,41 @@ type ProxyChecker interface {
 }
 
 type VerifyOutput struct {
-	Success   bool
-	Latency   time.Duration
-	Anonymity string
-	Error     error
+	Success      bool
+	Latency      time.Duration
+	Anonymity    string
+	PassedJudges []string
+	ExitIP       string
+	Error        error
+}
+
+type Capabilities struct {
+	Connect   bool
+	PlainHTTP bool
+}
+
+type CapabilityProber interface {
+	Probe(ctx context.Context, p Verifiable) Capabilities
+}
+
+type Location struct {
+	Country string
+	City    string
+	ASN     uint
+}
+
+type GeoLocator interface {
+	Locate(ip string) (Location, error)
 }
 
 type VerifiedProxy interface {
 	Verifiable
+	Source() string
+	Recheck() bool
 	MarkSuccess(latency time.Duration, anonymity string)
+	SetCapabilities(caps Capabilities)
+	SetExitIP(ip string)
+	SetLocation(loc Location)
 }
 
 type ProxyDeserializer interface {
@@ -54,6 +96,16 @@ type WorkerPool interface {
 
 type Writer interface {
 	Save(ctx context.Context, p VerifiedProxy) error
+	RecordFailure(ctx context.Context, p VerifiedProxy) error
+	RecordMITM(ctx context.Context, p VerifiedProxy) error
+}
+
+type StatsRecorder interface {
+	RecordVerified(ctx context.Context, source string, latency time.Duration) error
+}
+
+type Metrics interface {
+	ObserveVerification(outcome string, latency time.Duration)
 }
 
 type VerifyFromQueueUseCase struct {
@@ -61,6 +113,12 @@ type VerifyFromQueueUseCase struct {
 	checker      ProxyChecker
 	deserializer ProxyDeserializer
 	writer       Writer
+	stats        StatsRecorder
+	metrics      Metrics
+	prober       CapabilityProber
+	geo          GeoLocator
+	dlq          DeadLetterQueue
+	maxAttempts  int64
 	logger       Logger
 	pool         WorkerPool
 	id           string
@@ -92,6 +150,32 @@ func NewVerifyFromQueueUseCase(
 	}
 }
 
+func (uc *VerifyFromQueueUseCase) WithStats(stats StatsRecorder) *VerifyFromQueueUseCase {
+	uc.stats = stats
+	return uc
+}
+
+func (uc *VerifyFromQueueUseCase) WithMetrics(metrics Metrics) *VerifyFromQueueUseCase {
+	uc.metrics = metrics
+	return uc
+}
+
+func (uc *VerifyFromQueueUseCase) WithCapabilities(prober CapabilityProber) *VerifyFromQueueUseCase {
+	uc.prober = prober
+	return uc
+}
+
+func (uc *VerifyFromQueueUseCase) WithGeoIP(geo GeoLocator) *VerifyFromQueueUseCase {
+	uc.geo = geo
+	return uc
+}
+
+func (uc *VerifyFromQueueUseCase) WithRetry(maxAttempts int, dlq DeadLetterQueue) *VerifyFromQueueUseCase {
+	uc.maxAttempts = int64(maxAttempts)
+	uc.dlq = dlq
+	return uc
+}
+
 func (uc *VerifyFromQueueUseCase) Execute(ctx context.Context) error {
 	uc.logger.Info("starting verification", "consumer", uc.id, "topic", uc.topic, "group", uc.group)
 
@@ -111,27 +195,60 @@ func (uc *VerifyFromQueueUseCase) Execute(ctx context.Context) error {
 			p, err := uc.deserializer.Deserialize(m.Payload)
 			if err != nil {
 				uc.logger.Warn("failed to deserialize proxy", "error", err, "msgID", m.ID)
-				if err := uc.consumer.Ack(ctx, uc.topic, uc.group, m.ID); err != nil {
-					uc.logger.Warn("failed to ack message", "msgID", m.ID, "error", err)
-				}
+				uc.deadLetter(ctx, m, err)
 				return
 			}
 
-			result := uc.checker.Verify(ctx, p)
+			spanCtx, span := uc.startSpan(ctx, m, p)
+			defer span.End()
+
+			result := uc.checker.Verify(spanCtx, p)
 			current := processed.Add(1)
+			span.SetAttributes(attribute.String("verifier.outcome", Outcome(result)))
+			if uc.metrics != nil {
+				uc.metrics.ObserveVerification(Outcome(result), result.Latency)
+			}
 
-			if result.Success {
+			var writeErr error
+			if errors.Is(result.Error, ErrNoJudge) {
+				uc.logger.Warn("skipping verification, no judge available", "address", p.Address())
+				writeErr = result.Error
+			} else if result.Success {
 				p.MarkSuccess(result.Latency, result.Anonymity)
-				if err := uc.writer.Save(ctx, p); err != nil {
+				if result.ExitIP != "" {
+					p.SetExitIP(result.ExitIP)
+				}
+				if uc.prober != nil {
+					p.SetCapabilities(uc.prober.Probe(spanCtx, p))
+				}
+				if uc.geo != nil {
+					uc.locate(p, result.ExitIP)
+				}
+				if err := uc.writer.Save(spanCtx, p); errors.Is(err, ErrProxyBlocked) {
+					uc.logger.Debug("proxy is blocked, not saving", "address", p.Address())
+				} else if err != nil {
 					uc.logger.Warn("failed to save proxy", "address", p.Address(), "error", err)
+					writeErr = err
 				} else {
 					alive.Add(1)
 					uc.logger.Debug("proxy verified", "address", p.Address(), "latency", result.Latency)
+					uc.recordVerified(spanCtx, p, result.Latency)
 				}
+			} else if errors.Is(result.Error, ErrInjectionDetected) {
+				uc.logger.Warn("proxy intercepts tls, blocking", "address", p.Address(), "error", result.Error)
+				if err := uc.writer.RecordMITM(spanCtx, p); err != nil {
+					uc.logger.Warn("failed to record mitm proxy", "address", p.Address(), "error", err)
+					writeErr = err
+				}
+			} else if err := uc.writer.RecordFailure(spanCtx, p); err != nil {
+				uc.logger.Warn("failed to record proxy failure", "address", p.Address(), "error", err)
+				writeErr = err
 			}
 
-			if err := uc.consumer.Ack(ctx, uc.topic, uc.group, m.ID); err != nil {
-				uc.logger.Warn("failed to ack message", "msgID", m.ID, "error", err)
+			if writeErr != nil {
+				uc.fail(ctx, m, writeErr)
+			} else {
+				uc.ack(ctx, m)
 			}
 
 			if current%100 == 0 {
@@ -146,3 +263,93 @@ func (uc *VerifyFromQueueUseCase) Execute(ctx context.Context) error {
 	uc.logger.Info("verification stopped", "processed", processed.Load(), "alive", alive.Load())
 	return nil
 }
+
+func (uc *VerifyFromQueueUseCase) ack(ctx context.Context, m Message) {
+	if err := uc.consumer.Ack(ctx, uc.topic, uc.group, m.ID); err != nil {
+		uc.logger.Warn("failed to ack message", "msgID", m.ID, "error", err)
+	}
+}
+
+func (uc *VerifyFromQueueUseCase) fail(ctx context.Context, m Message, cause error) {
+	if uc.dlq == nil {
+		uc.ack(ctx, m)
+		return
+	}
+
+	if m.Deliveries < uc.maxAttempts {
+		uc.logger.Debug("leaving message for redelivery", "msgID", m.ID, "deliveries", m.Deliveries, "error", cause)
+		uc.nack(ctx, m)
+		return
+	}
+
+	uc.deadLetter(ctx, m, cause)
+}
+
+func (uc *VerifyFromQueueUseCase) nack(ctx context.Context, m Message) {
+	if err := uc.consumer.Nack(ctx, uc.topic, uc.group, m.ID); err != nil {
+		uc.logger.Warn("failed to nack message", "msgID", m.ID, "error", err)
+	}
+}
+
+// deadLetter moves m to the dead letter queue right away, which is also where
+// payloads that no redelivery can decode go. A message that cannot be moved
+// is handed back to be tried again.
+func (uc *VerifyFromQueueUseCase) deadLetter(ctx context.Context, m Message, cause error) {
+	if uc.dlq == nil {
+		uc.ack(ctx, m)
+		return
+	}
+
+	if err := uc.dlq.DeadLetter(ctx, uc.topic, uc.group, m, cause.Error()); err != nil {
+		uc.logger.Warn("failed to dead-letter message", "msgID", m.ID, "error", err)
+		uc.nack(ctx, m)
+		return
+	}
+	uc.logger.Warn("moved message to dead letter queue", "msgID", m.ID, "deliveries", m.Deliveries, "reason", cause)
+}
+
+func (uc *VerifyFromQueueUseCase) startSpan(ctx context.Context, m Message, p VerifiedProxy) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.destination.name", uc.topic),
+			attribute.String("messaging.message.id", m.ID),
+			attribute.String("proxy.address", p.Address()),
+		),
+	}
+
+	producer := otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(m.Headers))
+	if link := trace.LinkFromContext(producer); link.SpanContext.IsValid() {
+		opts = append(opts, trace.WithLinks(link))
+	}
+
+	return otel.Tracer(tracerName).Start(ctx, "verifier.process", opts...)
+}
+
+func (uc *VerifyFromQueueUseCase) locate(p VerifiedProxy, exitIP string) {
+	ip := exitIP
+	if ip == "" {
+		host, _, err := net.SplitHostPort(p.Address())
+		if err != nil {
+			return
+		}
+		ip = host
+	}
+
+	loc, err := uc.geo.Locate(ip)
+	if err != nil {
+		uc.logger.Debug("geoip lookup failed", "address", p.Address(), "ip", ip, "error", err)
+		return
+	}
+	p.SetLocation(loc)
+}
+
+func (uc *VerifyFromQueueUseCase) recordVerified(ctx context.Context, p VerifiedProxy, latency time.Duration) {
+	if uc.stats == nil || p.Recheck() || p.Source() == "" {
+		return
+	}
+
+	if err := uc.stats.RecordVerified(ctx, p.Source(), latency); err != nil {
+		uc.logger.Warn("failed to record source stats", "source", p.Source(), "error", err)
+	}
+}
@@ -3,12 +3,20 @@ package verifier_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
 	"github.com/JulianoL13/app-proxy-engine/internal/verifier/mocks"
@@ -75,7 +83,290 @@ func TestVerifyFromQueueUseCase_Execute(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("acks but does not save failed proxy", func(t *testing.T) {
+	t.Run("records source stats for verified proxy", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().Source().Return("source-a")
+		proxyMock.EXPECT().Recheck().Return(false)
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{
+				Success:   true,
+				Latency:   100 * time.Millisecond,
+				Anonymity: "elite",
+			})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			Save(mock.Anything, proxyMock).
+			Return(nil)
+
+		stats := mocks.NewStatsRecorder(t)
+		stats.EXPECT().
+			RecordVerified(mock.Anything, "source-a", 100*time.Millisecond).
+			Return(errors.New("redis down"))
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithStats(stats)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips source stats for rechecked proxy", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().Recheck().Return(true)
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{
+				Success:   true,
+				Latency:   100 * time.Millisecond,
+				Anonymity: "elite",
+			})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			Save(mock.Anything, proxyMock).
+			Return(nil)
+
+		stats := mocks.NewStatsRecorder(t)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithStats(stats)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("probes capabilities for verified proxy", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		caps := verifier.Capabilities{Connect: true}
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+		proxyMock.EXPECT().SetCapabilities(caps).Return()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Success: true, Latency: 100 * time.Millisecond, Anonymity: "elite"})
+
+		prober := mocks.NewCapabilityProber(t)
+		prober.EXPECT().
+			Probe(mock.Anything, proxyMock).
+			Return(caps)
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			Save(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithCapabilities(prober)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("locates verified proxy by its exit ip", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		loc := verifier.Location{Country: "BR", City: "Sao Paulo", ASN: 28573}
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+		proxyMock.EXPECT().SetExitIP("177.54.10.20").Return()
+		proxyMock.EXPECT().SetLocation(loc).Return()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Success: true, Latency: 100 * time.Millisecond, Anonymity: "elite", ExitIP: "177.54.10.20"})
+
+		geo := mocks.NewGeoLocator(t)
+		geo.EXPECT().
+			Locate("177.54.10.20").
+			Return(loc, nil)
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			Save(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithGeoIP(geo)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("falls back to the proxy ip without an exit ip", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		loc := verifier.Location{Country: "BR", City: "Sao Paulo", ASN: 28573}
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+		proxyMock.EXPECT().SetLocation(loc).Return()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Success: true, Latency: 100 * time.Millisecond, Anonymity: "elite"})
+
+		geo := mocks.NewGeoLocator(t)
+		geo.EXPECT().
+			Locate("1.1.1.1").
+			Return(loc, nil)
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			Save(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithGeoIP(geo)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("acks and records failure for failed proxy", func(t *testing.T) {
 		messages := make(chan verifier.Message, 1)
 		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
 		close(messages)
@@ -103,6 +394,194 @@ func TestVerifyFromQueueUseCase_Execute(t *testing.T) {
 			Return(verifier.VerifyOutput{Success: false})
 
 		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			RecordFailure(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group")
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("observes verification outcome", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Latency: 10 * time.Second, Error: verifier.ErrProxyTimeout})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			RecordFailure(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		metrics := mocks.NewMetrics(t)
+		metrics.EXPECT().ObserveVerification(verifier.OutcomeProxyTimeout, 10*time.Second).Return()
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group").
+			WithMetrics(metrics)
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("blocks proxy when tls interception is detected", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Latency: 50 * time.Millisecond, Error: verifier.ErrInjectionDetected})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			RecordMITM(mock.Anything, proxyMock).
+			Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group")
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("does not record failure when no judge is available", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Error: verifier.ErrNoJudge})
+
+		writer := mocks.NewWriter(t)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, logger, pool, "test-worker", "test-topic", "test-group")
+
+		err := uc.Execute(context.Background())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("handles record failure error gracefully", func(t *testing.T) {
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`)}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().
+			Deserialize([]byte(`{}`)).
+			Return(proxyMock, nil)
+
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			Return(verifier.VerifyOutput{Success: false})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().
+			RecordFailure(mock.Anything, proxyMock).
+			Return(errors.New("redis down"))
 
 		pool := mocks.NewWorkerPool(t)
 		pool.EXPECT().
@@ -222,3 +701,220 @@ func TestVerifyFromQueueUseCase_Execute(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name string
+		out  verifier.VerifyOutput
+		want string
+	}{
+		{"success", verifier.VerifyOutput{Success: true}, verifier.OutcomeSuccess},
+		{"dead", verifier.VerifyOutput{Error: fmt.Errorf("proxy x: %w", verifier.ErrProxyDead)}, verifier.OutcomeProxyDead},
+		{"timeout", verifier.VerifyOutput{Error: verifier.ErrProxyTimeout}, verifier.OutcomeProxyTimeout},
+		{"payload modified", verifier.VerifyOutput{Error: verifier.ErrPayloadModified}, verifier.OutcomePayloadModified},
+		{"injection", verifier.VerifyOutput{Error: verifier.ErrInjectionDetected}, verifier.OutcomeInjection},
+		{"no judge", verifier.VerifyOutput{Error: verifier.ErrNoJudge}, verifier.OutcomeNoJudge},
+		{"unclassified", verifier.VerifyOutput{Error: errors.New("boom")}, verifier.OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, verifier.Outcome(tt.out))
+		})
+	}
+}
+
+func TestVerifyFromQueueUseCase_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	t.Run("links processing span to producer trace", func(t *testing.T) {
+		producerCtx, producer := provider.Tracer("test").Start(context.Background(), "queue.publish")
+		producer.End()
+
+		headers := make(map[string]string)
+		otel.GetTextMapPropagator().Inject(producerCtx, propagation.MapCarrier(headers))
+
+		messages := make(chan verifier.Message, 1)
+		messages <- verifier.Message{ID: "msg-1", Payload: []byte(`{}`), Headers: headers}
+		close(messages)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+		consumer.EXPECT().
+			Ack(mock.Anything, "test-topic", "test-group", "msg-1").
+			Return(nil)
+
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`{}`)).Return(proxyMock, nil)
+
+		var checkSpan trace.SpanContext
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().
+			Verify(mock.Anything, proxyMock).
+			RunAndReturn(func(ctx context.Context, _ verifier.Verifiable) verifier.VerifyOutput {
+				checkSpan = trace.SpanContextFromContext(ctx)
+				return verifier.VerifyOutput{Error: verifier.ErrProxyDead}
+			})
+
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().RecordFailure(mock.Anything, proxyMock).Return(nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, verifierTestLogger{}, pool, "test-worker", "test-topic", "test-group")
+
+		err := uc.Execute(context.Background())
+		assert.NoError(t, err)
+
+		var processed sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == "verifier.process" {
+				processed = span
+			}
+		}
+		require.NotNil(t, processed)
+		require.Len(t, processed.Links(), 1)
+		assert.Equal(t, producer.SpanContext().TraceID(), processed.Links()[0].SpanContext.TraceID())
+		assert.Equal(t, processed.SpanContext().SpanID(), checkSpan.SpanID())
+	})
+}
+
+func TestVerifyFromQueueUseCase_Retry(t *testing.T) {
+	saveErr := errors.New("redis: connection refused")
+
+	runOnce := func(t *testing.T, msg verifier.Message, consumer *mocks.Consumer, deserializer *mocks.ProxyDeserializer, checker *mocks.ProxyChecker, writer *mocks.Writer, dlq *mocks.DeadLetterQueue) {
+		t.Helper()
+
+		messages := make(chan verifier.Message, 1)
+		messages <- msg
+		close(messages)
+
+		consumer.EXPECT().
+			Subscribe(mock.Anything, "test-topic", "test-group", "test-worker").
+			Return((<-chan verifier.Message)(messages), nil)
+
+		pool := mocks.NewWorkerPool(t)
+		pool.EXPECT().
+			Submit(mock.Anything, mock.AnythingOfType("func(context.Context)")).
+			RunAndReturn(func(ctx context.Context, job func(context.Context)) error {
+				job(ctx)
+				return nil
+			})
+
+		uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, deserializer, writer, verifierTestLogger{}, pool, "test-worker", "test-topic", "test-group").
+			WithRetry(3, dlq)
+
+		require.NoError(t, uc.Execute(context.Background()))
+	}
+
+	newVerified := func(t *testing.T) *mocks.VerifiedProxy {
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+		proxyMock.EXPECT().MarkSuccess(100*time.Millisecond, "elite").Return()
+		return proxyMock
+	}
+
+	passing := verifier.VerifyOutput{Success: true, Latency: 100 * time.Millisecond, Anonymity: "elite"}
+
+	t.Run("nacks failed save before the last attempt", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`{}`), Deliveries: 2}
+		proxyMock := newVerified(t)
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`{}`)).Return(proxyMock, nil)
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().Verify(mock.Anything, proxyMock).Return(passing)
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().Save(mock.Anything, proxyMock).Return(saveErr)
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().Nack(mock.Anything, "test-topic", "test-group", "msg-1").Return(nil)
+
+		runOnce(t, msg, consumer, deserializer, checker, writer, mocks.NewDeadLetterQueue(t))
+	})
+
+	t.Run("dead-letters failed save on the last attempt", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`{}`), Deliveries: 3}
+		proxyMock := newVerified(t)
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`{}`)).Return(proxyMock, nil)
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().Verify(mock.Anything, proxyMock).Return(passing)
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().Save(mock.Anything, proxyMock).Return(saveErr)
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().DeadLetter(mock.Anything, "test-topic", "test-group", msg, saveErr.Error()).Return(nil)
+
+		runOnce(t, msg, mocks.NewConsumer(t), deserializer, checker, writer, dlq)
+	})
+
+	t.Run("dead-letters undecodable payload on the first attempt", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`invalid`), Deliveries: 1}
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`invalid`)).Return(nil, errors.New("invalid json"))
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().DeadLetter(mock.Anything, "test-topic", "test-group", msg, "invalid json").Return(nil)
+
+		runOnce(t, msg, mocks.NewConsumer(t), deserializer, mocks.NewProxyChecker(t), mocks.NewWriter(t), dlq)
+	})
+
+	t.Run("nacks message the dead letter queue refused", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`invalid`), Deliveries: 1}
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`invalid`)).Return(nil, errors.New("invalid json"))
+		dlq := mocks.NewDeadLetterQueue(t)
+		dlq.EXPECT().DeadLetter(mock.Anything, "test-topic", "test-group", msg, "invalid json").Return(errors.New("redis down"))
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().Nack(mock.Anything, "test-topic", "test-group", "msg-1").Return(nil)
+
+		runOnce(t, msg, consumer, deserializer, mocks.NewProxyChecker(t), mocks.NewWriter(t), dlq)
+	})
+
+	t.Run("nacks message when no judge is available", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`{}`), Deliveries: 1}
+		proxyMock := mocks.NewVerifiedProxy(t)
+		proxyMock.EXPECT().Address().Return("1.1.1.1:8080").Maybe()
+
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`{}`)).Return(proxyMock, nil)
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().Verify(mock.Anything, proxyMock).Return(verifier.VerifyOutput{Error: verifier.ErrNoJudge})
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().Nack(mock.Anything, "test-topic", "test-group", "msg-1").Return(nil)
+
+		runOnce(t, msg, consumer, deserializer, checker, mocks.NewWriter(t), mocks.NewDeadLetterQueue(t))
+	})
+
+	t.Run("acks blocked proxy without retrying", func(t *testing.T) {
+		msg := verifier.Message{ID: "msg-1", Payload: []byte(`{}`), Deliveries: 1}
+		proxyMock := newVerified(t)
+
+		consumer := mocks.NewConsumer(t)
+		consumer.EXPECT().Ack(mock.Anything, "test-topic", "test-group", "msg-1").Return(nil)
+		deserializer := mocks.NewProxyDeserializer(t)
+		deserializer.EXPECT().Deserialize([]byte(`{}`)).Return(proxyMock, nil)
+		checker := mocks.NewProxyChecker(t)
+		checker.EXPECT().Verify(mock.Anything, proxyMock).Return(passing)
+		writer := mocks.NewWriter(t)
+		writer.EXPECT().Save(mock.Anything, proxyMock).Return(fmt.Errorf("%w: intercepted", verifier.ErrProxyBlocked))
+
+		runOnce(t, msg, consumer, deserializer, checker, writer, mocks.NewDeadLetterQueue(t))
+	})
+}
@@ -0,0 +1,58 @@
+package wiring
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/auth"
+	authredis "github.com/JulianoL13/app-proxy-engine/internal/auth/redis"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	proxyhttp "github.com/JulianoL13/app-proxy-engine/internal/proxy/http"
+)
+
+type APIConfig struct {
+	Topic       string
+	SessionTTL  time.Duration
+	KeyPrefix   string
+	AuthEnabled bool
+	AdminKey    string
+}
+
+// NewAPI wires the HTTP API over store. The dead letter endpoints read q,
+// the broker the workers dead-letter into; keys holds the API keys. Admin
+// endpoints are only served when authentication is enabled.
+func NewAPI(ctx context.Context, cfg APIConfig, store APIStore, q queue.Queue, keys *redis.Client, registry *metrics.Registry, logger slog.Logger) (http.Handler, error) {
+	handler := proxyhttp.NewHandler(
+		adapters.NewGetProxies(proxy.NewGetProxiesUseCase(store, logger)),
+		adapters.NewGetRandomProxy(proxy.NewGetRandomProxyUseCase(store, logger).WithSessions(store, cfg.SessionTTL)),
+		proxy.NewListSourcesUseCase(store, logger),
+		proxy.NewForceSourceUseCase(store, logger),
+		adapters.NewLogger(logger),
+	)
+	handler.WithMetrics(metrics.NewHTTPMetrics(registry), registry.Handler())
+
+	if cfg.AuthEnabled {
+		keyStore := authredis.NewKeyStore(keys, cfg.KeyPrefix)
+		quota := authredis.NewQuotaCounter(keys, cfg.KeyPrefix)
+		manageKeysUC := auth.NewManageKeysUseCase(keyStore, logger)
+
+		if err := manageKeysUC.Bootstrap(ctx, cfg.AdminKey); err != nil {
+			return nil, fmt.Errorf("register admin key: %w", err)
+		}
+
+		handler.WithAuth(auth.NewAuthenticateUseCase(keyStore, quota)).WithKeys(manageKeysUC)
+		handler.WithDeadLetters(adapters.NewDeadLetters(q, cfg.Topic))
+	} else {
+		logger.Warn("api authentication is disabled, admin endpoints are not served")
+	}
+
+	return proxyhttp.NewRouter(handler, adapters.NewLogger(logger)), nil
+}
@@ -0,0 +1,4 @@
+// Package wiring assembles the scheduler, worker and API components from
+// their configuration, so the standalone binaries and the single-process
+// engine build them the same way.
+package wiring
@@ -0,0 +1,78 @@
+package wiring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	sourcefile "github.com/JulianoL13/app-proxy-engine/internal/scraper/file"
+	httpclient "github.com/JulianoL13/app-proxy-engine/internal/scraper/http"
+)
+
+type SchedulerConfig struct {
+	Topic            string
+	ScrapeInterval   time.Duration
+	SourceTimeout    time.Duration
+	SourcesFile      string
+	SourcesReload    time.Duration
+	RecheckInterval  time.Duration
+	RecheckMaxAge    time.Duration
+	RecheckBatchSize int
+	Quarantine       scraper.QuarantinePolicy
+}
+
+// NewScheduler wires the scrape and recheck schedulers. A nil cipher leaves
+// proxies with credentials out of the queue. The sources file, if any, is
+// watched until ctx ends.
+func NewScheduler(ctx context.Context, cfg SchedulerConfig, publisher scraper.Publisher, store SchedulerStore, cleaner scraper.Cleaner, cipher storage.Cipher, registry *metrics.Registry, logger slog.Logger) (func(context.Context) error, error) {
+	scraperMetrics := metrics.NewScraperMetrics(registry)
+
+	scrapeUC := scraper.NewScrapeProxiesUseCase(httpclient.New(logger), scraper.PublicSources(), logger, cfg.SourceTimeout).
+		WithMetrics(scraperMetrics)
+
+	if cfg.SourcesFile != "" {
+		watcher, err := sourcefile.NewWatcher(cfg.SourcesFile, cfg.SourcesReload, logger)
+		if err != nil {
+			return nil, fmt.Errorf("load sources file %s: %w", cfg.SourcesFile, err)
+		}
+		logger.Info("loaded sources file", "path", cfg.SourcesFile, "sources", len(watcher.Sources()))
+
+		scrapeUC.WithSourceProvider(watcher)
+		go watcher.Watch(ctx)
+	}
+
+	if cipher == nil {
+		logger.Warn("CREDENTIALS_KEY is not set, proxies with credentials will not be queued")
+	}
+
+	uc := scraper.NewScheduleScrapingUseCase(adapters.NewScraper(scrapeUC), adapters.ProxySerializer{Cipher: cipher}, publisher, cleaner, cfg.ScrapeInterval, logger, cfg.Topic).
+		WithStats(store).
+		WithMetrics(scraperMetrics).
+		WithQuarantine(adapters.NewSourceHealthStore(store), cfg.Quarantine)
+
+	recheckUC := scraper.NewScheduleRecheckUseCase(
+		adapters.NewStaleReader(store),
+		adapters.ProxySerializer{Cipher: cipher, Recheck: true},
+		publisher,
+		cfg.RecheckInterval,
+		cfg.RecheckMaxAge,
+		cfg.RecheckBatchSize,
+		logger,
+		cfg.Topic,
+	).WithMetrics(scraperMetrics)
+
+	return func(ctx context.Context) error {
+		go func() {
+			if err := recheckUC.Execute(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("recheck scheduler error", "error", err)
+			}
+		}()
+		return uc.Execute(ctx)
+	}, nil
+}
@@ -0,0 +1,29 @@
+package wiring
+
+import (
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy"
+	"github.com/JulianoL13/app-proxy-engine/internal/scraper"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+)
+
+// SchedulerStore is what the scheduler needs from proxy storage.
+type SchedulerStore interface {
+	scraper.SourceStatsRecorder
+	adapters.StaleProxies
+	adapters.SourceHealthRepository
+}
+
+// WorkerStore is what the worker needs from proxy storage.
+type WorkerStore interface {
+	verifier.StatsRecorder
+	adapters.ProxyWriter
+}
+
+// APIStore is what the API needs from proxy storage.
+type APIStore interface {
+	proxy.Reader
+	proxy.SessionStore
+	proxy.SourceStatsReader
+	proxy.SourceOverrideWriter
+}
@@ -0,0 +1,186 @@
+package wiring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JulianoL13/app-proxy-engine/internal/adapters"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/logs/slog"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/metrics"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/queue"
+	"github.com/JulianoL13/app-proxy-engine/internal/common/workerpool"
+	"github.com/JulianoL13/app-proxy-engine/internal/judge"
+	"github.com/JulianoL13/app-proxy-engine/internal/proxy/storage"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier"
+	httpverifier "github.com/JulianoL13/app-proxy-engine/internal/verifier/http"
+	"github.com/JulianoL13/app-proxy-engine/internal/verifier/mmdb"
+)
+
+type WorkerConfig struct {
+	ConsumerName string
+	Topic        string
+	Group        string
+	Concurrency  int
+	MaxAttempts  int
+	GeoIPDB      string
+
+	VerifyTimeout       time.Duration
+	JudgeURLs           string
+	JudgeKey            string
+	JudgeStrategy       string
+	JudgeQuorum         int
+	JudgeHealthInterval time.Duration
+	JudgeTLSPort        string
+	CapabilityProbe     bool
+	CapabilityPlainURL  string
+	CapabilityTLSTarget string
+	MITMTarget          string
+	MITMPins            string
+}
+
+// NewWorker wires the verifier consuming q. A nil cipher refuses queued
+// proxies with credentials. The returned func releases the worker pool and
+// the geoip database.
+func NewWorker(ctx context.Context, cfg WorkerConfig, q queue.Queue, store WorkerStore, cipher storage.Cipher, registry *metrics.Registry, logger slog.Logger) (*verifier.VerifyFromQueueUseCase, func(), error) {
+	checker, err := NewChecker(cfg, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool, err := workerpool.New(cfg.Concurrency)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create worker pool: %w", err)
+	}
+	closers := []func(){pool.Stop}
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+	metrics.RegisterPool(registry, pool)
+
+	consumer := adapters.NewConsumer(q)
+	uc := verifier.NewVerifyFromQueueUseCase(consumer, checker, adapters.ProxyDeserializer{Cipher: cipher}, adapters.NewWriter(store), logger, pool, cfg.ConsumerName, cfg.Topic, cfg.Group).
+		WithStats(store).
+		WithMetrics(metrics.NewVerifierMetrics(registry))
+
+	if cfg.CapabilityProbe {
+		probe, err := NewCapabilityProbe(ctx, cfg, logger)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		uc.WithCapabilities(probe)
+	}
+
+	if cfg.MaxAttempts > 0 {
+		uc.WithRetry(cfg.MaxAttempts, consumer)
+	}
+
+	if cfg.GeoIPDB != "" {
+		locator, err := mmdb.Open(strings.Split(cfg.GeoIPDB, ",")...)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("open geoip database: %w", err)
+		}
+		closers = append(closers, func() { _ = locator.Close() })
+		uc.WithGeoIP(locator)
+	}
+
+	return uc, closeAll, nil
+}
+
+// NewChecker builds the judge checker: one per JUDGE_URL, combined by the
+// configured strategy when there are several, behind the MITM pin check
+// when pins are set.
+func NewChecker(cfg WorkerConfig, logger slog.Logger) (verifier.ProxyChecker, error) {
+	var signer *judge.Signer
+	if cfg.JudgeKey != "" {
+		var err error
+		signer, err = judge.NewSignerFromBase64(cfg.JudgeKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid judge key: %w", err)
+		}
+	}
+
+	var judges []*httpverifier.Checker
+	for _, u := range strings.Split(cfg.JudgeURLs, ",") {
+		judgeChecker := httpverifier.NewChecker(strings.TrimSpace(u), cfg.VerifyTimeout, logger)
+		if signer != nil {
+			judgeChecker.WithJudge(signer)
+		}
+		judges = append(judges, judgeChecker)
+	}
+
+	var checker verifier.ProxyChecker = judges[0]
+	if len(judges) > 1 {
+		strategy, err := httpverifier.ParseStrategy(cfg.JudgeStrategy)
+		if err != nil {
+			return nil, err
+		}
+
+		multi, err := httpverifier.NewMultiChecker(judges, strategy, cfg.JudgeQuorum, logger)
+		if err != nil {
+			return nil, err
+		}
+		checker = multi.WithHealthInterval(cfg.JudgeHealthInterval)
+		logger.Info("using multiple judges", "judges", len(judges), "strategy", strategy)
+	}
+
+	if cfg.MITMPins != "" {
+		pins, err := judge.ParsePins(cfg.MITMPins)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.MITMTarget == "" {
+			return nil, errors.New("MITM_TARGET is required when MITM_PINS is set")
+		}
+		checker = httpverifier.NewPinChecker(checker, cfg.MITMTarget, pins, cfg.VerifyTimeout, logger)
+		logger.Info("mitm detection enabled", "target", cfg.MITMTarget, "pins", len(pins))
+	}
+
+	return checker, nil
+}
+
+// NewCapabilityProbe points the CONNECT probe at the judge's TLS listener
+// unless a target is configured explicitly, trusting it by its pins when set.
+// The judge only listens for TLS when given a certificate, so a derived
+// target that does not answer falls back to DefaultTLSTarget; otherwise every
+// proxy would be recorded without CONNECT support.
+func NewCapabilityProbe(ctx context.Context, cfg WorkerConfig, logger slog.Logger) (*httpverifier.CapabilityProbe, error) {
+	if cfg.CapabilityTLSTarget != "" {
+		probe := httpverifier.NewCapabilityProbe(cfg.CapabilityPlainURL, cfg.CapabilityTLSTarget, cfg.VerifyTimeout, logger)
+		if err := probe.CheckTarget(ctx); err != nil {
+			logger.Warn("CAPABILITY_TLS_TARGET does not complete a tls handshake, proxies will not be marked as CONNECT capable", "error", err)
+		}
+		return probe, nil
+	}
+
+	target := cfg.MITMTarget
+	derived := target == ""
+	if derived {
+		target = httpverifier.JudgeTLSTarget(strings.Split(cfg.JudgeURLs, ",")[0], cfg.JudgeTLSPort)
+	}
+	probe := httpverifier.NewCapabilityProbe(cfg.CapabilityPlainURL, target, cfg.VerifyTimeout, logger)
+	if cfg.MITMPins != "" {
+		pins, err := judge.ParsePins(cfg.MITMPins)
+		if err != nil {
+			return nil, err
+		}
+		probe.WithPins(pins)
+	}
+
+	if err := probe.CheckTarget(ctx); err != nil {
+		if !derived || target == "" {
+			logger.Warn("capability tls target does not complete a tls handshake, proxies will not be marked as CONNECT capable", "error", err)
+			return probe, nil
+		}
+		logger.Warn("judge tls listener is not reachable, probing CONNECT against the default target; set JUDGE_TLS_CERT/JUDGE_TLS_KEY on the judge or CAPABILITY_TLS_TARGET",
+			"target", target, "fallback", httpverifier.DefaultTLSTarget, "error", err)
+		return httpverifier.NewCapabilityProbe(cfg.CapabilityPlainURL, httpverifier.DefaultTLSTarget, cfg.VerifyTimeout, logger), nil
+	}
+	return probe, nil
+}